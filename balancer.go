@@ -28,12 +28,43 @@ type Balancer struct {
 //function which transform DataItem into SFC-readable format,
 //optimizer function which distributes cells into groups
 //and list of nodes in space(could be nil).
+//
+//If the code of Hilbert or Morton curve(dims * log2(size)) does not fit 64 bits,
+//cells of the space are aligned hypercubes of the curve addressed by the leading 63 bits of the wide code(see curve.Truncated).
 func NewBalancer(cType curve.CurveType, dims, size uint64, tf TransformFunc, of OptimizerFunc, nodes []node.Node) (*Balancer, error) {
 	bits, err := log2(size)
 	if err != nil {
 		return nil, err
 	}
-	sfc, err := curve.NewCurve(cType, dims, bits)
+	sfc, err := newCurve(cType, dims, bits)
+	if err != nil {
+		return nil, err
+	}
+	s, err := NewSpace(sfc, tf, nodes)
+	if err != nil {
+		return nil, err
+	}
+	return &Balancer{
+		space: s,
+		of:    of,
+	}, nil
+}
+
+//NewUnevenBalancer creates a new instance of balancer
+//using curve type, size of each dimension(the amount of dimensions is the length of the slice),
+//function which transform DataItem into SFC-readable format,
+//optimizer function which distributes cells into groups
+//and list of nodes in space(could be nil).
+func NewUnevenBalancer(cType curve.CurveType, sizes []uint64, tf TransformFunc, of OptimizerFunc, nodes []node.Node) (*Balancer, error) {
+	bits := make([]uint64, len(sizes))
+	for i, size := range sizes {
+		b, err := log2(size)
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = b
+	}
+	sfc, err := curve.NewUnevenCurve(cType, bits)
 	if err != nil {
 		return nil, err
 	}
@@ -109,6 +140,12 @@ func (b *Balancer) AddData(cID uint64, d DataItem) error {
 	return b.space.AddData(cID, d)
 }
 
+//AddDataBatch locates data items and loads them into the Space of the balancer at once.
+//It returns IDs of cells where items were added, in the same order as items.
+func (b *Balancer) AddDataBatch(ds []DataItem) ([]uint64, error) {
+	return b.space.AddDataBatch(ds)
+}
+
 //RemoveData removes DataItem from the Space of the balancer
 func (b *Balancer) RemoveData(d DataItem) error {
 	return b.space.RemoveData(d)
@@ -119,6 +156,21 @@ func (b *Balancer) RelocateData(d DataItem, ncID uint64) (node.Node, uint64, err
 	return b.space.RelocateData(d, ncID)
 }
 
+//BoxNodes returns nodes which cell groups contain at least one cell of the box,
+//so the box query has to be sent to each of them.
+//Box is given by the minimum and maximum(inclusive) coordinates in each dimension.
+func (b *Balancer) BoxNodes(min, max []uint64) ([]node.Node, error) {
+	cgs, err := b.space.BoxCellGroups(min, max)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]node.Node, len(cgs))
+	for i := range cgs {
+		res[i] = cgs[i].Node()
+	}
+	return res, nil
+}
+
 //Optimize updates cell groups by optimizer.
 func (b *Balancer) Optimize() error {
 	cgs, err := b.of(b.space)
@@ -129,6 +181,21 @@ func (b *Balancer) Optimize() error {
 	return nil
 }
 
+//newCurve creates a space-filling curve for the balancer.
+//If codes of Hilbert or Morton curve do not fit 64 bits, the curve with wide codes is used
+//and cells are addressed by the leading bits of its codes(see curve.Truncated), so the balancer runs at the truncated resolution.
+//Other curve types, including registered ones, are created as is.
+func newCurve(cType curve.CurveType, dims, bits uint64) (curve.Curve, error) {
+	if dims*bits <= 64 || (cType != curve.Hilbert && cType != curve.Morton) {
+		return curve.NewCurve(cType, dims, bits)
+	}
+	wc, err := curve.NewWideCurve(cType, dims, bits)
+	if err != nil {
+		return nil, err
+	}
+	return curve.Truncate(wc)
+}
+
 func log2(n uint64) (p uint64, err error) {
 	if (n & (n - 1)) != 0 {
 		return 0, errors.New("number must be a power of 2")
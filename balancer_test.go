@@ -2,7 +2,9 @@ package balancer
 
 import (
 	"errors"
+	"fmt"
 	"math"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/mock"
@@ -177,6 +179,83 @@ func TestNewBalancer(t *testing.T) {
 	}
 }
 
+func TestNewBalancer_WideCurve(t *testing.T) {
+	n := &mocks.Node{}
+	n.On("ID").Return("test-node")
+	tf := func(values []interface{}, sfc curve.Curve) ([]uint64, error) {
+		return []uint64{1<<20 - 1, 1 << 19, 0, 1 << 19}, nil
+	}
+
+	b, err := NewBalancer(curve.Hilbert, 4, 1<<20, tf, nil, []node.Node{n})
+	assert.NoError(t, err)
+
+	tc, ok := b.SFC().(*curve.Truncated)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, uint64(1<<20-1), tc.DimensionSize())
+	assert.Equal(t, uint64(1<<60-1), b.Space().Capacity())
+
+	d := &mocks.DataItem{}
+	d.On("Values").Return([]interface{}{})
+	d.On("ID").Return("test-di")
+
+	wcID, err := tc.Encode([]uint64{1<<20 - 1, 1 << 19, 0, 1 << 19})
+	assert.NoError(t, err)
+
+	got, cID, err := b.LocateData(d)
+	assert.NoError(t, err)
+	assert.Equal(t, n, got)
+	assert.Equal(t, wcID, cID)
+
+	nodes, err := b.BoxNodes([]uint64{0, 0, 0, 0}, []uint64{1<<19 - 1, 1<<19 - 1, 1<<19 - 1, 1<<19 - 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []node.Node{n}, nodes)
+}
+
+func TestNewBalancer_RegisteredCurve(t *testing.T) {
+	ct := curve.Register("balancer-test-curve", func(dims, bits uint64) (curve.Curve, error) {
+		return morton.New(dims, bits)
+	})
+	b, err := NewBalancer(ct, 2, 16, nil, nil, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.IsType(t, &morton.Curve{}, b.SFC())
+	assert.Equal(t, uint64(255), b.SFC().Length())
+}
+
+func TestNewUnevenBalancer(t *testing.T) {
+	n := &mocks.Node{}
+	n.On("ID").Return("test-node")
+	tf := func(values []interface{}, sfc curve.Curve) ([]uint64, error) {
+		return []uint64{1<<20 - 2, 1}, nil
+	}
+
+	b, err := NewUnevenBalancer(curve.Hilbert, []uint64{1 << 20, 1 << 6}, tf, nil, []node.Node{n})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []uint64{1<<20 - 1, 1<<6 - 1}, curve.DimensionSizes(b.SFC()))
+	assert.Equal(t, uint64(1<<26-1), b.Space().Capacity())
+
+	d := &mocks.DataItem{}
+	d.On("Values").Return([]interface{}{})
+	d.On("ID").Return("test-di")
+
+	want, err := b.SFC().Encode([]uint64{1<<20 - 2, 1})
+	assert.NoError(t, err)
+	got, cID, err := b.LocateData(d)
+	assert.NoError(t, err)
+	assert.Equal(t, n, got)
+	assert.Equal(t, want, cID)
+
+	_, err = NewUnevenBalancer(curve.Hilbert, []uint64{1 << 20, 42}, tf, nil, nil)
+	assert.Error(t, err)
+	_, err = NewUnevenBalancer(curve.Peano, []uint64{1 << 20, 1 << 6}, tf, nil, nil)
+	assert.Error(t, err)
+}
+
 func TestBalancer_Space(t *testing.T) {
 	type fields struct {
 		space *Space
@@ -696,7 +775,7 @@ func TestBalancer_LocateData(t *testing.T) {
 					cgs: []*CellGroup{
 						{
 							id:     "test-node",
-							cRange: Range{0, math.MaxUint64, math.MaxUint64},
+							cRange: Range{Min: 0, Max: math.MaxUint64, Len: math.MaxUint64},
 							cells:  make(map[uint64]*cell),
 						},
 					},
@@ -766,7 +845,7 @@ func TestBalancer_AddData(t *testing.T) {
 					cgs: []*CellGroup{
 						{
 							id:     "test-node",
-							cRange: Range{0, math.MaxUint64, math.MaxUint64},
+							cRange: Range{Min: 0, Max: math.MaxUint64, Len: math.MaxUint64},
 							cells:  make(map[uint64]*cell),
 						},
 					},
@@ -813,6 +892,37 @@ func TestBalancer_AddData(t *testing.T) {
 	}
 }
 
+func TestBalancer_AddDataBatch(t *testing.T) {
+	n := &mocks.Node{}
+	n.On("ID").Return("test-node")
+	tf := func(values []interface{}, sfc curve.Curve) ([]uint64, error) {
+		return []uint64{values[0].(uint64), values[1].(uint64)}, nil
+	}
+	b, err := NewBalancer(curve.Hilbert, 2, 16, tf, nil, []node.Node{n})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ds := make([]DataItem, 500)
+	for i := range ds {
+		d := &mocks.DataItem{}
+		d.On("ID").Return(fmt.Sprintf("test-di-%d", i))
+		d.On("Size").Return(uint64(1))
+		d.On("Values").Return([]interface{}{uint64(i % 15), uint64(i / 15 % 15)})
+		ds[i] = d
+	}
+	got, err := b.AddDataBatch(ds)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for i, d := range ds {
+		_, cID, err := b.LocateData(d)
+		assert.NoError(t, err)
+		assert.Equal(t, cID, got[i])
+	}
+	assert.Equal(t, uint64(len(ds)), b.Space().TotalLoad())
+}
+
 func TestBalancer_RemoveData(t *testing.T) {
 	type fields struct {
 		space *Space
@@ -836,7 +946,7 @@ func TestBalancer_RemoveData(t *testing.T) {
 					cgs: []*CellGroup{
 						{
 							id:     "test-node",
-							cRange: Range{0, math.MaxUint64, math.MaxUint64},
+							cRange: Range{Min: 0, Max: math.MaxUint64, Len: math.MaxUint64},
 							cells:  make(map[uint64]*cell),
 						},
 					},
@@ -905,7 +1015,7 @@ func TestBalancer_RelocateData(t *testing.T) {
 					cgs: []*CellGroup{
 						{
 							id:     "test-node",
-							cRange: Range{0, math.MaxUint64, math.MaxUint64},
+							cRange: Range{Min: 0, Max: math.MaxUint64, Len: math.MaxUint64},
 							cells:  make(map[uint64]*cell),
 						},
 					},
@@ -1015,3 +1125,46 @@ func TestBalancer_Optimize(t *testing.T) {
 		})
 	}
 }
+
+//BenchmarkBalancer_BoxFanOut reports the average amount of nodes which receive a box query.
+func BenchmarkBalancer_BoxFanOut(b *testing.B) {
+	const nodes = 16
+	ns := make([]node.Node, nodes)
+	for i := range ns {
+		n := &mocks.Node{}
+		n.On("ID").Return(fmt.Sprintf("node-%d", i))
+		ns[i] = n
+	}
+	for _, dims := range []uint64{2, 3} {
+		for _, cType := range []curve.CurveType{curve.Hilbert, curve.Morton, curve.Peano, curve.Moore, curve.Gray, curve.Onion} {
+			if cType == curve.Moore && dims != 2 {
+				continue
+			}
+			bal, err := NewBalancer(cType, dims, 64, nil, nil, ns)
+			if err != nil {
+				b.Fatal(err)
+			}
+			size := bal.SFC().DimensionSize()
+			side := (size + 1) / 8
+			b.Run(fmt.Sprintf("%s/%dD", cType, dims), func(b *testing.B) {
+				rnd := rand.New(rand.NewSource(42))
+				min := make([]uint64, dims)
+				max := make([]uint64, dims)
+				var total int
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					for d := range min {
+						min[d] = uint64(rnd.Int63n(int64(size - side + 2)))
+						max[d] = min[d] + side - 1
+					}
+					res, err := bal.BoxNodes(min, max)
+					if err != nil {
+						b.Fatal(err)
+					}
+					total += len(res)
+				}
+				b.ReportMetric(float64(total)/float64(b.N), "nodes/query")
+			})
+		}
+	}
+}
@@ -71,6 +71,20 @@ func (cg *CellGroup) SetRange(min, max uint64) error {
 	return nil
 }
 
+//SetRingRange sets the minimum and maximum of the cell group range on the curve which is treated as a ring.
+//If min is greater than max, the range wraps from the end of the curve back to zero.
+//
+//length - the maximum code of the curve(Space.Capacity).
+func (cg *CellGroup) SetRingRange(min, max, length uint64) error {
+	cg.mu.Lock()
+	defer cg.mu.Unlock()
+	if min > max && min > length {
+		return errors.Errorf("min(%d) of wrapping range should be less or equall then length(%d)", min, length)
+	}
+	cg.cRange = NewRingRange(min, max, length)
+	return nil
+}
+
 //FitsRange checks if the index of the cell fits the range of the cell group.
 func (cg *CellGroup) FitsRange(index uint64) bool {
 	cg.mu.RLock()
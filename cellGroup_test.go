@@ -331,6 +331,71 @@ func TestCellGroup_SetRange(t *testing.T) {
 	}
 }
 
+func TestCellGroup_SetRingRange(t *testing.T) {
+	type args struct {
+		min    uint64
+		max    uint64
+		length uint64
+	}
+	type want struct {
+		err    bool
+		cRange Range
+		cells  []uint64
+	}
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "linear",
+			args: args{min: 10, max: 121, length: 255},
+			want: want{
+				cRange: Range{Min: 10, Max: 121, Len: 111},
+				cells:  []uint64{10, 15, 111, 115},
+			},
+		},
+		{
+			name: "wraps",
+			args: args{min: 121, max: 10, length: 255},
+			want: want{
+				cRange: Range{Min: 121, Max: 10, Len: 145},
+				cells:  []uint64{1, 2, 121, 122, 255},
+			},
+		},
+		{
+			name: "min exceeds length",
+			args: args{min: 256, max: 10, length: 255},
+			want: want{err: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Space{cells: map[uint64]*cell{}}
+			for _, id := range []uint64{1, 2, 10, 15, 111, 115, 121, 122, 255} {
+				s.cells[id] = &cell{id: id, load: uint64ptr(1)}
+			}
+			cg := &CellGroup{cells: map[uint64]*cell{}}
+
+			err := cg.SetRingRange(tt.args.min, tt.args.max, tt.args.length)
+			if tt.want.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.cRange, cg.Range())
+
+			s.FillCellGroup(cg)
+			var cells []uint64
+			for id := range cg.Cells() {
+				cells = append(cells, id)
+			}
+			assert.ElementsMatch(t, tt.want.cells, cells)
+			assert.Equal(t, uint64(len(tt.want.cells)), cg.TotalLoad())
+		})
+	}
+}
+
 func TestCellGroup_Cells(t *testing.T) {
 	type fields struct {
 		cells map[uint64]*cell
@@ -0,0 +1,372 @@
+/*
+	Measurements of locality of space-filling curves.
+
+	Analyze builds a report which helps to choose the curve for the data:
+	how many ranges of codes a box query touches(clustering number),
+	how far along the curve adjacent cells are placed,
+	and how many faces of cells are cut when the curve is split into partitions.
+*/
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand"
+
+	"github.com/struckoff/sfcframework/curve"
+)
+
+//Options of the analysis, zero values are replaced by defaults.
+type Options struct {
+	Queries    int    //amount of random box queries, 1000 by default
+	QuerySide  uint64 //maximum side of the query box in cells, by default the box has at most 2^16 cells
+	Partitions int    //amount of equal ranges the curve is split into, 16 by default
+	Samples    int    //curves with more cells are measured on this amount of random cells, 2^16 by default
+	Seed       int64  //seed of the random generator
+}
+
+func (o Options) withDefaults(c curve.Curve) Options {
+	if o.Queries <= 0 {
+		o.Queries = 1000
+	}
+	if o.QuerySide == 0 {
+		o.QuerySide = uint64(math.Pow(2, math.Floor(16/float64(c.Dimensions()))))
+		if o.QuerySide == 0 {
+			o.QuerySide = 1
+		}
+	}
+	if o.Partitions <= 0 {
+		o.Partitions = 16
+	}
+	if o.Samples <= 0 {
+		o.Samples = 1 << 16
+	}
+	return o
+}
+
+//Report - results of the analysis of the curve.
+type Report struct {
+	Curve      string       `json:"curve"`      //name of the curve
+	Dimensions uint64       `json:"dimensions"` //amount of curve dimensions
+	Bits       uint64       `json:"bits"`       //size in bits of each dimension
+	Exact      bool         `json:"exact"`      //all cells are measured, otherwise measurements are estimated by random cells
+	Clustering Clustering   `json:"clustering"`
+	Adjacency  Distribution `json:"adjacency"`  //distance along the curve between cells which share a face
+	Continuity Distribution `json:"continuity"` //euclidean distance between cells with consecutive codes
+	Partitions []Partition  `json:"partitions"`
+	Boundary   Summary      `json:"boundary"` //boundary of partitions
+}
+
+//Clustering - amount of contiguous ranges of codes which cover a random box.
+type Clustering struct {
+	Queries    int     `json:"queries"`     //amount of box queries
+	MeanVolume float64 `json:"mean_volume"` //average amount of cells in the box
+	Mean       float64 `json:"mean"`        //average amount of ranges per box
+	Max        int     `json:"max"`         //the biggest amount of ranges per box
+}
+
+//Distribution - distribution of distances.
+type Distribution struct {
+	Count     uint64   `json:"count"` //amount of measured distances
+	Mean      float64  `json:"mean"`
+	Max       float64  `json:"max"`
+	Histogram []Bucket `json:"histogram"` //buckets of powers of 2
+}
+
+//Bucket - amount of distances in range [2^(i-1), 2^i), where i is the index of the bucket.
+//The first bucket counts distances below 1.
+type Bucket struct {
+	Max   float64 `json:"max"` //exclusive upper bound of distances in the bucket
+	Count uint64  `json:"count"`
+}
+
+//Partition - range of codes when the curve is split into equal ranges.
+type Partition struct {
+	Range    curve.Range `json:"range"`
+	Boundary float64     `json:"boundary"` //amount of faces of partition cells shared with cells of other partitions
+}
+
+//Summary - average and the worst value.
+type Summary struct {
+	Mean float64 `json:"mean"`
+	Max  float64 `json:"max"`
+}
+
+//AnalyzeType creates the curve by given type and analyzes it.
+func AnalyzeType(cType curve.CurveType, dims, bits uint64, opts Options) (Report, error) {
+	c, err := curve.NewCurve(cType, dims, bits)
+	if err != nil {
+		return Report{}, err
+	}
+	r, err := Analyze(c, opts)
+	r.Curve = cType.String()
+	return r, err
+}
+
+//Analyze measures locality of the curve.
+//
+//Curves with at most Options.Samples cells are measured exhaustively,
+//otherwise adjacency, continuity and boundaries are estimated by random cells.
+func Analyze(c curve.Curve, opts Options) (Report, error) {
+	if c.Dimensions() == 0 {
+		return Report{}, errors.New("number of dimensions must be greater than 0")
+	}
+	opts = opts.withDefaults(c)
+	r := Report{
+		Curve:      fmt.Sprintf("%T", c),
+		Dimensions: c.Dimensions(),
+		Bits:       c.Bits(),
+		Exact:      c.Length() < uint64(opts.Samples),
+	}
+	rnd := rand.New(rand.NewSource(opts.Seed))
+
+	var err error
+	if r.Clustering, err = clustering(c, rnd, opts); err != nil {
+		return Report{}, err
+	}
+
+	m := newMeter(c, opts.Partitions)
+	if r.Exact {
+		err = m.all()
+	} else {
+		err = m.sample(rnd, opts.Samples)
+	}
+	if err != nil {
+		return Report{}, err
+	}
+	r.Adjacency = m.adjacency.distribution()
+	r.Continuity = m.continuity.distribution()
+	r.Partitions = m.partitions()
+	for _, p := range r.Partitions {
+		r.Boundary.Mean += p.Boundary
+		r.Boundary.Max = math.Max(r.Boundary.Max, p.Boundary)
+	}
+	r.Boundary.Mean /= float64(len(r.Partitions))
+	return r, nil
+}
+
+//clustering runs random box queries and counts ranges of codes which cover each box.
+func clustering(c curve.Curve, rnd *rand.Rand, opts Options) (Clustering, error) {
+	sizes := curve.DimensionSizes(c)
+	min := make([]uint64, len(sizes))
+	max := make([]uint64, len(sizes))
+	res := Clustering{Queries: opts.Queries}
+	for q := 0; q < opts.Queries; q++ {
+		volume := 1.0
+		for i, size := range sizes {
+			side := opts.QuerySide
+			if size < side {
+				side = size + 1
+			}
+			side = 1 + uint64(rnd.Int63n(int64(side)))
+			min[i] = randomUint64(rnd, size-side+1)
+			max[i] = min[i] + side - 1
+			volume *= float64(side)
+		}
+		rs, err := curve.BoxRanges(c, min, max, 0)
+		if err != nil {
+			return Clustering{}, err
+		}
+		res.MeanVolume += volume
+		res.Mean += float64(len(rs))
+		if len(rs) > res.Max {
+			res.Max = len(rs)
+		}
+	}
+	res.MeanVolume /= float64(opts.Queries)
+	res.Mean /= float64(opts.Queries)
+	return res, nil
+}
+
+//meter collects distances and boundaries cell by cell.
+type meter struct {
+	c          curve.Curve
+	n          uint64    //amount of partitions
+	boundary   []float64 //[partition] -> amount of faces shared with other partitions
+	weight     float64   //amount of cells represented by each measured cell
+	adjacency  histogram
+	continuity histogram
+	buf        []uint64
+}
+
+func newMeter(c curve.Curve, partitions int) *meter {
+	n := uint64(partitions)
+	if n > c.Length() {
+		n = c.Length() + 1
+	}
+	return &meter{
+		c:        c,
+		n:        n,
+		boundary: make([]float64, n),
+		weight:   1,
+		buf:      make([]uint64, c.Dimensions()),
+	}
+}
+
+//all measures every cell of the curve.
+func (m *meter) all() error {
+	var prev []uint64
+	var err error
+	werr := curve.Walk(m.c, curve.NewRange(0, m.c.Length()+1), func(code uint64, coords []uint64) bool {
+		if prev != nil {
+			m.continuity.add(euclidean(prev, coords))
+		} else {
+			prev = make([]uint64, len(coords))
+		}
+		copy(prev, coords)
+		err = m.cell(code)
+		return err == nil
+	})
+	if werr != nil {
+		return werr
+	}
+	return err
+}
+
+//sample measures random cells of the curve, boundaries are scaled to the whole curve.
+func (m *meter) sample(rnd *rand.Rand, samples int) error {
+	cells := float64(m.c.Length()) + 1
+	m.weight = cells / float64(samples)
+	next := make([]uint64, m.c.Dimensions())
+	for i := 0; i < samples; i++ {
+		code := randomUint64(rnd, m.c.Length())
+		if code == m.c.Length() {
+			code--
+		}
+		if err := m.cell(code); err != nil {
+			return err
+		}
+		coords, err := m.c.DecodeWithBuffer(m.buf, code)
+		if err != nil {
+			return err
+		}
+		copy(next, coords)
+		coords, err = m.c.DecodeWithBuffer(m.buf, code+1)
+		if err != nil {
+			return err
+		}
+		m.continuity.add(euclidean(next, coords))
+	}
+	return nil
+}
+
+//cell measures distances to face neighbors of the cell and its boundary.
+func (m *meter) cell(code uint64) error {
+	ns, err := curve.Neighbors(m.c, code, curve.Clamp, false)
+	if err != nil {
+		return err
+	}
+	p := m.partition(code)
+	for _, n := range ns {
+		if n > code {
+			m.adjacency.add(float64(n - code))
+		} else {
+			m.adjacency.add(float64(code - n))
+		}
+		if m.partition(n) != p {
+			m.boundary[p] += m.weight
+		}
+	}
+	return nil
+}
+
+func (m *meter) partitions() []Partition {
+	res := make([]Partition, len(m.boundary))
+	for i := range res {
+		min := m.bound(uint64(i))
+		max := m.c.Length() + 1
+		if i < len(res)-1 {
+			max = m.bound(uint64(i) + 1)
+		} else if max == 0 {
+			max = math.MaxUint64
+		}
+		res[i] = Partition{
+			Range:    curve.NewRange(min, max),
+			Boundary: m.boundary[i],
+		}
+	}
+	return res
+}
+
+//bound returns the first code of the partition, i * (Length + 1) / n.
+//Products are 128-bit, so partitions differ by at most one code and there are exactly n of them.
+func (m *meter) bound(i uint64) uint64 {
+	cells := m.c.Length() + 1
+	hi, lo := bits.Mul64(i, cells)
+	if cells == 0 {
+		//the curve has 2^64 cells
+		hi, lo = i, 0
+	}
+	res, _ := bits.Div64(hi, lo, m.n)
+	return res
+}
+
+//partition returns the partition of the code, ((code + 1) * n - 1) / (Length + 1).
+func (m *meter) partition(code uint64) uint64 {
+	hi, lo := bits.Mul64(code, m.n)
+	var carry uint64
+	lo, carry = bits.Add64(lo, m.n-1, 0)
+	hi += carry
+	cells := m.c.Length() + 1
+	if cells == 0 {
+		return hi
+	}
+	res, _ := bits.Div64(hi, lo, cells)
+	return res
+}
+
+//histogram - distribution of distances by buckets of powers of 2.
+type histogram struct {
+	count   uint64
+	sum     float64
+	max     float64
+	buckets []uint64
+}
+
+func (h *histogram) add(d float64) {
+	h.count++
+	h.sum += d
+	h.max = math.Max(h.max, d)
+	b := 0
+	if d >= 1 {
+		b = bits.Len64(uint64(d))
+	}
+	for len(h.buckets) <= b {
+		h.buckets = append(h.buckets, 0)
+	}
+	h.buckets[b]++
+}
+
+func (h *histogram) distribution() Distribution {
+	d := Distribution{
+		Count:     h.count,
+		Max:       h.max,
+		Histogram: make([]Bucket, len(h.buckets)),
+	}
+	if h.count > 0 {
+		d.Mean = h.sum / float64(h.count)
+	}
+	for i, n := range h.buckets {
+		d.Histogram[i] = Bucket{Max: math.Ldexp(1, i), Count: n}
+	}
+	return d
+}
+
+func euclidean(a, b []uint64) float64 {
+	var sum float64
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+//randomUint64 returns a random value in range [0, max].
+func randomUint64(rnd *rand.Rand, max uint64) uint64 {
+	if max == math.MaxUint64 {
+		return rnd.Uint64()
+	}
+	return rnd.Uint64() % (max + 1)
+}
@@ -0,0 +1,166 @@
+package analysis
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/struckoff/sfcframework/curve"
+)
+
+func TestAnalyzeType_Exact(t *testing.T) {
+	tests := []struct {
+		name              string
+		cType             curve.CurveType
+		wantContinuityMax float64
+	}{
+		{"hilbert", curve.Hilbert, 1},
+		{"moore", curve.Moore, 1},
+		{"morton", curve.Morton, math.Sqrt(15*15 + 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := AnalyzeType(tt.cType, 2, 4, Options{Queries: 100, Partitions: 4})
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tt.cType.String(), r.Curve)
+			assert.Equal(t, uint64(2), r.Dimensions)
+			assert.Equal(t, uint64(4), r.Bits)
+			assert.True(t, r.Exact)
+
+			//each of 2 * 16 * 15 faces is measured from both cells
+			assert.Equal(t, uint64(960), r.Adjacency.Count)
+			assert.Equal(t, uint64(255), r.Continuity.Count)
+			assert.Equal(t, tt.wantContinuityMax, r.Continuity.Max)
+			assert.Equal(t, r.Adjacency.Count, sumBuckets(r.Adjacency.Histogram))
+			assert.Equal(t, r.Continuity.Count, sumBuckets(r.Continuity.Histogram))
+
+			//the curve is split into quadrants 8x8, each shares 16 faces with others
+			if assert.Len(t, r.Partitions, 4) {
+				for i, p := range r.Partitions {
+					assert.Equal(t, curve.NewRange(uint64(i)*64, uint64(i+1)*64), p.Range)
+					assert.Equal(t, 16.0, p.Boundary)
+				}
+			}
+			assert.Equal(t, Summary{Mean: 16, Max: 16}, r.Boundary)
+
+			assert.Equal(t, 100, r.Clustering.Queries)
+			assert.True(t, r.Clustering.Mean >= 1)
+			assert.True(t, float64(r.Clustering.Max) >= r.Clustering.Mean)
+		})
+	}
+}
+
+func TestAnalyze_UnevenPartitions(t *testing.T) {
+	//81 cells of Peano curve are not divisible into 16 partitions
+	r, err := AnalyzeType(curve.Peano, 2, 3, Options{Queries: 10, Partitions: 16})
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.Len(t, r.Partitions, 16) {
+		return
+	}
+	assert.Equal(t, uint64(0), r.Partitions[0].Range.Min)
+	assert.Equal(t, uint64(81), r.Partitions[15].Range.Max)
+	for i, p := range r.Partitions {
+		assert.Equal(t, uint64(i)*81/16, p.Range.Min)
+		assert.True(t, p.Range.Len == 5 || p.Range.Len == 6, "partition %v: %v", i, p.Range)
+	}
+}
+
+func Test_meter_partition(t *testing.T) {
+	for _, shape := range []struct {
+		cType      curve.CurveType
+		dims, bits uint64
+	}{{curve.Peano, 2, 3}, {curve.Hilbert, 2, 32}} {
+		c, err := curve.NewCurve(shape.cType, shape.dims, shape.bits)
+		if !assert.NoError(t, err) {
+			return
+		}
+		for _, n := range []int{1, 7, 16} {
+			m := newMeter(c, n)
+			for _, code := range []uint64{0, 1, 5, 6, 40, 80, c.Length() / 3, c.Length() - 1, c.Length()} {
+				if code > c.Length() {
+					continue
+				}
+				p := m.partition(code)
+				if !assert.True(t, p < uint64(n), "code %v, partition %v", code, p) {
+					return
+				}
+				assert.True(t, m.bound(p) <= code, "code %v, partition %v", code, p)
+				if p+1 < uint64(n) {
+					assert.True(t, code < m.bound(p+1), "code %v, partition %v", code, p)
+				}
+			}
+		}
+	}
+}
+
+func TestAnalyze_SingleCellQueries(t *testing.T) {
+	c, err := curve.NewCurve(curve.Peano, 3, 2)
+	if !assert.NoError(t, err) {
+		return
+	}
+	r, err := Analyze(c, Options{Queries: 50, QuerySide: 1})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, Clustering{Queries: 50, MeanVolume: 1, Mean: 1, Max: 1}, r.Clustering)
+	assert.Equal(t, "*peano.Curve", r.Curve)
+}
+
+func TestAnalyze_HilbertClustersBetterThanMorton(t *testing.T) {
+	opts := Options{Queries: 500, QuerySide: 16, Seed: 7}
+	h, err := AnalyzeType(curve.Hilbert, 2, 8, opts)
+	assert.NoError(t, err)
+	m, err := AnalyzeType(curve.Morton, 2, 8, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, h.Clustering.MeanVolume, m.Clustering.MeanVolume)
+	assert.Less(t, h.Clustering.Mean, m.Clustering.Mean)
+}
+
+func TestAnalyze_Sampled(t *testing.T) {
+	r, err := AnalyzeType(curve.Hilbert, 2, 16, Options{Queries: 10, Samples: 1000, Partitions: 8})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.False(t, r.Exact)
+	assert.Equal(t, uint64(1000), r.Continuity.Count)
+	assert.Equal(t, 1.0, r.Continuity.Max)
+	assert.True(t, r.Adjacency.Count >= 2000)
+	if assert.Len(t, r.Partitions, 8) {
+		assert.Equal(t, uint64(0), r.Partitions[0].Range.Min)
+		assert.Equal(t, uint64(1<<32), r.Partitions[7].Range.Max)
+	}
+
+	data, err := json.Marshal(r)
+	assert.NoError(t, err)
+	var decoded Report
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, r, decoded)
+}
+
+func TestAnalyze_Wide(t *testing.T) {
+	r, err := AnalyzeType(curve.Hilbert, 2, 32, Options{Queries: 10, Samples: 100})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.False(t, r.Exact)
+	if assert.Len(t, r.Partitions, 16) {
+		assert.Equal(t, uint64(1<<64-1), r.Partitions[15].Range.Max)
+	}
+}
+
+func TestAnalyzeType_Error(t *testing.T) {
+	_, err := AnalyzeType(curve.Moore, 3, 2, Options{})
+	assert.Error(t, err)
+}
+
+func sumBuckets(bs []Bucket) (n uint64) {
+	for _, b := range bs {
+		n += b.Count
+	}
+	return n
+}
@@ -0,0 +1,49 @@
+package curve
+
+import "fmt"
+
+//BatchCurve is implemented by curves which encode and decode many cells at once without allocations per cell.
+type BatchCurve interface {
+	Curve
+	EncodeBatch(coords [][]uint64, out []uint64) error //EncodeBatch writes codes of each set of coordinates into out
+	DecodeBatch(codes []uint64, out [][]uint64) error  //DecodeBatch writes coordinates of each code into buffers of out
+}
+
+//EncodeBatch writes codes(distances) of each set of coordinates into out, coords are not altered.
+//Curves which do not implement BatchCurve encode coordinates one by one.
+func EncodeBatch(c Curve, coords [][]uint64, out []uint64) error {
+	if bc, ok := c.(BatchCurve); ok {
+		return bc.EncodeBatch(coords, out)
+	}
+	if len(out) < len(coords) {
+		return fmt.Errorf("output length == %v less then number of coordinates == %v", len(out), len(coords))
+	}
+	var buf []uint64
+	for i := range coords {
+		//Encode may alter coordinates
+		buf = append(buf[:0], coords[i]...)
+		code, err := c.Encode(buf)
+		if err != nil {
+			return fmt.Errorf("coordinates %d: %w", i, err)
+		}
+		out[i] = code
+	}
+	return nil
+}
+
+//DecodeBatch writes coordinates for each code(distance) into buffers of out.
+//Curves which do not implement BatchCurve decode codes one by one.
+func DecodeBatch(c Curve, codes []uint64, out [][]uint64) error {
+	if bc, ok := c.(BatchCurve); ok {
+		return bc.DecodeBatch(codes, out)
+	}
+	if len(out) < len(codes) {
+		return fmt.Errorf("output length == %v less then number of codes == %v", len(out), len(codes))
+	}
+	for i := range codes {
+		if _, err := c.DecodeWithBuffer(out[i], codes[i]); err != nil {
+			return fmt.Errorf("code %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package curve
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeBatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		cType CurveType
+		dims  uint64
+		bits  uint64
+		n     int
+	}{
+		{"hilbert 2x16", Hilbert, 2, 16, 100},
+		{"hilbert 4x8 parallel", Hilbert, 4, 8, 10000},
+		{"hilbert 3x20 parallel", Hilbert, 3, 20, 10000},
+		{"morton 3x10 parallel", Morton, 3, 10, 10000},
+		{"peano 2x8", Peano, 2, 8, 100},
+		{"gray 3x10 parallel", Gray, 3, 10, 10000},
+		{"onion 3x4", Onion, 3, 4, 100},
+	}
+	rnd := rand.New(rand.NewSource(42))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCurve(tt.cType, tt.dims, tt.bits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			coords := make([][]uint64, tt.n)
+			for i := range coords {
+				coords[i] = make([]uint64, tt.dims)
+				for d := range coords[i] {
+					coords[i][d] = uint64(rnd.Int63n(int64(c.DimensionSize()) + 1))
+				}
+			}
+			orig := make([][]uint64, tt.n)
+			for i := range coords {
+				orig[i] = append([]uint64{}, coords[i]...)
+			}
+
+			codes := make([]uint64, tt.n)
+			if !assert.NoError(t, EncodeBatch(c, coords, codes)) {
+				return
+			}
+			assert.Equal(t, orig, coords, "coordinates should not be altered")
+			for i := range coords {
+				want, err := c.Encode(append([]uint64{}, coords[i]...))
+				assert.NoError(t, err)
+				if !assert.Equal(t, want, codes[i]) {
+					return
+				}
+			}
+
+			out := make([][]uint64, tt.n)
+			for i := range out {
+				out[i] = make([]uint64, tt.dims)
+			}
+			if !assert.NoError(t, DecodeBatch(c, codes, out)) {
+				return
+			}
+			assert.Equal(t, orig, out)
+		})
+	}
+}
+
+func TestEncodeBatch_Errors(t *testing.T) {
+	for _, cType := range []CurveType{Hilbert, Morton, Peano, Gray, Onion} {
+		c, err := NewCurve(cType, 2, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Error(t, EncodeBatch(c, [][]uint64{{1, 1}, {1, 1}}, make([]uint64, 1)))
+		assert.Error(t, EncodeBatch(c, [][]uint64{{1, 1}, {1, c.DimensionSize() + 1}}, make([]uint64, 2)))
+		assert.Error(t, DecodeBatch(c, []uint64{1, 2}, [][]uint64{{0, 0}}))
+		assert.Error(t, DecodeBatch(c, []uint64{1, 2}, [][]uint64{{0, 0}, {0}}))
+		assert.Error(t, DecodeBatch(c, []uint64{1, c.Length() + 1}, [][]uint64{{0, 0}, {0, 0}}))
+	}
+}
@@ -0,0 +1,346 @@
+package curve
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+//maxEnumeratedCells - the maximum amount of cells which could be encoded one by one
+//to decompose a box for curves which do not implement BlockCurve.
+const maxEnumeratedCells = 1 << 20
+
+//BlockCurve is implemented by curves which visit every aligned block of cells as a contiguous range of codes.
+//Block is a hypercube with the side of BlockRadix()^k cells
+//which origin coordinates are multiples of the side.
+type BlockCurve interface {
+	Curve
+	BlockRadix() uint64 // BlockRadix - base of the side of aligned blocks
+}
+
+//BoxCurve is implemented by curves which decompose a box into ranges of codes by themselves.
+type BoxCurve interface {
+	Curve
+	//BoxRanges returns ranges of codes which cover the valid box, ranges may be unsorted and overlap.
+	//If limit is greater than 0, the result may contain more than limit ranges, they are reduced by the caller.
+	BoxRanges(min, max []uint64, limit int) ([]Range, error)
+}
+
+//BoxRanges returns the sorted set of code ranges [Min, Max) which cover the box.
+//Box is given by the minimum and maximum(inclusive) coordinates in each dimension.
+//
+//If limit is greater than 0, the result contains at most limit ranges,
+//in this case ranges may also cover cells outside of the box.
+//Otherwise, the result is the minimal set of ranges which covers exactly the box.
+//
+//Curves which implement BoxCurve decompose the box by themselves,
+//curves which implement FacedCurve are decomposed face by face,
+//curves which implement BlockCurve are decomposed block by block,
+//other curves are decomposed by encoding each cell of the box.
+func BoxRanges(c Curve, min, max []uint64, limit int) ([]Range, error) {
+	if err := validateBox(c, min, max); err != nil {
+		return nil, err
+	}
+	var res []Range
+	var err error
+	if bx, ok := c.(BoxCurve); ok {
+		res, err = bx.BoxRanges(min, max, limit)
+	} else if fc, ok := c.(FacedCurve); ok {
+		res, err = faceRanges(fc, min, max, limit)
+	} else if bc, ok := c.(BlockCurve); ok && bc.BlockRadix() > 1 {
+		res, err = blockRanges(bc, min, max, limit)
+	} else {
+		res, err = cellRanges(c, min, max)
+	}
+	if err != nil {
+		return nil, err
+	}
+	res = mergeRanges(res)
+	if limit > 0 && len(res) > limit {
+		res = reduceRanges(res, limit)
+	}
+	return res, nil
+}
+
+func validateBox(c Curve, min, max []uint64) error {
+	dims := int(c.Dimensions())
+	if len(min) < dims || len(max) < dims {
+		return fmt.Errorf("number of coordinates == %v, %v less then dimensions == %v", len(min), len(max), dims)
+	}
+	sizes := DimensionSizes(c)
+	for i := 0; i < dims; i++ {
+		if min[i] > max[i] {
+			return fmt.Errorf("minimum coordinate == %v exceeds maximum == %v", min[i], max[i])
+		}
+		if max[i] > sizes[i] {
+			return fmt.Errorf("coordinate == %v exceeds limit == %v", max[i], sizes[i])
+		}
+	}
+	return nil
+}
+
+//block - aligned hypercube of cells.
+type block struct {
+	origin []uint64
+	level  uint64 //side of the block is radix^level
+}
+
+//blockRanges splits the curve into aligned blocks level by level.
+//Blocks which are inside the box become ranges, blocks which partially overlap the box are split further.
+func blockRanges(c BlockCurve, min, max []uint64, limit int) ([]Range, error) {
+	dims := c.Dimensions()
+	radix := c.BlockRadix()
+	sizes := DimensionSizes(c)
+	biggest := uint64(0)
+	for _, s := range sizes {
+		if s > biggest {
+			biggest = s
+		}
+	}
+	levels := uint64(0)
+	for side := uint64(1); side != 0 && side-1 < biggest; side *= radix {
+		levels++
+	}
+
+	var res []Range
+	buf := make([]uint64, dims)
+	queue := []block{{origin: make([]uint64, dims), level: levels}}
+	for len(queue) > 0 {
+		var partial []block
+		for _, b := range queue {
+			switch overlap(b, pow(radix, b.level)-1, sizes, min, max) {
+			case overlapNone:
+			case overlapFull:
+				r, err := blockRange(c, buf, sizes, b)
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, r)
+			default:
+				partial = append(partial, b)
+			}
+		}
+		if limit > 0 && len(res)+len(partial) >= limit {
+			for _, b := range partial {
+				r, err := blockRange(c, buf, sizes, b)
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, r)
+			}
+			break
+		}
+		queue = queue[:0]
+		for _, b := range partial {
+			queue = appendChildren(queue, b, radix, min, max)
+		}
+	}
+	return res, nil
+}
+
+const (
+	overlapNone = iota
+	overlapPartial
+	overlapFull
+)
+
+//overlap checks how the block with given side(last == side - 1) overlaps the box.
+//Blocks are cut by sizes of dimensions, since dimensions of the curve may be shorter than the block.
+func overlap(b block, last uint64, sizes, min, max []uint64) int {
+	res := overlapFull
+	for i := range b.origin {
+		end := b.origin[i] + last
+		if end < b.origin[i] || end > sizes[i] {
+			end = sizes[i]
+		}
+		if end < min[i] || b.origin[i] > max[i] {
+			return overlapNone
+		}
+		if b.origin[i] < min[i] || end > max[i] {
+			res = overlapPartial
+		}
+	}
+	return res
+}
+
+//blockRange returns the range of codes of the block.
+func blockRange(c BlockCurve, buf, sizes []uint64, b block) (Range, error) {
+	copy(buf, b.origin)
+	code, err := c.Encode(buf)
+	if err != nil {
+		return Range{}, err
+	}
+	span := blockSpan(pow(c.BlockRadix(), b.level), sizes)
+	if span == 0 {
+		//the block is the whole curve, which length does not fit uint64
+		return NewRange(0, math.MaxUint64), nil
+	}
+	start := code - code%span
+	end := start + span
+	if end < start {
+		end = math.MaxUint64
+	}
+	return NewRange(start, end), nil
+}
+
+//blockSpan returns the amount of cells in the block with given side, the result is 0 if it overflows uint64.
+func blockSpan(side uint64, sizes []uint64) uint64 {
+	res := uint64(1)
+	for _, size := range sizes {
+		s := side
+		if s == 0 || s-1 > size {
+			s = size + 1
+		}
+		if s == 0 || res > math.MaxUint64/s {
+			return 0
+		}
+		res *= s
+	}
+	return res
+}
+
+//appendChildren appends sub-blocks of the block which overlap the box.
+func appendChildren(blocks []block, b block, radix uint64, min, max []uint64) []block {
+	dims := len(b.origin)
+	side := pow(radix, b.level-1)
+	lo := make([]uint64, dims)
+	hi := make([]uint64, dims)
+	for i := 0; i < dims; i++ {
+		from, to := min[i], max[i]
+		if from < b.origin[i] {
+			from = b.origin[i]
+		}
+		if last := b.origin[i] + side*radix - 1; side*radix != 0 && to > last {
+			to = last
+		}
+		lo[i] = (from - b.origin[i]) / side
+		hi[i] = (to - b.origin[i]) / side
+	}
+	idx := append([]uint64{}, lo...)
+	for {
+		origin := make([]uint64, dims)
+		for i := 0; i < dims; i++ {
+			origin[i] = b.origin[i] + idx[i]*side
+		}
+		blocks = append(blocks, block{origin: origin, level: b.level - 1})
+
+		i := 0
+		for ; i < dims; i++ {
+			if idx[i] < hi[i] {
+				idx[i]++
+				break
+			}
+			idx[i] = lo[i]
+		}
+		if i == dims {
+			return blocks
+		}
+	}
+}
+
+//cellRanges encodes each cell of the box and groups codes into ranges.
+func cellRanges(c Curve, min, max []uint64) ([]Range, error) {
+	dims := int(c.Dimensions())
+	volume := uint64(1)
+	for i := 0; i < dims; i++ {
+		side := max[i] - min[i] + 1
+		if side == 0 || volume > maxEnumeratedCells/side {
+			return nil, fmt.Errorf("box exceeds limit of %d cells", maxEnumeratedCells)
+		}
+		volume *= side
+	}
+
+	codes := make([]uint64, 0, volume)
+	idx := append([]uint64{}, min[:dims]...)
+	buf := make([]uint64, dims)
+	for {
+		copy(buf, idx)
+		code, err := c.Encode(buf)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+
+		i := 0
+		for ; i < dims; i++ {
+			if idx[i] < max[i] {
+				idx[i]++
+				break
+			}
+			idx[i] = min[i]
+		}
+		if i == dims {
+			break
+		}
+	}
+
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	res := make([]Range, 0, len(codes))
+	for _, code := range codes {
+		if len(res) > 0 && res[len(res)-1].Max == code {
+			res[len(res)-1] = NewRange(res[len(res)-1].Min, code+1)
+			continue
+		}
+		res = append(res, NewRange(code, code+1))
+	}
+	return res, nil
+}
+
+//mergeRanges sorts ranges and joins overlapping and adjacent ones.
+func mergeRanges(rs []Range) []Range {
+	if len(rs) == 0 {
+		return rs
+	}
+	sort.Slice(rs, func(i, j int) bool { return rs[i].Min < rs[j].Min })
+	res := rs[:1]
+	for _, r := range rs[1:] {
+		l := &res[len(res)-1]
+		if r.Min <= l.Max {
+			if r.Max > l.Max {
+				*l = NewRange(l.Min, r.Max)
+			}
+			continue
+		}
+		res = append(res, r)
+	}
+	return res
+}
+
+//reduceRanges joins sorted ranges separated by the smallest gaps until only limit ranges left.
+func reduceRanges(rs []Range, limit int) []Range {
+	gaps := make([]int, len(rs)-1)
+	for i := range gaps {
+		gaps[i] = i
+	}
+	sort.SliceStable(gaps, func(i, j int) bool {
+		return rs[gaps[i]+1].Min-rs[gaps[i]].Max < rs[gaps[j]+1].Min-rs[gaps[j]].Max
+	})
+	closed := make([]bool, len(rs)-1)
+	for _, g := range gaps[:len(rs)-limit] {
+		closed[g] = true
+	}
+	res := make([]Range, 0, limit)
+	min := rs[0].Min
+	for i := range rs {
+		if i < len(closed) && closed[i] {
+			continue
+		}
+		res = append(res, NewRange(min, rs[i].Max))
+		if i+1 < len(rs) {
+			min = rs[i+1].Min
+		}
+	}
+	return res
+}
+
+//pow returns base^exp, the result is 0 if it overflows uint64.
+func pow(base, exp uint64) uint64 {
+	res := uint64(1)
+	for i := uint64(0); i < exp; i++ {
+		if res > math.MaxUint64/base {
+			return 0
+		}
+		res *= base
+	}
+	return res
+}
@@ -0,0 +1,242 @@
+package curve
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//plainCurve hides optional methods of the curve.
+type plainCurve struct {
+	Curve
+}
+
+//plainUnevenCurve hides optional methods of the curve except sizes of dimensions.
+type plainUnevenCurve struct {
+	plainCurve
+	sizes []uint64
+}
+
+func (c plainUnevenCurve) DimensionSizes() []uint64 {
+	return c.sizes
+}
+
+//plain hides optional methods of the curve which do not change its cells.
+func plain(c Curve) Curve {
+	if uc, ok := c.(UnevenCurve); ok {
+		return plainUnevenCurve{plainCurve{c}, uc.DimensionSizes()}
+	}
+	return plainCurve{c}
+}
+
+//forEachCell calls fn for coordinates of each cell of the curve.
+func forEachCell(c Curve, fn func(coords []uint64)) {
+	dims := int(c.Dimensions())
+	sizes := DimensionSizes(c)
+	idx := make([]uint64, dims)
+	for {
+		fn(append([]uint64{}, idx...))
+		i := 0
+		for ; i < dims; i++ {
+			if idx[i] < sizes[i] {
+				idx[i]++
+				break
+			}
+			idx[i] = 0
+		}
+		if i == dims {
+			return
+		}
+	}
+}
+
+func randomBox(rnd *rand.Rand, c Curve) (min, max []uint64) {
+	min = make([]uint64, c.Dimensions())
+	max = make([]uint64, c.Dimensions())
+	sizes := DimensionSizes(c)
+	for i := range min {
+		a := uint64(rnd.Int63n(int64(sizes[i]) + 1))
+		b := uint64(rnd.Int63n(int64(sizes[i]) + 1))
+		if a > b {
+			a, b = b, a
+		}
+		min[i], max[i] = a, b
+	}
+	return min, max
+}
+
+func inBox(coords, min, max []uint64) bool {
+	for i := range min {
+		if coords[i] < min[i] || coords[i] > max[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func inRanges(code uint64, rs []Range) bool {
+	for i := range rs {
+		if rs[i].Fits(code) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestBoxRanges_Exact(t *testing.T) {
+	tests := []struct {
+		name  string
+		cType CurveType
+		dims  uint64
+		bits  uint64
+	}{
+		{"hilbert 2x4", Hilbert, 2, 4},
+		{"hilbert 3x3", Hilbert, 3, 3},
+		{"morton 2x4", Morton, 2, 4},
+		{"morton 3x3", Morton, 3, 3},
+		{"peano 2x3", Peano, 2, 3},
+		{"peano 3x1", Peano, 3, 1},
+		{"moore 2x4", Moore, 2, 4},
+		{"gray 2x4", Gray, 2, 4},
+		{"gray 3x3", Gray, 3, 3},
+		{"onion 2x4", Onion, 2, 4},
+		{"onion 3x3", Onion, 3, 3},
+		{"cube 3x2", Cube, 3, 2},
+		{"cube 3x3", Cube, 3, 3},
+	}
+	rnd := rand.New(rand.NewSource(42))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCurve(tt.cType, tt.dims, tt.bits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for n := 0; n < 20; n++ {
+				min, max := randomBox(rnd, c)
+				got, err := BoxRanges(c, min, max, 0)
+				if !assert.NoError(t, err) {
+					return
+				}
+				want, err := BoxRanges(plain(c), min, max, 0)
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.Equal(t, want, got)
+				for i := 1; i < len(got); i++ {
+					assert.True(t, got[i-1].Max < got[i].Min, "ranges should be sorted and separated")
+				}
+				forEachCell(c, func(coords []uint64) {
+					code, err := c.Encode(append([]uint64{}, coords...))
+					assert.NoError(t, err)
+					assert.Equal(t, inBox(coords, min, max), inRanges(code, got), "coords %v, code %v", coords, code)
+				})
+			}
+		})
+	}
+}
+
+func TestBoxRanges_Limit(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	for _, cType := range []CurveType{Hilbert, Morton, Peano, Gray, Onion} {
+		c, err := NewCurve(cType, 2, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for n := 0; n < 20; n++ {
+			min, max := randomBox(rnd, c)
+			exact, err := BoxRanges(c, min, max, 0)
+			assert.NoError(t, err)
+			for _, limit := range []int{1, 2, 3, 8} {
+				got, err := BoxRanges(c, min, max, limit)
+				assert.NoError(t, err)
+				assert.True(t, len(got) <= limit)
+				for _, r := range exact {
+					for code := r.Min; code < r.Max; code++ {
+						assert.True(t, inRanges(code, got), "code %v is not covered", code)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestBoxRanges_Large(t *testing.T) {
+	c, err := NewCurve(Hilbert, 2, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	min := []uint64{1000, 123456789}
+	max := []uint64{1 << 31, 1<<32 - 1}
+	got, err := BoxRanges(c, min, max, 16)
+	assert.NoError(t, err)
+	assert.True(t, len(got) <= 16)
+	for _, coords := range [][]uint64{min, max, {1 << 30, 1 << 31}} {
+		code, err := c.Encode(append([]uint64{}, coords...))
+		assert.NoError(t, err)
+		assert.True(t, inRanges(code, got))
+	}
+
+	got, err = BoxRanges(c, []uint64{0, 0}, []uint64{1<<32 - 1, 1<<32 - 1}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []Range{NewRange(0, 1<<64-1)}, got)
+}
+
+func TestBoxRanges_Faces(t *testing.T) {
+	c := mustCurve(t, Cube, 3, 20)
+	span := uint64(1) << 40
+	min := []uint64{1, 0, 0}
+	max := []uint64{3, 1<<19 - 1, 1<<19 - 1}
+	got, err := BoxRanges(c, min, max, 0)
+	if !assert.NoError(t, err) || !assert.Len(t, got, 3) {
+		return
+	}
+	//the quadrant of each face is an aligned block of the face curve
+	for i, r := range got {
+		assert.Equal(t, uint64(i+1), r.Min/span)
+		assert.Equal(t, span/4, r.Len)
+	}
+	for _, coords := range [][]uint64{min, max, {2, 12345, 1<<19 - 1}} {
+		code, err := c.Encode(append([]uint64{}, coords...))
+		assert.NoError(t, err)
+		assert.True(t, inRanges(code, got), "coords %v", coords)
+	}
+	code, err := c.Encode([]uint64{2, 1 << 19, 0})
+	assert.NoError(t, err)
+	assert.False(t, inRanges(code, got))
+
+	got, err = BoxRanges(c, []uint64{0, 5, 7}, []uint64{5, 1 << 19, 1<<20 - 1}, 4)
+	assert.NoError(t, err)
+	assert.True(t, len(got) <= 4)
+}
+
+func TestBoxRanges_Errors(t *testing.T) {
+	c, err := NewCurve(Hilbert, 2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		c        Curve
+		min, max []uint64
+	}{
+		{"not enough coordinates", c, []uint64{0}, []uint64{1, 1}},
+		{"min exceeds max", c, []uint64{2, 0}, []uint64{1, 1}},
+		{"coordinate exceeds limit", c, []uint64{0, 0}, []uint64{16, 1}},
+		{"too many cells", plainCurve{mustCurve(t, Hilbert, 2, 16)}, []uint64{0, 0}, []uint64{65535, 65535}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BoxRanges(tt.c, tt.min, tt.max, 0)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func mustCurve(t *testing.T, cType CurveType, dims, bits uint64) Curve {
+	c, err := NewCurve(cType, dims, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
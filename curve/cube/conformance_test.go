@@ -0,0 +1,18 @@
+package cube_test
+
+import (
+	"testing"
+
+	"github.com/struckoff/sfcframework/curve/cube"
+	"github.com/struckoff/sfcframework/curve/curvetest"
+)
+
+var factory = curvetest.Factory(cube.New)
+
+func TestCurve_Conformance(t *testing.T) {
+	curvetest.Run(t, factory, curvetest.Options{Shapes: []curvetest.Shape{{Dims: 3, Bits: 1}, {Dims: 3, Bits: 5}, {Dims: 3, Bits: 30}}})
+}
+
+func TestCurve_Fuzz(t *testing.T) {
+	curvetest.Fuzz(t, factory, 3, 30, 42, 10000)
+}
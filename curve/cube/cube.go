@@ -0,0 +1,290 @@
+/*
+	The cube-face curve covers the sphere in the style of S2 geometry.
+
+	The sphere is projected onto six faces of the cube, each face is filled by a Hilbert curve.
+	The point is projected onto the face by the quadratic transform,
+	so cells cover roughly equal surface area(the biggest cell is about twice the smallest one).
+
+	Coordinates of the curve are the face [0, 5] and the position of the cell on the face (i, j).
+	The code consists of the face in the leading bits and the Hilbert index of the cell on the face.
+
+	The curve does not implement curve.BlockCurve: aligned blocks of (face, i, j) with the side above 1 span several faces,
+	which are not contiguous ranges of codes. Instead it implements curve.FacedCurve(Face),
+	so curve.BoxRanges and curve.RangeRegion decompose boxes and ranges face by face by aligned blocks of the face curve.
+*/
+package cube
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/struckoff/sfcframework/curve/hilbert"
+)
+
+//Faces - amount of faces of the cube.
+const Faces = 6
+
+//MaxBits - the biggest size in bits of each face dimension, the code of such curve takes 63 bits.
+const MaxBits = 30
+
+//Curve - the representation of the cube-face curve.
+type Curve struct {
+	bits    uint64 //size in bits of each face dimension
+	maxSize uint64 //maximum value of each face dimension
+	maxCode uint64 //biggest code which could be decoded by curve
+	face    *hilbert.Curve
+}
+
+//New - create new cube-face curve.
+//
+//dims - amount of curve dimensions, must be 3(face, i, j).
+//
+//bits - size in bits of each face dimension, must be in range [1, 30].
+func New(dims, bits uint64) (*Curve, error) {
+	if dims != 3 {
+		return nil, errors.New("number of dimensions must be 3")
+	}
+	if bits == 0 || bits > MaxBits {
+		return nil, fmt.Errorf("number of bits must be in range [1, %d]", MaxBits)
+	}
+	face, err := hilbert.New(2, bits)
+	if err != nil {
+		return nil, err
+	}
+	return &Curve{
+		bits:    bits,
+		maxSize: 1<<bits - 1,
+		maxCode: Faces<<(2*bits) - 1,
+		face:    face,
+	}, nil
+}
+
+//Decode returns coordinates(face, i, j) for a given code(distance)
+//Method will return error if code(distance) exceeds the limit(6 * 2 ^ (2 * bits) - 1)
+func (c *Curve) Decode(code uint64) (coords []uint64, err error) {
+	return c.DecodeWithBuffer(make([]uint64, 3), code)
+}
+
+//DecodeWithBuffer returns coordinates(face, i, j) for a given code(distance).
+//Method will return error if:
+//  - buffer less than number of dimensions
+//	- code(distance) exceeds the limit(6 * 2 ^ (2 * bits) - 1)
+func (c *Curve) DecodeWithBuffer(buf []uint64, code uint64) (coords []uint64, err error) {
+	if len(buf) < 3 {
+		return nil, errors.New("buffer length less then dimensions")
+	}
+	if code > c.maxCode {
+		return nil, fmt.Errorf("code == %v exceeds limit (6 * 2^(2 * bits) - 1) == %v", code, c.maxCode)
+	}
+	ij, err := c.face.DecodeWithBuffer(buf[1:], code&(1<<(2*c.bits)-1))
+	if err != nil {
+		return nil, err
+	}
+	buf[0] = code >> (2 * c.bits)
+	buf[1], buf[2] = ij[0], ij[1]
+	return buf, nil
+}
+
+//Encode returns code(distance) for a given set of coordinates(face, i, j)
+//Method will return error if the face exceeds 5 or i, j exceed limit(2 ^ bits - 1)
+func (c *Curve) Encode(coords []uint64) (code uint64, err error) {
+	if len(coords) < 3 {
+		return 0, fmt.Errorf("number of coordinates == %v less then dimensions == %v", len(coords), 3)
+	}
+	if coords[0] >= Faces {
+		return 0, fmt.Errorf("face == %v exceeds limit == %v", coords[0], Faces-1)
+	}
+	code, err = c.face.Encode(coords[1:3])
+	if err != nil {
+		return 0, err
+	}
+	return coords[0]<<(2*c.bits) | code, nil
+}
+
+// DimensionSize returns the maximum coordinate value which is valid in every dimension
+func (c *Curve) DimensionSize() uint64 {
+	if c.maxSize < Faces-1 {
+		return c.maxSize
+	}
+	return Faces - 1
+}
+
+// DimensionSizes returns the maximum coordinate value of each dimension: the face, i and j
+func (c *Curve) DimensionSizes() []uint64 {
+	return []uint64{Faces - 1, c.maxSize, c.maxSize}
+}
+
+// Length returns the maximum distance along curve(code value)
+//
+// 6 * 2^(2 * bits) - 1
+func (c *Curve) Length() uint64 {
+	return c.maxCode
+}
+
+//Dimensions - amount of curve dimensions(face, i, j)
+func (c *Curve) Dimensions() uint64 {
+	return 3
+}
+
+//Bits - size in bits of each face dimension
+func (c *Curve) Bits() uint64 {
+	return c.bits
+}
+
+//Face returns the Hilbert curve which fills each face, codes of the face f are face codes shifted by f << (2 * bits).
+func (c *Curve) Face() *hilbert.Curve {
+	return c.face
+}
+
+//Coords returns coordinates(face, i, j) of the cell which contains the point.
+//
+//lat, lon - latitude and longitude in degrees.
+//
+//Method will return error if latitude is out of range [-90, 90] or longitude is out of range [-180, 180].
+func (c *Curve) Coords(lat, lon float64) ([]uint64, error) {
+	if !(lat >= -90 && lat <= 90) {
+		return nil, fmt.Errorf("latitude == %v must be in range [-90, 90]", lat)
+	}
+	if !(lon >= -180 && lon <= 180) {
+		return nil, fmt.Errorf("longitude == %v must be in range [-180, 180]", lon)
+	}
+	face, u, v := xyzToFaceUV(latLonToXYZ(lat, lon))
+	return []uint64{face, c.stToIJ(uvToST(u)), c.stToIJ(uvToST(v))}, nil
+}
+
+//Center returns latitude and longitude in degrees of the center of the cell.
+func (c *Curve) Center(code uint64) (lat, lon float64, err error) {
+	coords, err := c.Decode(code)
+	if err != nil {
+		return 0, 0, err
+	}
+	return c.point(coords[0], float64(coords[1])+0.5, float64(coords[2])+0.5)
+}
+
+//Polygon returns vertices of the cell as pairs of latitude and longitude in degrees.
+//Vertices are listed counterclockwise when the face is seen from outside of the sphere,
+//edges between vertices are arcs of great circles.
+func (c *Curve) Polygon(code uint64) ([][2]float64, error) {
+	coords, err := c.Decode(code)
+	if err != nil {
+		return nil, err
+	}
+	i, j := float64(coords[1]), float64(coords[2])
+	corners := [4][2]float64{{i, j}, {i + 1, j}, {i + 1, j + 1}, {i, j + 1}}
+	res := make([][2]float64, len(corners))
+	for k, corner := range corners {
+		lat, lon, err := c.point(coords[0], corner[0], corner[1])
+		if err != nil {
+			return nil, err
+		}
+		res[k] = [2]float64{lat, lon}
+	}
+	return res, nil
+}
+
+//point returns latitude and longitude of the point of the face given in cell units.
+func (c *Curve) point(face uint64, i, j float64) (lat, lon float64, err error) {
+	if face >= Faces {
+		return 0, 0, fmt.Errorf("face == %v exceeds limit == %v", face, Faces-1)
+	}
+	side := float64(c.maxSize) + 1
+	lat, lon = xyzToLatLon(faceUVToXYZ(face, stToUV(i/side), stToUV(j/side)))
+	return lat, lon, nil
+}
+
+//stToIJ returns position of the cell on the face by the coordinate in range [0, 1].
+func (c *Curve) stToIJ(s float64) uint64 {
+	if s <= 0 {
+		return 0
+	}
+	ij := uint64(s * (float64(c.maxSize) + 1))
+	if ij > c.maxSize {
+		return c.maxSize
+	}
+	return ij
+}
+
+func latLonToXYZ(lat, lon float64) (x, y, z float64) {
+	phi, theta := lat*math.Pi/180, lon*math.Pi/180
+	return math.Cos(phi) * math.Cos(theta), math.Cos(phi) * math.Sin(theta), math.Sin(phi)
+}
+
+func xyzToLatLon(x, y, z float64) (lat, lon float64) {
+	lat = math.Atan2(z, math.Hypot(x, y)) * 180 / math.Pi
+	lon = math.Atan2(y, x) * 180 / math.Pi
+	return lat, lon
+}
+
+//xyzToFaceUV returns the face which axis is the biggest component of the vector
+//and coordinates of the projection onto the face in range [-1, 1].
+func xyzToFaceUV(x, y, z float64) (face uint64, u, v float64) {
+	ax, ay, az := math.Abs(x), math.Abs(y), math.Abs(z)
+	switch {
+	case ax >= ay && ax >= az:
+		face = 0
+		if x < 0 {
+			face = 3
+		}
+	case ay >= az:
+		face = 1
+		if y < 0 {
+			face = 4
+		}
+	default:
+		face = 2
+		if z < 0 {
+			face = 5
+		}
+	}
+	switch face {
+	case 0:
+		u, v = y/x, z/x
+	case 1:
+		u, v = -x/y, z/y
+	case 2:
+		u, v = -x/z, -y/z
+	case 3:
+		u, v = z/x, y/x
+	case 4:
+		u, v = z/y, -x/y
+	default:
+		u, v = -y/z, -x/z
+	}
+	return face, u, v
+}
+
+//faceUVToXYZ returns the vector of the point of the face, the vector is not normalized.
+func faceUVToXYZ(face uint64, u, v float64) (x, y, z float64) {
+	switch face {
+	case 0:
+		return 1, u, v
+	case 1:
+		return -u, 1, v
+	case 2:
+		return -u, -v, 1
+	case 3:
+		return -1, -v, -u
+	case 4:
+		return v, -1, -u
+	default:
+		return v, u, -1
+	}
+}
+
+//uvToST - quadratic transform of the coordinate of the face from [-1, 1] to [0, 1],
+//which makes cells of the face to cover similar area of the sphere.
+func uvToST(u float64) float64 {
+	if u >= 0 {
+		return 0.5 * math.Sqrt(1+3*u)
+	}
+	return 1 - 0.5*math.Sqrt(1-3*u)
+}
+
+//stToUV reverts uvToST.
+func stToUV(s float64) float64 {
+	if s >= 0.5 {
+		return (4*s*s - 1) / 3
+	}
+	return (1 - 4*(1-s)*(1-s)) / 3
+}
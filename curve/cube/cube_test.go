@@ -0,0 +1,213 @@
+package cube
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	type args struct {
+		dims uint64
+		bits uint64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{"3x1", args{dims: 3, bits: 1}, false},
+		{"3x30", args{dims: 3, bits: 30}, false},
+		{"3x0", args{dims: 3, bits: 0}, true},
+		{"3x31", args{dims: 3, bits: 31}, true},
+		{"2x4", args{dims: 2, bits: 4}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.args.dims, tt.args.bits)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(3), c.Dimensions())
+			assert.Equal(t, tt.args.bits, c.Bits())
+			assert.Equal(t, uint64(6<<(2*tt.args.bits)-1), c.Length())
+			assert.Equal(t, []uint64{5, 1<<tt.args.bits - 1, 1<<tt.args.bits - 1}, c.DimensionSizes())
+		})
+	}
+}
+
+func TestCurve_EncodeDecode(t *testing.T) {
+	c, err := New(3, 3)
+	if !assert.NoError(t, err) {
+		return
+	}
+	seen := map[[3]uint64]bool{}
+	for code := uint64(0); code <= c.Length(); code++ {
+		coords, err := c.Decode(code)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, code>>6, coords[0])
+		key := [3]uint64{coords[0], coords[1], coords[2]}
+		assert.False(t, seen[key])
+		seen[key] = true
+		got, err := c.Encode(coords)
+		if !assert.NoError(t, err) || !assert.Equal(t, code, got) {
+			return
+		}
+		fc, err := c.Face().Encode([]uint64{coords[1], coords[2]})
+		assert.NoError(t, err)
+		assert.Equal(t, code, coords[0]<<6|fc)
+	}
+}
+
+func TestCurve_Errors(t *testing.T) {
+	c, err := New(3, 3)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = c.Decode(c.Length() + 1)
+	assert.Error(t, err)
+	_, err = c.DecodeWithBuffer(make([]uint64, 2), 0)
+	assert.Error(t, err)
+	_, err = c.Encode([]uint64{0, 0})
+	assert.Error(t, err)
+	_, err = c.Encode([]uint64{6, 0, 0})
+	assert.Error(t, err)
+	_, err = c.Encode([]uint64{0, 8, 0})
+	assert.Error(t, err)
+	_, err = c.Polygon(c.Length() + 1)
+	assert.Error(t, err)
+	_, _, err = c.Center(c.Length() + 1)
+	assert.Error(t, err)
+}
+
+func TestCurve_Coords(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lon float64
+		want     uint64
+		wantErr  bool
+	}{
+		{"prime meridian", 0, 0, 0, false},
+		{"east", 0, 90, 1, false},
+		{"north pole", 90, 0, 2, false},
+		{"antimeridian", 0, 180, 3, false},
+		{"antimeridian west", 0, -180, 3, false},
+		{"west", 0, -90, 4, false},
+		{"south pole", -90, 0, 5, false},
+		{"latitude out of range", 200, 0, 0, true},
+		{"longitude out of range", 0, -180.5, 0, true},
+		{"nan latitude", math.NaN(), 0, 0, true},
+		{"infinite longitude", 0, math.Inf(1), 0, true},
+	}
+	c, err := New(3, 4)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coords, err := c.Coords(tt.lat, tt.lon)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, coords[0])
+		})
+	}
+}
+
+func TestCurve_CenterRoundTrip(t *testing.T) {
+	c, err := New(3, 12)
+	if !assert.NoError(t, err) {
+		return
+	}
+	rnd := rand.New(rand.NewSource(42))
+	for n := 0; n < 10000; n++ {
+		lat := math.Asin(2*rnd.Float64()-1) * 180 / math.Pi
+		lon := rnd.Float64()*360 - 180
+		coords, err := c.Coords(lat, lon)
+		if !assert.NoError(t, err) {
+			return
+		}
+		code, err := c.Encode(coords)
+		if !assert.NoError(t, err) {
+			return
+		}
+		clat, clon, err := c.Center(code)
+		if !assert.NoError(t, err) {
+			return
+		}
+		got, err := c.Coords(clat, clon)
+		assert.NoError(t, err)
+		if !assert.Equal(t, coords, got, "point %v, %v", lat, lon) {
+			return
+		}
+	}
+}
+
+func TestCurve_Polygon(t *testing.T) {
+	c, err := New(3, 3)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var total, min, max float64
+	min = math.Inf(1)
+	for code := uint64(0); code <= c.Length(); code++ {
+		poly, err := c.Polygon(code)
+		if !assert.NoError(t, err) || !assert.Len(t, poly, 4) {
+			return
+		}
+		vs := make([][3]float64, len(poly))
+		for i, p := range poly {
+			x, y, z := latLonToXYZ(p[0], p[1])
+			vs[i] = [3]float64{x, y, z}
+		}
+		area := triangleArea(vs[0], vs[1], vs[2]) + triangleArea(vs[0], vs[2], vs[3])
+		total += area
+		min = math.Min(min, area)
+		max = math.Max(max, area)
+
+		//counterclockwise seen from outside of the sphere
+		assert.True(t, dot(cross(sub(vs[1], vs[0]), sub(vs[2], vs[0])), vs[0]) > 0, "code %v", code)
+
+		//points near vertices belong to the cell
+		lat, lon, err := c.Center(code)
+		assert.NoError(t, err)
+		cx, cy, cz := latLonToXYZ(lat, lon)
+		want, err := c.Decode(code)
+		assert.NoError(t, err)
+		for _, v := range vs {
+			p := [3]float64{v[0]*0.99 + cx*0.01, v[1]*0.99 + cy*0.01, v[2]*0.99 + cz*0.01}
+			plat, plon := xyzToLatLon(p[0], p[1], p[2])
+			got, err := c.Coords(plat, plon)
+			assert.NoError(t, err)
+			assert.Equal(t, want, got, "code %v", code)
+		}
+	}
+	assert.InDelta(t, 4*math.Pi, total, 1e-9)
+	assert.Less(t, max/min, 2.1)
+}
+
+func triangleArea(a, b, c [3]float64) float64 {
+	num := math.Abs(dot(a, cross(b, c)))
+	den := 1 + dot(a, b) + dot(b, c) + dot(c, a)
+	return 2 * math.Atan2(num, den)
+}
+
+func dot(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func cross(a, b [3]float64) [3]float64 {
+	return [3]float64{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+
+func sub(a, b [3]float64) [3]float64 {
+	return [3]float64{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+}
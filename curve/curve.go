@@ -4,12 +4,7 @@
 */
 package curve
 
-import (
-	"errors"
-
-	"github.com/struckoff/sfcframework/curve/hilbert"
-	"github.com/struckoff/sfcframework/curve/morton"
-)
+import "errors"
 
 //Curve is an interface of space filling curve realisation.
 type Curve interface {
@@ -24,18 +19,15 @@ type Curve interface {
 
 //NewCurve - create a curve by given type
 //
-//cType - curve type(Hilbert, Morton)
+//cType - curve type(Hilbert, Morton, Peano, Moore, Cube, Gray, Onion or registered by Register)
 //
 //dims - amount of curve dimensions.
 //
 //bits - size in bits of each dimension.
 func NewCurve(cType CurveType, dims, bits uint64) (Curve, error) {
-	switch cType {
-	case Hilbert:
-		return hilbert.New(dims, bits)
-	case Morton:
-		return morton.New(dims, bits)
-	default:
+	f, ok := cType.factory()
+	if !ok {
 		return nil, errors.New("unknown curve type")
 	}
+	return f(dims, bits)
 }
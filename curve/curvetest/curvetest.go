@@ -0,0 +1,415 @@
+/*
+	Conformance checks for implementations of curve.Curve.
+
+	Run checks a curve created by the factory for several shapes(amount of dimensions and bits):
+	round trips of codes, bijection between codes and cells, adjacency of consecutive codes(if the curve promises it),
+	errors for codes and coordinates out of range, semantics of the buffer of DecodeWithBuffer and concurrent usage.
+	Small curves are checked exhaustively, big ones on a sample of codes.
+
+	Usage:
+		func TestCurve_Conformance(t *testing.T) {
+			curvetest.Run(t, curvetest.Factory(hilbert.New), curvetest.Options{})
+		}
+
+	FuzzDecode and FuzzEncode are fuzz targets for the same curve(Go 1.18 or newer),
+	Fuzz runs the same checks on random inputs generated from the seed with any version of Go.
+*/
+package curvetest
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/struckoff/sfcframework/curve"
+)
+
+const (
+	defaultMaxCells   = 1 << 16
+	defaultSamples    = 1 << 12
+	defaultGoroutines = 8
+)
+
+//Shape - amount of dimensions and size in bits of each dimension of the tested curve.
+type Shape struct {
+	Dims uint64
+	Bits uint64
+}
+
+//DefaultShapes - shapes which are checked if Options.Shapes is empty.
+//Shapes which codes do not fit 64 bits are expected to be rejected by the factory.
+var DefaultShapes = []Shape{
+	{Dims: 1, Bits: 1}, {Dims: 1, Bits: 8},
+	{Dims: 2, Bits: 1}, {Dims: 2, Bits: 4}, {Dims: 2, Bits: 7},
+	{Dims: 3, Bits: 1}, {Dims: 3, Bits: 3},
+	{Dims: 4, Bits: 2}, {Dims: 5, Bits: 2},
+	{Dims: 2, Bits: 31}, {Dims: 2, Bits: 32}, {Dims: 3, Bits: 21}, {Dims: 4, Bits: 16},
+}
+
+//Options of the conformance checks, zero values are replaced by defaults.
+type Options struct {
+	Shapes       []Shape //shapes of tested curves, DefaultShapes by default; shapes rejected by the factory are skipped
+	Adjacent     bool    //consecutive codes are neighbor cells: they differ by 1 in a single coordinate
+	Closed       bool    //the last code is adjacent to the first one
+	AltersCoords bool    //Encode may alter given coordinates(as the generic Hilbert encoding does)
+	MaxCells     uint64  //curves with more cells are checked on a sample of codes, 65536 by default
+	Samples      int     //amount of sampled codes of big curves, 4096 by default
+	Seed         int64   //seed of sampled codes
+	Goroutines   int     //amount of goroutines which use the curve concurrently, 8 by default
+}
+
+func (o Options) withDefaults() Options {
+	if len(o.Shapes) == 0 {
+		o.Shapes = DefaultShapes
+	}
+	if o.MaxCells == 0 {
+		o.MaxCells = defaultMaxCells
+	}
+	if o.Samples <= 0 {
+		o.Samples = defaultSamples
+	}
+	if o.Goroutines <= 0 {
+		o.Goroutines = defaultGoroutines
+	}
+	return o
+}
+
+//skip - the check does not apply to the curve.
+type skip string
+
+func (s skip) Error() string {
+	return string(s)
+}
+
+//check - a single conformance check, it returns the first violation found.
+type check struct {
+	name string
+	fn   func(c curve.Curve, shape Shape, opts Options) error
+}
+
+var checks = []check{
+	{"Shape", checkShape},
+	{"RoundTrip", checkRoundTrip},
+	{"Bijection", checkBijection},
+	{"Adjacency", checkAdjacency},
+	{"Errors", checkErrors},
+	{"Buffer", checkBuffer},
+	{"Concurrency", checkConcurrency},
+}
+
+//Run checks curves created by the factory for each shape of options as subtests of t.
+//Run fails if the factory rejects all shapes.
+func Run(t *testing.T, factory curve.Factory, opts Options) {
+	opts = opts.withDefaults()
+	tested := 0
+	for _, shape := range opts.Shapes {
+		c, err := factory(shape.Dims, shape.Bits)
+		if err != nil {
+			t.Logf("shape %vx%v is skipped: %v", shape.Dims, shape.Bits, err)
+			continue
+		}
+		tested++
+		shape := shape
+		t.Run(fmt.Sprintf("%vx%v", shape.Dims, shape.Bits), func(t *testing.T) {
+			for _, ch := range checks {
+				ch := ch
+				t.Run(ch.name, func(t *testing.T) {
+					err := ch.fn(c, shape, opts)
+					if s, ok := err.(skip); ok {
+						t.Skip(string(s))
+					}
+					if err != nil {
+						t.Error(err)
+					}
+				})
+			}
+		})
+	}
+	if tested == 0 {
+		t.Fatal("factory rejected all shapes")
+	}
+}
+
+//exhaustive checks if all codes of the curve could be checked one by one.
+func exhaustive(c curve.Curve, opts Options) bool {
+	return c.Length() < opts.MaxCells
+}
+
+//codes returns codes to check: all codes of small curves,
+//the first, the last and random codes of big ones.
+func codes(c curve.Curve, opts Options) []uint64 {
+	if exhaustive(c, opts) {
+		res := make([]uint64, c.Length()+1)
+		for i := range res {
+			res[i] = uint64(i)
+		}
+		return res
+	}
+	rnd := rand.New(rand.NewSource(opts.Seed))
+	res := []uint64{0, c.Length()}
+	for len(res) < opts.Samples {
+		res = append(res, random(rnd, c.Length()))
+	}
+	return res
+}
+
+//random returns a random code in range [0, max].
+func random(rnd *rand.Rand, max uint64) uint64 {
+	if max == math.MaxUint64 {
+		return rnd.Uint64()
+	}
+	return rnd.Uint64() % (max + 1)
+}
+
+//checkShape checks the amount of dimensions and that the amount of cells equals the amount of codes.
+//Bits are not checked, curves may round the size of dimensions up(as the Peano curve does).
+func checkShape(c curve.Curve, shape Shape, _ Options) error {
+	if c.Dimensions() != shape.Dims {
+		return fmt.Errorf("Dimensions() == %v, want %v", c.Dimensions(), shape.Dims)
+	}
+	sizes := curve.DimensionSizes(c)
+	if uint64(len(sizes)) != shape.Dims {
+		return fmt.Errorf("amount of dimension sizes == %v, want %v", len(sizes), shape.Dims)
+	}
+	cells := big.NewInt(1)
+	for _, size := range sizes {
+		if size < c.DimensionSize() {
+			return fmt.Errorf("dimension size == %v less then DimensionSize() == %v", size, c.DimensionSize())
+		}
+		side := new(big.Int).SetUint64(size)
+		cells.Mul(cells, side.Add(side, big.NewInt(1)))
+	}
+	codes := new(big.Int).SetUint64(c.Length())
+	codes.Add(codes, big.NewInt(1))
+	if cells.Cmp(codes) != 0 {
+		return fmt.Errorf("amount of cells == %v differs from amount of codes(Length() + 1) == %v", cells, codes)
+	}
+	return nil
+}
+
+//checkRoundTrip checks that decoded codes are inside of the curve and are encoded back to the same codes.
+func checkRoundTrip(c curve.Curve, _ Shape, opts Options) error {
+	sizes := curve.DimensionSizes(c)
+	for _, code := range codes(c, opts) {
+		if err := checkCode(c, sizes, code, opts.AltersCoords); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//checkCode checks the round trip of the single code.
+func checkCode(c curve.Curve, sizes []uint64, code uint64, alters bool) error {
+	coords, err := c.Decode(code)
+	if err != nil {
+		return fmt.Errorf("Decode(%v): %v", code, err)
+	}
+	if uint64(len(coords)) != c.Dimensions() {
+		return fmt.Errorf("Decode(%v) == %v, want %v coordinates", code, coords, c.Dimensions())
+	}
+	for i := range coords {
+		if coords[i] > sizes[i] {
+			return fmt.Errorf("Decode(%v) == %v, coordinate %v exceeds limit == %v", code, coords, i, sizes[i])
+		}
+	}
+	return checkCoords(c, coords, code, alters)
+}
+
+//checkCoords checks that coordinates are encoded into the code and, unless alters is set, are not modified by Encode.
+func checkCoords(c curve.Curve, coords []uint64, want uint64, alters bool) error {
+	orig := append([]uint64{}, coords...)
+	got, err := c.Encode(coords)
+	if err != nil {
+		return fmt.Errorf("Encode(%v): %v", orig, err)
+	}
+	if !alters && !equal(coords, orig) {
+		return fmt.Errorf("Encode(%v) modified coordinates to %v", orig, coords)
+	}
+	if got != want {
+		return fmt.Errorf("Encode(%v) == %v, want %v", orig, got, want)
+	}
+	return nil
+}
+
+//checkBijection checks that every cell is decoded from a single code.
+func checkBijection(c curve.Curve, _ Shape, opts Options) error {
+	if !exhaustive(c, opts) {
+		return skip("curve is too big to be checked exhaustively")
+	}
+	seen := make(map[string]uint64, c.Length()+1)
+	for code := uint64(0); code <= c.Length(); code++ {
+		coords, err := c.Decode(code)
+		if err != nil {
+			return fmt.Errorf("Decode(%v): %v", code, err)
+		}
+		key := fmt.Sprint(coords)
+		if prev, ok := seen[key]; ok {
+			return fmt.Errorf("codes %v and %v are decoded into the same cell %v", prev, code, coords)
+		}
+		seen[key] = code
+	}
+	return nil
+}
+
+//checkAdjacency checks that consecutive codes are neighbor cells.
+func checkAdjacency(c curve.Curve, _ Shape, opts Options) error {
+	if !opts.Adjacent && !opts.Closed {
+		return skip("curve does not promise adjacency")
+	}
+	var pairs [][2]uint64
+	if opts.Adjacent {
+		for _, code := range codes(c, opts) {
+			if code < c.Length() {
+				pairs = append(pairs, [2]uint64{code, code + 1})
+			}
+		}
+	}
+	if opts.Closed {
+		pairs = append(pairs, [2]uint64{c.Length(), 0})
+	}
+	for _, p := range pairs {
+		a, err := c.Decode(p[0])
+		if err != nil {
+			return fmt.Errorf("Decode(%v): %v", p[0], err)
+		}
+		b, err := c.Decode(p[1])
+		if err != nil {
+			return fmt.Errorf("Decode(%v): %v", p[1], err)
+		}
+		if !adjacent(a, b) {
+			return fmt.Errorf("cells %v and %v of codes %v and %v are not adjacent", a, b, p[0], p[1])
+		}
+	}
+	return nil
+}
+
+//adjacent checks if cells differ by 1 in a single coordinate.
+func adjacent(a, b []uint64) bool {
+	diff := 0
+	for i := range a {
+		switch {
+		case a[i] == b[i]:
+		case a[i]+1 == b[i] || b[i]+1 == a[i]:
+			diff++
+		default:
+			return false
+		}
+	}
+	return diff == 1
+}
+
+//checkErrors checks that codes and coordinates out of range, short buffers and short coordinates are rejected.
+func checkErrors(c curve.Curve, _ Shape, _ Options) error {
+	if c.Length() < math.MaxUint64 {
+		if coords, err := c.Decode(c.Length() + 1); err == nil {
+			return fmt.Errorf("Decode(Length() + 1) == %v, want error", coords)
+		}
+		if coords, err := c.DecodeWithBuffer(make([]uint64, c.Dimensions()), c.Length()+1); err == nil {
+			return fmt.Errorf("DecodeWithBuffer(Length() + 1) == %v, want error", coords)
+		}
+	}
+	if coords, err := c.DecodeWithBuffer(make([]uint64, c.Dimensions()-1), 0); err == nil {
+		return fmt.Errorf("DecodeWithBuffer with buffer of %v elements == %v, want error", c.Dimensions()-1, coords)
+	}
+	if code, err := c.Encode(make([]uint64, c.Dimensions()-1)); err == nil {
+		return fmt.Errorf("Encode of %v coordinates == %v, want error", c.Dimensions()-1, code)
+	}
+	for i, size := range curve.DimensionSizes(c) {
+		if size == math.MaxUint64 {
+			continue
+		}
+		coords := make([]uint64, c.Dimensions())
+		coords[i] = size + 1
+		if code, err := c.Encode(coords); err == nil {
+			return fmt.Errorf("Encode(%v) == %v, want error", coords, code)
+		}
+	}
+	return nil
+}
+
+//checkBuffer checks that DecodeWithBuffer decodes into the buffer the same coordinates as Decode.
+func checkBuffer(c curve.Curve, _ Shape, opts Options) error {
+	dims := int(c.Dimensions())
+	bufs := [][]uint64{make([]uint64, dims), make([]uint64, dims+2)}
+	for _, code := range codes(c, opts) {
+		want, err := c.Decode(code)
+		if err != nil {
+			return fmt.Errorf("Decode(%v): %v", code, err)
+		}
+		for _, buf := range bufs {
+			//previous values of the buffer must not affect the result
+			for i := range buf {
+				buf[i] = math.MaxUint64 - uint64(i)
+			}
+			got, err := c.DecodeWithBuffer(buf, code)
+			if err != nil {
+				return fmt.Errorf("DecodeWithBuffer(%v elements, %v): %v", len(buf), code, err)
+			}
+			if len(got) < dims || &got[0] != &buf[0] {
+				return fmt.Errorf("DecodeWithBuffer(%v elements, %v) does not return the buffer", len(buf), code)
+			}
+			if !equal(got[:dims], want) {
+				return fmt.Errorf("DecodeWithBuffer(%v elements, %v) == %v, Decode(%v) == %v", len(buf), code, got[:dims], code, want)
+			}
+		}
+	}
+	return nil
+}
+
+//checkConcurrency checks that the curve gives the same results when it is used by several goroutines at once.
+//Data races are reported when tests are run with -race.
+func checkConcurrency(c curve.Curve, _ Shape, opts Options) error {
+	cs := codes(c, opts)
+	want := make([][]uint64, len(cs))
+	for i, code := range cs {
+		coords, err := c.Decode(code)
+		if err != nil {
+			return fmt.Errorf("Decode(%v): %v", code, err)
+		}
+		want[i] = coords
+	}
+
+	errs := make(chan error, opts.Goroutines)
+	var wg sync.WaitGroup
+	for g := 0; g < opts.Goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			buf := make([]uint64, c.Dimensions())
+			coords := make([]uint64, c.Dimensions())
+			//goroutines start at different codes to mix calls
+			for n := range cs {
+				i := (n + g*len(cs)/opts.Goroutines) % len(cs)
+				got, err := c.DecodeWithBuffer(buf, cs[i])
+				if err != nil || !equal(got, want[i]) {
+					errs <- fmt.Errorf("concurrent DecodeWithBuffer(%v) == %v, %v, want %v", cs[i], got, err, want[i])
+					return
+				}
+				copy(coords, want[i])
+				code, err := c.Encode(coords)
+				if err != nil || code != cs[i] {
+					errs <- fmt.Errorf("concurrent Encode(%v) == %v, %v, want %v", want[i], code, err, cs[i])
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
+
+func equal(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,213 @@
+package curvetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/struckoff/sfcframework/curve"
+	"github.com/struckoff/sfcframework/curve/hilbert"
+	"github.com/struckoff/sfcframework/curve/moore"
+	"github.com/struckoff/sfcframework/curve/morton"
+)
+
+func TestRun(t *testing.T) {
+	Run(t, func(dims, bits uint64) (curve.Curve, error) {
+		return curve.NewCurve(curve.Hilbert, dims, bits)
+	}, Options{Adjacent: true, AltersCoords: true})
+}
+
+func TestFactory(t *testing.T) {
+	f := Factory(morton.New)
+	c, err := f(2, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(63), c.Length())
+	c, err = f(0, 3)
+	assert.Error(t, err)
+	assert.Nil(t, c)
+
+	c, err = Factory(curve.Factory(func(dims, bits uint64) (curve.Curve, error) {
+		return curve.NewCurve(curve.Hilbert, dims, bits)
+	}))(2, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), c.Dimensions())
+
+	assert.Panics(t, func() { Factory(nil) })
+	assert.Panics(t, func() { Factory(hilbert.NewCompact) })
+	assert.Panics(t, func() { Factory(func(dims, bits uint64) (int, error) { return 0, nil }) })
+}
+
+//broken wraps the Hilbert curve and breaks one of its promises.
+type broken struct {
+	curve.Curve
+	decode func(c curve.Curve, buf []uint64, code uint64) ([]uint64, error)
+	encode func(c curve.Curve, coords []uint64) (uint64, error)
+}
+
+func (b broken) Decode(code uint64) ([]uint64, error) {
+	return b.DecodeWithBuffer(make([]uint64, b.Dimensions()), code)
+}
+
+func (b broken) DecodeWithBuffer(buf []uint64, code uint64) ([]uint64, error) {
+	if b.decode != nil {
+		return b.decode(b.Curve, buf, code)
+	}
+	return b.Curve.DecodeWithBuffer(buf, code)
+}
+
+func (b broken) Encode(coords []uint64) (uint64, error) {
+	if b.encode != nil {
+		return b.encode(b.Curve, coords)
+	}
+	return b.Curve.Encode(coords)
+}
+
+func Test_checks(t *testing.T) {
+	h, err := hilbert.New(2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	z, err := morton.New(2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h3, err := hilbert.New(3, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := moore.New(2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shape := Shape{Dims: 2, Bits: 3}
+	opts := Options{}.withDefaults()
+	tests := []struct {
+		name  string
+		ref   curve.Curve //curve which passes the check, Hilbert curve if nil
+		c     curve.Curve
+		opts  Options
+		check func(c curve.Curve, shape Shape, opts Options) error
+	}{
+		{
+			name:  "shape",
+			c:     h3,
+			opts:  opts,
+			check: checkShape,
+		},
+		{
+			name: "round trip",
+			c: broken{Curve: h, encode: func(c curve.Curve, coords []uint64) (uint64, error) {
+				code, err := c.Encode(coords)
+				return code ^ 1, err
+			}},
+			opts:  opts,
+			check: checkRoundTrip,
+		},
+		{
+			name: "modified coordinates",
+			c: broken{Curve: h, encode: func(c curve.Curve, coords []uint64) (uint64, error) {
+				code, err := c.Encode(coords)
+				coords[0] = 0
+				return code, err
+			}},
+			opts:  opts,
+			check: checkRoundTrip,
+		},
+		{
+			name: "bijection",
+			c: broken{Curve: h, decode: func(c curve.Curve, buf []uint64, code uint64) ([]uint64, error) {
+				return c.DecodeWithBuffer(buf, code&^1)
+			}},
+			opts:  opts,
+			check: checkBijection,
+		},
+		{
+			name:  "adjacency",
+			c:     z,
+			opts:  Options{Adjacent: true}.withDefaults(),
+			check: checkAdjacency,
+		},
+		{
+			name:  "closed",
+			ref:   m,
+			c:     h,
+			opts:  Options{Closed: true}.withDefaults(),
+			check: checkAdjacency,
+		},
+		{
+			name: "errors",
+			c: broken{Curve: h, encode: func(c curve.Curve, coords []uint64) (uint64, error) {
+				if len(coords) >= 2 && coords[0] > c.DimensionSize() {
+					return 0, nil
+				}
+				return c.Encode(coords)
+			}},
+			opts:  opts,
+			check: checkErrors,
+		},
+		{
+			name: "buffer",
+			c: broken{Curve: h, decode: func(c curve.Curve, buf []uint64, code uint64) ([]uint64, error) {
+				return c.Decode(code)
+			}},
+			opts:  opts,
+			check: checkBuffer,
+		},
+		{
+			name: "dirty buffer",
+			c: broken{Curve: h, decode: func(c curve.Curve, buf []uint64, code uint64) ([]uint64, error) {
+				if code == 0 {
+					return buf, nil
+				}
+				return c.DecodeWithBuffer(buf, code)
+			}},
+			opts:  opts,
+			check: checkBuffer,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref := tt.ref
+			if ref == nil {
+				ref = h
+			}
+			assert.NoError(t, tt.check(ref, shape, tt.opts), "reference curve")
+			assert.Error(t, tt.check(tt.c, shape, tt.opts))
+		})
+	}
+}
+
+func Test_checks_Skip(t *testing.T) {
+	h, err := hilbert.New(2, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{}.withDefaults()
+	assert.IsType(t, skip(""), checkBijection(h, Shape{Dims: 2, Bits: 20}, opts))
+	assert.IsType(t, skip(""), checkAdjacency(h, Shape{Dims: 2, Bits: 20}, opts))
+	assert.NoError(t, checkRoundTrip(h, Shape{Dims: 2, Bits: 20}, opts))
+}
+
+func Test_fuzz(t *testing.T) {
+	h, err := hilbert.New(2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sizes := curve.DimensionSizes(h)
+	assert.NoError(t, fuzzCode(h, sizes, 1<<40+5))
+	assert.NoError(t, fuzzCoords(h, sizes, []byte{3, 0, 0, 0, 0, 0, 0, 0, 5}))
+	assert.NoError(t, fuzzCoords(h, sizes, []byte{9}), "coordinates out of range")
+
+	b := broken{Curve: h, encode: func(c curve.Curve, coords []uint64) (uint64, error) {
+		code, err := c.Encode(coords)
+		return code ^ 1, err
+	}}
+	assert.Error(t, fuzzCode(b, sizes, 6))
+	assert.Error(t, fuzzCoords(b, sizes, []byte{3}))
+	b = broken{Curve: h, encode: func(c curve.Curve, coords []uint64) (uint64, error) {
+		if coords[0] > c.DimensionSize() {
+			return 0, nil
+		}
+		return c.Encode(coords)
+	}}
+	assert.Error(t, fuzzCoords(b, sizes, []byte{9}))
+}
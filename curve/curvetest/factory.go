@@ -0,0 +1,45 @@
+package curvetest
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/struckoff/sfcframework/curve"
+)
+
+var (
+	uint64Type = reflect.TypeOf(uint64(0))
+	errorType  = reflect.TypeOf((*error)(nil)).Elem()
+	curveType  = reflect.TypeOf((*curve.Curve)(nil)).Elem()
+)
+
+//Factory adapts the constructor of the curve package to curve.Factory, so the package tests its curve by a single call:
+//	curvetest.Run(t, curvetest.Factory(hilbert.New), curvetest.Options{})
+//
+//constructor - function func(dims, bits uint64) (C, error), where C implements curve.Curve.
+//Factory panics if constructor has another signature.
+//If the constructor returns error, the curve is nil, not C(nil) wrapped into the interface.
+func Factory(constructor interface{}) curve.Factory {
+	if f, ok := constructor.(curve.Factory); ok {
+		return f
+	}
+	if f, ok := constructor.(func(dims, bits uint64) (curve.Curve, error)); ok {
+		return f
+	}
+	fn := reflect.ValueOf(constructor)
+	if fn.Kind() != reflect.Func {
+		panic(fmt.Sprintf("constructor %T is not a function", constructor))
+	}
+	ft := fn.Type()
+	if ft.IsVariadic() || ft.NumIn() != 2 || ft.In(0) != uint64Type || ft.In(1) != uint64Type ||
+		ft.NumOut() != 2 || !ft.Out(0).Implements(curveType) || ft.Out(1) != errorType {
+		panic(fmt.Sprintf("constructor %v is not func(dims, bits uint64) (curve.Curve, error)", ft))
+	}
+	return func(dims, bits uint64) (curve.Curve, error) {
+		out := fn.Call([]reflect.Value{reflect.ValueOf(dims), reflect.ValueOf(bits)})
+		if err, _ := out[1].Interface().(error); err != nil {
+			return nil, err
+		}
+		return out[0].Interface().(curve.Curve), nil
+	}
+}
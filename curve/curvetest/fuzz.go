@@ -0,0 +1,53 @@
+//go:build go1.18
+// +build go1.18
+
+package curvetest
+
+import (
+	"testing"
+
+	"github.com/struckoff/sfcframework/curve"
+)
+
+//FuzzDecode fuzzes the curve created by the factory with given shape by codes.
+//Fuzzed codes are reduced to the range of the curve, each code is decoded and encoded back.
+//Unlike Run, it does not check if Encode alters coordinates.
+//
+//Usage:
+//	func FuzzCurve_Decode(f *testing.F) {
+//		curvetest.FuzzDecode(f, factory, 3, 8)
+//	}
+func FuzzDecode(f *testing.F, factory curve.Factory, dims, bits uint64) {
+	c, err := factory(dims, bits)
+	if err != nil {
+		f.Fatal(err)
+	}
+	sizes := curve.DimensionSizes(c)
+	f.Add(uint64(0))
+	f.Add(c.Length())
+	f.Add(c.Length() / 2)
+	f.Fuzz(func(t *testing.T, code uint64) {
+		if err := fuzzCode(c, sizes, code); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+//FuzzEncode fuzzes the curve created by the factory with given shape by coordinates.
+//Coordinates are read from fuzzed bytes, 8 bytes(little endian) per dimension, missing bytes are zeros.
+//Each coordinate is reduced to twice the size of its dimension, so about half of coordinates are in range.
+//Coordinates out of range must be rejected by Encode, others are encoded and decoded back.
+func FuzzEncode(f *testing.F, factory curve.Factory, dims, bits uint64) {
+	c, err := factory(dims, bits)
+	if err != nil {
+		f.Fatal(err)
+	}
+	sizes := curve.DimensionSizes(c)
+	f.Add([]byte{})
+	f.Add(maxCoords(sizes))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if err := fuzzCoords(c, sizes, data); err != nil {
+			t.Error(err)
+		}
+	})
+}
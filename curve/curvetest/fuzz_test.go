@@ -0,0 +1,44 @@
+//go:build go1.18
+// +build go1.18
+
+package curvetest
+
+import (
+	"testing"
+
+	"github.com/struckoff/sfcframework/curve"
+)
+
+func factory(cType curve.CurveType) curve.Factory {
+	return func(dims, bits uint64) (curve.Curve, error) {
+		return curve.NewCurve(cType, dims, bits)
+	}
+}
+
+func FuzzDecode_Hilbert(f *testing.F) {
+	FuzzDecode(f, factory(curve.Hilbert), 3, 21)
+}
+
+func FuzzEncode_Hilbert(f *testing.F) {
+	FuzzEncode(f, factory(curve.Hilbert), 3, 21)
+}
+
+func FuzzDecode_Peano(f *testing.F) {
+	FuzzDecode(f, factory(curve.Peano), 2, 20)
+}
+
+func FuzzEncode_Peano(f *testing.F) {
+	FuzzEncode(f, factory(curve.Peano), 2, 20)
+}
+
+func FuzzDecode_Onion(f *testing.F) {
+	FuzzDecode(f, factory(curve.Onion), 4, 16)
+}
+
+func FuzzEncode_Onion(f *testing.F) {
+	FuzzEncode(f, factory(curve.Onion), 4, 16)
+}
+
+func FuzzEncode_Cube(f *testing.F) {
+	FuzzEncode(f, factory(curve.Cube), 3, 30)
+}
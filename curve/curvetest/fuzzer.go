@@ -0,0 +1,101 @@
+package curvetest
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/struckoff/sfcframework/curve"
+)
+
+//Fuzz is the fuzz driver for Go versions without native fuzzing(before Go 1.18).
+//It checks the curve created by the factory with given shape by n random codes and n random sets of coordinates,
+//the same way as fuzz targets FuzzDecode and FuzzEncode do.
+//Inputs are generated from the seed, so failures are reproducible.
+//
+//Usage:
+//	func TestCurve_Fuzz(t *testing.T) {
+//		curvetest.Fuzz(t, factory, 3, 8, 42, 10000)
+//	}
+func Fuzz(t *testing.T, factory curve.Factory, dims, bits uint64, seed int64, n int) {
+	c, err := factory(dims, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sizes := curve.DimensionSizes(c)
+	codes := []uint64{0, c.Length(), c.Length() / 2}
+	inputs := [][]byte{{}, maxCoords(sizes)}
+	rnd := rand.New(rand.NewSource(seed))
+	for i := 0; i < n; i++ {
+		codes = append(codes, rnd.Uint64())
+		data := make([]byte, 8*dims)
+		rnd.Read(data)
+		inputs = append(inputs, data)
+	}
+	for _, code := range codes {
+		if err := fuzzCode(c, sizes, code); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	for _, data := range inputs {
+		if err := fuzzCoords(c, sizes, data); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+}
+
+//maxCoords returns fuzzed bytes of the biggest coordinates of the curve.
+func maxCoords(sizes []uint64) []byte {
+	data := make([]byte, 8*len(sizes))
+	for i, size := range sizes {
+		binary.LittleEndian.PutUint64(data[8*i:], size)
+	}
+	return data
+}
+
+//fuzzCode reduces the fuzzed code to the range of the curve, decodes it and encodes back.
+func fuzzCode(c curve.Curve, sizes []uint64, code uint64) error {
+	if c.Length() < math.MaxUint64 {
+		code %= c.Length() + 1
+	}
+	return checkCode(c, sizes, code, true)
+}
+
+//fuzzCoords reads coordinates from fuzzed bytes, 8 bytes(little endian) per dimension, missing bytes are zeros.
+//Each coordinate is reduced to twice the size of its dimension, so about half of coordinates are in range.
+//Coordinates out of range must be rejected by Encode, others are encoded and decoded back.
+func fuzzCoords(c curve.Curve, sizes []uint64, data []byte) error {
+	buf := make([]byte, 8*len(sizes))
+	copy(buf, data)
+	coords := make([]uint64, len(sizes))
+	valid := true
+	for i := range coords {
+		coords[i] = binary.LittleEndian.Uint64(buf[8*i:])
+		if sizes[i] < math.MaxUint64/2 {
+			coords[i] %= 2 * (sizes[i] + 1)
+		}
+		valid = valid && coords[i] <= sizes[i]
+	}
+	code, err := c.Encode(append([]uint64{}, coords...))
+	if !valid {
+		if err == nil {
+			return fmt.Errorf("Encode(%v) == %v, want error", coords, code)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Encode(%v): %v", coords, err)
+	}
+	got, err := c.Decode(code)
+	if err != nil {
+		return fmt.Errorf("Decode(%v): %v", code, err)
+	}
+	if !equal(got, coords) {
+		return fmt.Errorf("Decode(Encode(%v)) == %v", coords, got)
+	}
+	return nil
+}
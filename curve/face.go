@@ -0,0 +1,59 @@
+package curve
+
+import (
+	"github.com/struckoff/sfcframework/curve/hilbert"
+)
+
+//FacedCurve is implemented by curves which consist of faces filled by the same Hilbert curve(cube.Curve).
+//The coordinate 0 is the face, other coordinates are the cell on the face,
+//codes of the face f are codes of the face curve shifted by f * (Face().Length() + 1).
+//
+//Aligned blocks of such curves span several faces, so boxes and regions are decomposed face by face
+//by aligned blocks of the face curve.
+type FacedCurve interface {
+	Curve
+	Face() *hilbert.Curve //Face returns the curve which fills each face
+}
+
+//faceRanges decomposes the box on each face it covers, ranges of the face are shifted to codes of the face.
+func faceRanges(c FacedCurve, min, max []uint64, limit int) ([]Range, error) {
+	face := c.Face()
+	span := face.Length() + 1
+	var res []Range
+	for f := min[0]; f <= max[0]; f++ {
+		rs, err := BoxRanges(face, min[1:], max[1:], limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rs {
+			res = append(res, NewRange(f*span+r.Min, f*span+r.Max))
+		}
+	}
+	return res, nil
+}
+
+//faceRegion splits the range by faces, each piece is decomposed into boxes of the face curve.
+func faceRegion(c FacedCurve, r Range) ([]Box, error) {
+	face := c.Face()
+	span := face.Length() + 1
+	var res []Box
+	for pos := r.Min; pos < r.Max; {
+		f := pos / span
+		end := (f + 1) * span
+		if end > r.Max {
+			end = r.Max
+		}
+		boxes, err := RangeRegion(face, NewRange(pos-f*span, end-f*span))
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range boxes {
+			res = append(res, Box{
+				Min: append([]uint64{f}, b.Min...),
+				Max: append([]uint64{f}, b.Max...),
+			})
+		}
+		pos = end
+	}
+	return res, nil
+}
@@ -0,0 +1,120 @@
+package curve
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+//FloatBounds - the minimum and the maximum(inclusive) values of the float dimension.
+type FloatBounds struct {
+	Min float64
+	Max float64
+}
+
+//FloatCurve wraps a curve to encode float values of dimensions with known bounds.
+//
+//Bounds of each dimension are divided into DimensionSizes(c)[i] + 1 cells of equal width,
+//the maximum value belongs to the last cell.
+//
+//Optional interfaces of the wrapped curve(BlockCurve, Walker, ...) are not promoted,
+//the wrapped curve is available as the Curve field.
+type FloatCurve struct {
+	Curve
+	bounds []FloatBounds
+	sizes  []uint64  //maximum coordinate value of each dimension
+	widths []float64 //width of the cell along each dimension
+}
+
+//NewFloatCurve creates a float wrapper of the curve with bounds of each dimension.
+func NewFloatCurve(c Curve, bounds []FloatBounds) (*FloatCurve, error) {
+	if uint64(len(bounds)) != c.Dimensions() {
+		return nil, fmt.Errorf("number of bounds == %v differs from dimensions == %v", len(bounds), c.Dimensions())
+	}
+	fc := &FloatCurve{
+		Curve:  c,
+		bounds: append([]FloatBounds{}, bounds...),
+		sizes:  DimensionSizes(c),
+		widths: make([]float64, len(bounds)),
+	}
+	for i, b := range bounds {
+		if math.IsNaN(b.Min) || math.IsNaN(b.Max) || math.IsInf(b.Max-b.Min, 0) {
+			return nil, fmt.Errorf("bounds of dimension %v must be finite", i)
+		}
+		if b.Min >= b.Max {
+			return nil, fmt.Errorf("minimum == %v of dimension %v must be less than maximum == %v", b.Min, i, b.Max)
+		}
+		fc.widths[i] = (b.Max - b.Min) / (float64(fc.sizes[i]) + 1)
+	}
+	return fc, nil
+}
+
+//Bounds returns bounds of each dimension.
+func (fc *FloatCurve) Bounds() []FloatBounds {
+	return append([]FloatBounds{}, fc.bounds...)
+}
+
+//Resolution returns the width of the cell along each dimension in units of the dimension.
+func (fc *FloatCurve) Resolution() []float64 {
+	return append([]float64{}, fc.widths...)
+}
+
+//Coords returns coordinates of the cell which contains values.
+//Method will return error if the amount of values differs from dimensions or any value is out of bounds.
+func (fc *FloatCurve) Coords(values []float64) ([]uint64, error) {
+	if len(values) != len(fc.bounds) {
+		return nil, fmt.Errorf("number of values == %v differs from dimensions == %v", len(values), len(fc.bounds))
+	}
+	coords := make([]uint64, len(values))
+	for i, v := range values {
+		b := fc.bounds[i]
+		if !(v >= b.Min && v <= b.Max) {
+			return nil, fmt.Errorf("value == %v of dimension %v is out of bounds [%v, %v]", v, i, b.Min, b.Max)
+		}
+		//the product is computed before the division to keep cells aligned to bounds
+		cell := math.Floor((v - b.Min) * (float64(fc.sizes[i]) + 1) / (b.Max - b.Min))
+		if cell >= float64(fc.sizes[i]) {
+			coords[i] = fc.sizes[i]
+			continue
+		}
+		coords[i] = uint64(cell)
+	}
+	return coords, nil
+}
+
+//EncodeFloat returns code(distance) of the cell which contains values.
+//Method will return error if the amount of values differs from dimensions or any value is out of bounds.
+func (fc *FloatCurve) EncodeFloat(values []float64) (uint64, error) {
+	coords, err := fc.Coords(values)
+	if err != nil {
+		return 0, err
+	}
+	return fc.Curve.Encode(coords)
+}
+
+//DecodeBox returns the bounding box of the cell with given code:
+//minimum(inclusive) and maximum(exclusive, except the last cell of the dimension) values of each dimension.
+func (fc *FloatCurve) DecodeBox(code uint64) (min, max []float64, err error) {
+	coords, err := fc.Curve.Decode(code)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(coords) < len(fc.bounds) {
+		return nil, nil, errors.New("curve decoded less coordinates than dimensions")
+	}
+	min = make([]float64, len(fc.bounds))
+	max = make([]float64, len(fc.bounds))
+	for i, b := range fc.bounds {
+		min[i], max[i] = fc.edge(i, coords[i]), fc.edge(i, coords[i]+1)
+		if coords[i] == fc.sizes[i] {
+			max[i] = b.Max
+		}
+	}
+	return min, max, nil
+}
+
+//edge returns the value of the lower edge of the cell along the dimension.
+func (fc *FloatCurve) edge(dim int, coord uint64) float64 {
+	b := fc.bounds[dim]
+	return b.Min + float64(coord)*(b.Max-b.Min)/(float64(fc.sizes[dim])+1)
+}
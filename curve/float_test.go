@@ -0,0 +1,121 @@
+package curve
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFloatCurve(t *testing.T) {
+	c, err := NewCurve(Hilbert, 2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		bounds  []FloatBounds
+		wantErr bool
+	}{
+		{"valid", []FloatBounds{{-90, 90}, {-180, 180}}, false},
+		{"not enough bounds", []FloatBounds{{-90, 90}}, true},
+		{"empty", []FloatBounds{{-90, 90}, {1, 1}}, true},
+		{"inverted", []FloatBounds{{90, -90}, {-180, 180}}, true},
+		{"NaN", []FloatBounds{{math.NaN(), 90}, {-180, 180}}, true},
+		{"infinite", []FloatBounds{{-90, 90}, {-math.MaxFloat64, math.MaxFloat64}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fc, err := NewFloatCurve(c, tt.bounds)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.bounds, fc.Bounds())
+			assert.Equal(t, []float64{180.0 / 16, 360.0 / 16}, fc.Resolution())
+		})
+	}
+}
+
+func TestFloatCurve_Coords(t *testing.T) {
+	c, err := NewCurve(Morton, 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fc, err := NewFloatCurve(c, []FloatBounds{{0, 1}, {-10, 10}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		values  []float64
+		want    []uint64
+		wantErr bool
+	}{
+		{"minimum", []float64{0, -10}, []uint64{0, 0}, false},
+		{"maximum", []float64{1, 10}, []uint64{3, 3}, false},
+		{"edges", []float64{0.25, 0}, []uint64{1, 2}, false},
+		{"inside", []float64{0.74, 4.99}, []uint64{2, 2}, false},
+		{"below", []float64{-0.01, 0}, nil, true},
+		{"above", []float64{0.5, 10.01}, nil, true},
+		{"NaN", []float64{math.NaN(), 0}, nil, true},
+		{"not enough values", []float64{0.5}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fc.Coords(tt.values)
+			if tt.wantErr {
+				assert.Error(t, err)
+				_, err = fc.EncodeFloat(tt.values)
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			code, err := fc.EncodeFloat(tt.values)
+			assert.NoError(t, err)
+			want, err := c.Encode(tt.want)
+			assert.NoError(t, err)
+			assert.Equal(t, want, code)
+		})
+	}
+}
+
+func TestFloatCurve_DecodeBox(t *testing.T) {
+	c, err := NewUnevenCurve(Hilbert, []uint64{3, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fc, err := NewFloatCurve(c, []FloatBounds{{-90, 90}, {-180, 180}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := fc.Resolution()
+	assert.Equal(t, []float64{22.5, 11.25}, res)
+	for code := uint64(0); code <= fc.Length(); code++ {
+		min, max, err := fc.DecodeBox(code)
+		if !assert.NoError(t, err) {
+			return
+		}
+		center := make([]float64, len(min))
+		for i := range min {
+			assert.InDelta(t, res[i], max[i]-min[i], 1e-9)
+			center[i] = (min[i] + max[i]) / 2
+		}
+		got, err := fc.EncodeFloat(center)
+		assert.NoError(t, err)
+		assert.Equal(t, code, got)
+		got, err = fc.EncodeFloat(min)
+		assert.NoError(t, err)
+		assert.Equal(t, code, got, "minimum %v", min)
+	}
+
+	min, max, err := fc.DecodeBox(0)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{-90, -180}, min)
+	assert.Equal(t, []float64{-67.5, -168.75}, max)
+
+	_, _, err = fc.DecodeBox(fc.Length() + 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,128 @@
+/*
+	The Gray-coded curve orders cells of the Morton curve by the binary reflected Gray code.
+
+	The code of the cell is the inverse Gray code of its Morton index,
+	so cells with consecutive codes differ in a single bit of a single coordinate.
+
+	Example: 010 & 011 -> Morton 001101 -> 001001
+*/
+package gray
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/struckoff/sfcframework/curve/morton"
+)
+
+//Curve - the representation of Gray-coded curve.
+type Curve struct {
+	z *morton.Curve
+}
+
+//New - create new gray-coded curve.
+//
+//dims - amount of curve dimensions.
+//
+//bits - size in bits of each dimension.
+func New(dims, bits uint64) (*Curve, error) {
+	if dims*bits > 64 {
+		return nil, errors.New("number of bits of code(dims * bits) must be less or equal than 64")
+	}
+	z, err := morton.New(dims, bits)
+	if err != nil {
+		return nil, err
+	}
+	return &Curve{z: z}, nil
+}
+
+//Decode returns coordinates for a given code(distance)
+//Method will return error if code(distance) exceeds the limit(2 ^ (dims * bits) - 1)
+func (c *Curve) Decode(code uint64) (coords []uint64, err error) {
+	return c.z.Decode(gray(code))
+}
+
+//DecodeWithBuffer returns coordinates for a given code(distance).
+//Method will return error if:
+//  - buffer less than number of dimensions
+//	- code(distance) exceeds the limit(2 ^ (dims * bits) - 1)
+func (c *Curve) DecodeWithBuffer(buf []uint64, code uint64) (coords []uint64, err error) {
+	return c.z.DecodeWithBuffer(buf, gray(code))
+}
+
+//Encode returns code(distance) for a given set of coordinates
+//Method will return error if any of the coordinates exceeds limit(2 ^ bits - 1)
+func (c *Curve) Encode(coords []uint64) (code uint64, err error) {
+	code, err = c.z.Encode(coords)
+	if err != nil {
+		return 0, err
+	}
+	return grayInverse(code), nil
+}
+
+//Walk calls fn for each code from first to last(inclusive) in order, coordinates are decoded into buf.
+//Only the first code is decoded from scratch, each next one flips the single bit changed by the Gray code.
+//The walk stops if fn returns false.
+//
+//Method will return error if:
+//  - buffer less than number of dimensions
+//	- first code exceeds the last one or the last code exceeds the limit(2 ^ (dims * bits) - 1)
+func (c *Curve) Walk(buf []uint64, first, last uint64, fn func(code uint64, coords []uint64) bool) error {
+	if first > last {
+		return fmt.Errorf("first code == %v exceeds last == %v", first, last)
+	}
+	if last > c.z.Length() {
+		return fmt.Errorf("code == %v exceeds limit (2^(dimensions * bits) - 1) == %v", last, c.z.Length())
+	}
+	coords, err := c.z.DecodeWithBuffer(buf, gray(first))
+	if err != nil {
+		return err
+	}
+	dims := c.z.Dimensions()
+	for code := first; fn(code, coords) && code != last; {
+		code++
+		pos := uint64(bits.TrailingZeros64(code))
+		coords[pos%dims] ^= 1 << (pos / dims)
+	}
+	return nil
+}
+
+// DimensionSize returns the maximum coordinate value in any dimension
+func (c *Curve) DimensionSize() uint64 {
+	return c.z.DimensionSize()
+}
+
+// Length returns the maximum distance along curve(code value)
+//
+// 2^(dimensions * bits) - 1
+func (c *Curve) Length() uint64 {
+	return c.z.Length()
+}
+
+//Dimensions - amount of curve dimensions
+func (c *Curve) Dimensions() uint64 {
+	return c.z.Dimensions()
+}
+
+//Bits - size in bits of each dimension
+func (c *Curve) Bits() uint64 {
+	return c.z.Bits()
+}
+
+//BlockRadix - every aligned block of cells with the side of power of 2 is visited by curve as a contiguous range of codes.
+//Cells of the block share the leading bits of the Morton index, so they share the leading bits of the code.
+func (c *Curve) BlockRadix() uint64 {
+	return 2
+}
+
+func gray(x uint64) uint64 {
+	return x ^ x>>1
+}
+
+func grayInverse(x uint64) uint64 {
+	for shift := uint(1); shift < 64; shift <<= 1 {
+		x ^= x >> shift
+	}
+	return x
+}
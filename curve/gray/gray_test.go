@@ -0,0 +1,136 @@
+package gray
+
+import (
+	"math/bits"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	type args struct {
+		dims uint64
+		bits uint64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{"2x4", args{dims: 2, bits: 4}, false},
+		{"2x32", args{dims: 2, bits: 32}, false},
+		{"2x0", args{dims: 2, bits: 0}, true},
+		{"0x4", args{dims: 0, bits: 4}, true},
+		{"3x22", args{dims: 3, bits: 22}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.args.dims, tt.args.bits)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.args.dims, c.Dimensions())
+			assert.Equal(t, tt.args.bits, c.Bits())
+			assert.Equal(t, uint64(1<<tt.args.bits-1), c.DimensionSize())
+		})
+	}
+}
+
+func TestCurve_Encode(t *testing.T) {
+	c, err := New(2, 3)
+	if !assert.NoError(t, err) {
+		return
+	}
+	//Morton index of 011 & 010 is 0b001101
+	code, err := c.Encode([]uint64{3, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0b001001), code)
+
+	coords, err := c.Decode(0b001001)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{3, 2}, coords)
+}
+
+func TestCurve_SingleBitSteps(t *testing.T) {
+	type args struct {
+		dims uint64
+		bits uint64
+	}
+	tests := []struct {
+		name string
+		args args
+	}{
+		{"1x6", args{dims: 1, bits: 6}},
+		{"2x4", args{dims: 2, bits: 4}},
+		{"3x3", args{dims: 3, bits: 3}},
+		{"4x2", args{dims: 4, bits: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.args.dims, tt.args.bits)
+			if !assert.NoError(t, err) {
+				return
+			}
+			prev, err := c.Decode(0)
+			if !assert.NoError(t, err) {
+				return
+			}
+			for code := uint64(1); code <= c.Length(); code++ {
+				coords, err := c.Decode(code)
+				if !assert.NoError(t, err) {
+					return
+				}
+				changed := 0
+				for i := range coords {
+					changed += bits.OnesCount64(coords[i] ^ prev[i])
+				}
+				if !assert.Equal(t, 1, changed, "code %v", code) {
+					return
+				}
+				got, err := c.Encode(coords)
+				if !assert.NoError(t, err) || !assert.Equal(t, code, got) {
+					return
+				}
+				prev = coords
+			}
+		})
+	}
+}
+
+func TestCurve_Walk(t *testing.T) {
+	c, err := New(3, 4)
+	if !assert.NoError(t, err) {
+		return
+	}
+	next := uint64(100)
+	err = c.Walk(make([]uint64, 3), 100, 3000, func(code uint64, coords []uint64) bool {
+		want, err := c.Decode(code)
+		assert.NoError(t, err)
+		assert.Equal(t, next, code)
+		next++
+		return assert.Equal(t, want, coords, "code %v", code)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3001), next)
+
+	assert.Error(t, c.Walk(make([]uint64, 3), 10, 5, nil))
+	assert.Error(t, c.Walk(make([]uint64, 3), 0, c.Length()+1, nil))
+	assert.Error(t, c.Walk(make([]uint64, 2), 0, 5, nil))
+}
+
+func TestCurve_Errors(t *testing.T) {
+	c, err := New(2, 4)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = c.Decode(c.Length() + 1)
+	assert.Error(t, err)
+	_, err = c.DecodeWithBuffer(make([]uint64, 1), 0)
+	assert.Error(t, err)
+	_, err = c.Encode([]uint64{1})
+	assert.Error(t, err)
+	_, err = c.Encode([]uint64{16, 0})
+	assert.Error(t, err)
+}
@@ -0,0 +1,75 @@
+package hilbert
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/struckoff/sfcframework/curve/internal/batch"
+)
+
+//EncodeWithBuffer returns code(distance) for a given set of coordinates.
+//Unlike Encode, coords are not altered, buffer is used as a scratch space instead.
+// Method will return error if:
+//
+// - buffer less than number of dimensions
+//
+// - any of the coordinates exceeds limit(2 ^ bits - 1)
+func (c *Curve) EncodeWithBuffer(buf, coords []uint64) (code uint64, err error) {
+	if len(buf) < int(c.dimensions) {
+		return 0, errors.New("buffer length less then dimensions")
+	}
+	if err := c.validateCoordinates(coords); err != nil {
+		return 0, err
+	}
+	if t := c.table(); t != nil {
+		return t.encodeIndex(coords, c.bits), nil
+	}
+	buf = buf[:c.dimensions]
+	copy(buf, coords)
+	return c.encodeGeneric(buf), nil
+}
+
+//EncodeBatch writes codes(distances) of each set of coordinates into out, coords are not altered.
+//Big batches are encoded by several goroutines.
+// Method will return error if:
+//
+// - out is shorter than coords
+//
+// - any of the coordinates exceeds limit(2 ^ bits - 1)
+func (c *Curve) EncodeBatch(coords [][]uint64, out []uint64) error {
+	if len(out) < len(coords) {
+		return fmt.Errorf("output length == %v less then number of coordinates == %v", len(out), len(coords))
+	}
+	return batch.Run(len(coords), func(from, to int) error {
+		buf := make([]uint64, c.dimensions)
+		for i := from; i < to; i++ {
+			code, err := c.EncodeWithBuffer(buf, coords[i])
+			if err != nil {
+				return fmt.Errorf("coordinates %d: %w", i, err)
+			}
+			out[i] = code
+		}
+		return nil
+	})
+}
+
+//DecodeBatch writes coordinates for each code(distance) into buffers of out.
+//Big batches are decoded by several goroutines.
+// Method will return error if:
+//
+// - out is shorter than codes or any buffer less than number of dimensions
+//
+// - any code(distance) exceeds the limit(2 ^ (dims * bits) - 1)
+func (c *Curve) DecodeBatch(codes []uint64, out [][]uint64) error {
+	if len(out) < len(codes) {
+		return fmt.Errorf("output length == %v less then number of codes == %v", len(out), len(codes))
+	}
+	return batch.Run(len(codes), func(from, to int) error {
+		for i := from; i < to; i++ {
+			if _, err := c.DecodeWithBuffer(out[i], codes[i]); err != nil {
+				return fmt.Errorf("code %d: %w", i, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,92 @@
+package hilbert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurve_EncodeWithBuffer(t *testing.T) {
+	type args struct {
+		dims uint64
+		bits uint64
+	}
+	tests := []struct {
+		name string
+		args args
+	}{
+		{"2x4 table", args{dims: 2, bits: 4}},
+		{"4x4 generic", args{dims: 4, bits: 4}},
+		{"1x40 generic", args{dims: 1, bits: 40}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.args.dims, tt.args.bits)
+			if !assert.NoError(t, err) {
+				return
+			}
+			buf := make([]uint64, c.dimensions)
+			for _, coords := range randomCoords(c, 100) {
+				orig := append([]uint64{}, coords...)
+				got, err := c.EncodeWithBuffer(buf, coords)
+				assert.NoError(t, err)
+				assert.Equal(t, orig, coords)
+				want, err := c.Encode(orig)
+				assert.NoError(t, err)
+				assert.Equal(t, want, got)
+			}
+			_, err = c.EncodeWithBuffer(nil, buf)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCurve_Batch_Allocs(t *testing.T) {
+	for _, dims := range []uint64{3, 5} {
+		c, err := New(dims, 8)
+		if !assert.NoError(t, err) {
+			return
+		}
+		coords := randomCoords(c, 1000)
+		codes := make([]uint64, len(coords))
+		encode := testing.AllocsPerRun(10, func() {
+			assert.NoError(t, c.EncodeBatch(coords, codes))
+		})
+		decode := testing.AllocsPerRun(10, func() {
+			assert.NoError(t, c.DecodeBatch(codes, coords))
+		})
+		assert.True(t, encode <= 2, "encode allocations == %v", encode)
+		assert.True(t, decode <= 2, "decode allocations == %v", decode)
+	}
+}
+
+func BenchmarkCurve_EncodeBatch(b *testing.B) {
+	type args struct {
+		dims uint64
+		bits uint64
+	}
+	benchmarks := []struct {
+		name string
+		args args
+	}{
+		{"2x16", args{dims: 2, bits: 16}},
+		{"3x21", args{dims: 3, bits: 21}},
+		{"8x8", args{dims: 8, bits: 8}},
+	}
+	for _, bm := range benchmarks {
+		c, err := New(bm.args.dims, bm.args.bits)
+		if err != nil {
+			b.Fatal(err)
+		}
+		coords := randomCoords(c, 1<<16)
+		codes := make([]uint64, len(coords))
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := c.EncodeBatch(coords, codes); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
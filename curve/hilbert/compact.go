@@ -0,0 +1,268 @@
+package hilbert
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+//Compact - the representation of Hilbert curve with different size in bits of each dimension(compact Hilbert index).
+//
+//Curve is built inside the hypercube with the side of the biggest dimension,
+//but the code keeps only bits which could be set by coordinates in range, so there is no gaps between codes.
+//The code of each cell is its rank among cells of the curve.
+//
+//NOTE: This algorithm is derived from work done by Chris Hamilton and Andrew Rau-Chaplin and published in
+//"Compact Hilbert indices: Space-filling curves for domains with unequal side lengths".
+type Compact struct {
+	dimensions uint64   //amount of curve dimensions
+	widths     []uint64 //size in bits of each dimension
+	bits       uint64   //size in bits of the biggest dimension
+	length     uint64   //sum of widths
+	maxSizes   []uint64 //maximum value of each dimension
+	maxCode    uint64   //biggest code which could be decoded by curve
+	masks      []uint64 //[level] -> dimensions which have bits on the level
+}
+
+//NewCompact - create new compact hilbert curve.
+//
+//widths - size in bits of each dimension, the sum of widths must be less or equal than 64.
+func NewCompact(widths []uint64) (*Compact, error) {
+	dims := uint64(len(widths))
+	if dims == 0 || dims > 64 {
+		return nil, errors.New("number of dimensions must be in range [1, 64]")
+	}
+	c := &Compact{
+		dimensions: dims,
+		widths:     append([]uint64{}, widths...),
+		maxSizes:   make([]uint64, dims),
+	}
+	for i, w := range widths {
+		if w == 0 {
+			return nil, errors.New("number of bits must be greater than 0")
+		}
+		if w > 64-c.length {
+			return nil, errors.New("sum of bits of dimensions must be less or equal than 64")
+		}
+		c.length += w
+		c.maxSizes[i] = 1<<w - 1
+		if w > c.bits {
+			c.bits = w
+		}
+	}
+	c.maxCode = 1<<c.length - 1
+	c.masks = make([]uint64, c.bits)
+	for lvl := range c.masks {
+		for i, w := range widths {
+			if w > uint64(lvl) {
+				c.masks[lvl] |= 1 << c.bitOf(uint64(i))
+			}
+		}
+	}
+	return c, nil
+}
+
+//Decode returns coordinates for a given code(distance).
+//
+//Method will return error if code(distance) exceeds the limit(2 ^ (sum of widths) - 1).
+func (c *Compact) Decode(code uint64) (coords []uint64, err error) {
+	if err := c.validateCode(code); err != nil {
+		return nil, err
+	}
+	return c.decode(make([]uint64, c.dimensions), code), nil
+}
+
+//DecodeWithBuffer returns coordinates for a given code(distance).
+// Method will return error if:
+//
+// - buffer less than number of dimensions
+//
+// - code(distance) exceeds the limit(2 ^ (sum of widths) - 1)
+func (c *Compact) DecodeWithBuffer(buf []uint64, code uint64) (coords []uint64, err error) {
+	if len(buf) < int(c.dimensions) {
+		return nil, errors.New("buffer length less then dimensions")
+	}
+	if err := c.validateCode(code); err != nil {
+		return nil, err
+	}
+	return c.decode(buf, code), nil
+}
+
+func (c *Compact) decode(coords []uint64, code uint64) []uint64 {
+	for i := uint64(0); i < c.dimensions; i++ {
+		coords[i] = 0
+	}
+	e, d := uint64(0), c.dimensions-1
+	pos := c.length
+	for lvl := c.bits; lvl > 0; lvl-- {
+		mu := c.rotr(c.masks[lvl-1], d+1)
+		pi := c.rotr(e, d+1) &^ mu
+		size := uint64(bits.OnesCount64(mu))
+		pos -= size
+		r := code >> pos & (1<<size - 1)
+
+		w, g := c.rankInverse(mu, pi, r, size)
+		l := c.rotl(g, d+1) ^ e
+		for i := uint64(0); i < c.dimensions; i++ {
+			coords[i] |= (l >> c.bitOf(i) & 1) << (lvl - 1)
+		}
+		e ^= c.rotl(entry(w), d+1)
+		d = (d + c.direction(w) + 1) % c.dimensions
+	}
+	return coords
+}
+
+//rankInverse restores the index inside the sub-hypercube and its gray code by the rank of the index.
+//mu - bits which are free on the level, pi - values of other bits.
+func (c *Compact) rankInverse(mu, pi, r, size uint64) (w, g uint64) {
+	j := size
+	for k := c.dimensions; k > 0; k-- {
+		bit := k - 1
+		prev := w >> k & 1
+		if mu>>bit&1 == 1 {
+			j--
+			w |= (r >> j & 1) << bit
+			g |= ((w >> bit & 1) ^ prev) << bit
+		} else {
+			g |= pi & (1 << bit)
+			w |= ((g >> bit & 1) ^ prev) << bit
+		}
+	}
+	return w, g
+}
+
+func (c *Compact) validateCode(code uint64) error {
+	if code > c.maxCode {
+		return fmt.Errorf("code == %v exceeds limit (2^(sum of bits) - 1) == %v", code, c.maxCode)
+	}
+	return nil
+}
+
+//Encode returns code(distance) for a given set of coordinates
+//
+//Method will return error if any of the coordinates exceeds limit of its dimension(2 ^ width - 1)
+func (c *Compact) Encode(coords []uint64) (code uint64, err error) {
+	if err := c.validateCoordinates(coords); err != nil {
+		return 0, err
+	}
+	e, d := uint64(0), c.dimensions-1
+	for lvl := c.bits; lvl > 0; lvl-- {
+		mu := c.rotr(c.masks[lvl-1], d+1)
+		var l uint64
+		for i := uint64(0); i < c.dimensions; i++ {
+			l |= (coords[i] >> (lvl - 1) & 1) << c.bitOf(i)
+		}
+		w := grayInverse(c.rotr(l^e, d+1))
+		for k := c.dimensions; k > 0; k-- {
+			if mu>>(k-1)&1 == 1 {
+				code = code<<1 | w>>(k-1)&1
+			}
+		}
+		e ^= c.rotl(entry(w), d+1)
+		d = (d + c.direction(w) + 1) % c.dimensions
+	}
+	return code, nil
+}
+
+func (c *Compact) validateCoordinates(coords []uint64) error {
+	if len(coords) < int(c.dimensions) {
+		return fmt.Errorf("number of coordinates == %v less then dimensions == %v", len(coords), c.dimensions)
+	}
+	for i := uint64(0); i < c.dimensions; i++ {
+		if coords[i] > c.maxSizes[i] {
+			return fmt.Errorf("coordinate == %v exceeds limit == %v", coords[i], c.maxSizes[i])
+		}
+	}
+	return nil
+}
+
+//bitOf returns position of the dimension inside the label of sub-hypercube.
+//The first dimension is the most significant, as in the transposed index.
+func (c *Compact) bitOf(dim uint64) uint64 {
+	return c.dimensions - dim - 1
+}
+
+func (c *Compact) rotr(x, k uint64) uint64 {
+	n := c.dimensions
+	k %= n
+	x &= 1<<n - 1
+	return (x>>k | x<<(n-k)) & (1<<n - 1)
+}
+
+func (c *Compact) rotl(x, k uint64) uint64 {
+	return c.rotr(x, c.dimensions-k%c.dimensions)
+}
+
+//direction returns the dimension along which the curve leaves the sub-hypercube with given index.
+func (c *Compact) direction(w uint64) uint64 {
+	switch {
+	case w == 0:
+		return 0
+	case w%2 == 0:
+		return uint64(bits.TrailingZeros64(^(w - 1))) % c.dimensions
+	default:
+		return uint64(bits.TrailingZeros64(^w)) % c.dimensions
+	}
+}
+
+//entry returns the corner where the curve enters the sub-hypercube with given index.
+func entry(w uint64) uint64 {
+	if w == 0 {
+		return 0
+	}
+	return gray((w - 1) &^ 1)
+}
+
+func gray(x uint64) uint64 {
+	return x ^ x>>1
+}
+
+func grayInverse(x uint64) uint64 {
+	for shift := uint(1); shift < 64; shift <<= 1 {
+		x ^= x >> shift
+	}
+	return x
+}
+
+// DimensionSize returns the maximum coordinate value which is valid in every dimension
+func (c *Compact) DimensionSize() uint64 {
+	min := c.maxSizes[0]
+	for _, s := range c.maxSizes[1:] {
+		if s < min {
+			min = s
+		}
+	}
+	return min
+}
+
+// DimensionSizes returns the maximum coordinate value of each dimension
+func (c *Compact) DimensionSizes() []uint64 {
+	return append([]uint64{}, c.maxSizes...)
+}
+
+// Length returns the maximum distance along curve(code value).
+//
+// 2^(sum of widths) - 1
+func (c *Compact) Length() uint64 {
+	return c.maxCode
+}
+
+//Dimensions - amount of curve dimensions
+func (c *Compact) Dimensions() uint64 {
+	return c.dimensions
+}
+
+//Bits - size in bits of the biggest dimension
+func (c *Compact) Bits() uint64 {
+	return c.bits
+}
+
+//Widths - size in bits of each dimension
+func (c *Compact) Widths() []uint64 {
+	return append([]uint64{}, c.widths...)
+}
+
+//BlockRadix - every aligned block of cells with the side of power of 2 is visited by curve as a contiguous range of codes.
+func (c *Compact) BlockRadix() uint64 {
+	return 2
+}
@@ -0,0 +1,153 @@
+package hilbert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCompact(t *testing.T) {
+	tests := []struct {
+		name    string
+		widths  []uint64
+		wantErr bool
+	}{
+		{"2 dims", []uint64{20, 6}, false},
+		{"3 dims", []uint64{1, 2, 3}, false},
+		{"64 bits", []uint64{32, 16, 16}, false},
+		{"empty", nil, true},
+		{"zero width", []uint64{3, 0}, true},
+		{"too wide", []uint64{40, 25}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCompact(tt.widths)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.widths, got.Widths())
+		})
+	}
+}
+
+func TestCompact_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		widths []uint64
+	}{
+		{"1", []uint64{5}},
+		{"3x1", []uint64{3, 1}},
+		{"1x3", []uint64{1, 3}},
+		{"4x2x3", []uint64{4, 2, 3}},
+		{"2x2x2x2", []uint64{2, 2, 2, 2}},
+		{"1x1x4x2x1", []uint64{1, 1, 4, 2, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCompact(tt.widths)
+			if !assert.NoError(t, err) {
+				return
+			}
+			buf := make([]uint64, c.Dimensions())
+			for code := uint64(0); code <= c.Length(); code++ {
+				coords, err := c.DecodeWithBuffer(buf, code)
+				if !assert.NoError(t, err) {
+					return
+				}
+				for i, s := range c.DimensionSizes() {
+					assert.True(t, coords[i] <= s)
+				}
+				got, err := c.Encode(coords)
+				if !assert.NoError(t, err) || !assert.Equal(t, code, got) {
+					return
+				}
+			}
+		})
+	}
+}
+
+//Curve with equal widths must be continuous like any Hilbert curve.
+func TestCompact_Adjacent(t *testing.T) {
+	for _, widths := range [][]uint64{{3, 3}, {2, 2, 2}, {2, 2, 2, 2}} {
+		c, err := NewCompact(widths)
+		if !assert.NoError(t, err) {
+			return
+		}
+		prev, _ := c.Decode(0)
+		for code := uint64(1); code <= c.Length(); code++ {
+			cur, _ := c.Decode(code)
+			dist := uint64(0)
+			for i := range cur {
+				if cur[i] > prev[i] {
+					dist += cur[i] - prev[i]
+				} else {
+					dist += prev[i] - cur[i]
+				}
+			}
+			assert.Equal(t, uint64(1), dist, "%v -> %v", prev, cur)
+			prev = cur
+		}
+	}
+}
+
+//Compact index keeps the order of cells of the curve in the hypercube with the side of the biggest dimension.
+func TestCompact_KeepsOrder(t *testing.T) {
+	c, err := NewCompact([]uint64{4, 2, 3})
+	if !assert.NoError(t, err) {
+		return
+	}
+	full, err := NewCompact([]uint64{4, 4, 4})
+	if !assert.NoError(t, err) {
+		return
+	}
+	var prev uint64
+	for code := uint64(0); code <= c.Length(); code++ {
+		coords, _ := c.Decode(code)
+		fc, _ := full.Encode(coords)
+		if code > 0 {
+			assert.True(t, fc > prev, "order is broken at %v", coords)
+		}
+		prev = fc
+	}
+}
+
+func TestCompact_Encode(t *testing.T) {
+	c, err := NewCompact([]uint64{2, 2})
+	if !assert.NoError(t, err) {
+		return
+	}
+	h, err := New(2, 2)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for code := uint64(0); code <= c.Length(); code++ {
+		coords, _ := h.Decode(code)
+		got, err := c.Encode(coords)
+		assert.NoError(t, err)
+		assert.Equal(t, code, got)
+	}
+
+	_, err = c.Encode([]uint64{4, 0})
+	assert.Error(t, err)
+	_, err = c.Encode([]uint64{0})
+	assert.Error(t, err)
+	_, err = c.Decode(16)
+	assert.Error(t, err)
+	_, err = c.DecodeWithBuffer([]uint64{0}, 1)
+	assert.Error(t, err)
+}
+
+func TestCompact_Getters(t *testing.T) {
+	c, err := NewCompact([]uint64{5, 2, 3})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, uint64(3), c.Dimensions())
+	assert.Equal(t, uint64(5), c.Bits())
+	assert.Equal(t, uint64(3), c.DimensionSize())
+	assert.Equal(t, []uint64{31, 3, 7}, c.DimensionSizes())
+	assert.Equal(t, uint64(1<<10-1), c.Length())
+	assert.Equal(t, uint64(2), c.BlockRadix())
+}
@@ -0,0 +1,18 @@
+package hilbert_test
+
+import (
+	"testing"
+
+	"github.com/struckoff/sfcframework/curve/curvetest"
+	"github.com/struckoff/sfcframework/curve/hilbert"
+)
+
+var factory = curvetest.Factory(hilbert.New)
+
+func TestCurve_Conformance(t *testing.T) {
+	curvetest.Run(t, factory, curvetest.Options{Adjacent: true, AltersCoords: true})
+}
+
+func TestCurve_Fuzz(t *testing.T) {
+	curvetest.Fuzz(t, factory, 3, 21, 42, 10000)
+}
@@ -15,13 +15,11 @@
 package hilbert
 
 import (
-	"encoding/binary"
 	"errors"
 	"fmt"
+	"math/bits"
 )
 
-const bitSize = 8
-
 //Curve - the representation of Hilbert curve.
 type Curve struct {
 	dimensions uint64 //amount of curve dimensions
@@ -35,11 +33,14 @@ type Curve struct {
 //
 //dims - amount of curve dimensions.
 //
-//bits - size in bits of each dimension.
+//bits - size in bits of each dimension, codes(dims * bits) must fit 64 bits(see NewWide).
 func New(dims, bits uint64) (*Curve, error) {
 	if bits <= 0 || dims <= 0 {
 		return nil, errors.New("number of bits and dimension must be greater than 0")
 	}
+	if dims*bits > 64 {
+		return nil, errors.New("number of bits of code(dims * bits) must be less or equal than 64, use NewWide for wider codes")
+	}
 	return &Curve{
 		dimensions: dims,
 		bits:       bits,
@@ -57,8 +58,10 @@ func (c *Curve) Decode(code uint64) (coords []uint64, err error) {
 		return nil, err
 	}
 	coords = make([]uint64, c.dimensions)
-	coords = c.parseIndex(coords, code)
-	return c.transpose(coords), nil
+	if t := c.table(); t != nil {
+		return t.decodeIndex(coords, code, c.bits), nil
+	}
+	return c.decodeGeneric(coords, code), nil
 }
 
 //DecodeWithBuffer returns coordinates for a given code(distance).
@@ -74,9 +77,19 @@ func (c *Curve) DecodeWithBuffer(buf []uint64, code uint64) (coords []uint64, er
 	if err := c.validateCode(code); err != nil {
 		return nil, err
 	}
-	coords = c.parseIndex(buf, code)
-	coords = c.transpose(coords)
-	return coords, nil
+	if t := c.table(); t != nil {
+		return t.decodeIndex(buf, code, c.bits), nil
+	}
+	return c.decodeGeneric(buf, code), nil
+}
+
+//decodeGeneric decodes the code by the transpose algorithm, it works for any amount of dimensions.
+func (c *Curve) decodeGeneric(buf []uint64, code uint64) []uint64 {
+	for i := uint64(0); i < c.dimensions; i++ {
+		buf[i] = 0
+	}
+	buf = c.parseIndex(buf, code)
+	return c.transpose(buf)
 }
 
 func (c *Curve) validateCode(code uint64) error {
@@ -87,27 +100,11 @@ func (c *Curve) validateCode(code uint64) error {
 }
 
 func (c *Curve) parseIndex(coords []uint64, code uint64) []uint64 {
-	var bitLen int
-
-	b := make([]byte, bitSize)
-	binary.LittleEndian.PutUint64(b, code)
-
-	for i := 0; i < bitSize; i++ {
-		if (1 << (i * bitSize)) > code {
-			bitLen = i
-			break
-		}
-	}
-
-	if bitLen > 0 {
-		b = b[:bitLen]
-	}
-	for i := 0; i < bitSize*len(b); i++ {
-		if (b[i/bitSize] & (1 << (i % bitSize))) != 0 {
-			dim := (c.length - uint64(i) - 1) % c.dimensions
-			shift := (uint64(i) / c.dimensions) % c.bits
-			coords[dim] |= 1 << shift
-		}
+	for ; code != 0; code &= code - 1 {
+		i := uint64(bits.TrailingZeros64(code))
+		dim := (c.length - i - 1) % c.dimensions
+		shift := (i / c.dimensions) % c.bits
+		coords[dim] |= 1 << shift
 	}
 	return coords
 }
@@ -121,7 +118,37 @@ func (c *Curve) Encode(coords []uint64) (code uint64, err error) {
 	if err := c.validateCoordinates(coords); err != nil {
 		return 0, err
 	}
+	if t := c.table(); t != nil {
+		return t.encodeIndex(coords, c.bits), nil
+	}
+	return c.encodeGeneric(coords), nil
+}
+
+//encodeGeneric encodes coordinates by the transpose algorithm, it works for any amount of dimensions.
+//
+//! coords are altered by method
+func (c *Curve) encodeGeneric(coords []uint64) uint64 {
+	coords = c.axesToTranspose(coords)
+
+	//h = self._transpose_to_hilbert_integer(x)
+	return c.prepareIndex(coords)
+}
+
+//table returns the state machine for the curve, or nil if there is no such for given dimensions and bits.
+func (c *Curve) table() *table {
+	if c.dimensions >= uint64(len(tables)) || tables[c.dimensions] == nil {
+		return nil
+	}
+	if t := tables[c.dimensions]; c.bits < uint64(len(t.root)) {
+		return t
+	}
+	return nil
+}
 
+//axesToTranspose converts coordinates into the transposed Hilbert index.
+//
+//! coords are altered by method
+func (c *Curve) axesToTranspose(coords []uint64) []uint64 {
 	m := uint64(1 << (c.bits - 1))
 	coordsLen := len(coords)
 	// Inverse undo excess work
@@ -150,10 +177,7 @@ func (c *Curve) Encode(coords []uint64) (code uint64, err error) {
 	for i := 0; i < coordsLen; i++ {
 		coords[i] ^= t
 	}
-
-	//h = self._transpose_to_hilbert_integer(x)
-	code = c.prepareIndex(coords)
-	return
+	return coords
 }
 
 func (c *Curve) validateCoordinates(coords []uint64) error {
@@ -199,15 +223,14 @@ func (c *Curve) transpose(coords []uint64) []uint64 {
 	return coords
 }
 
-func (c *Curve) prepareIndex(coords []uint64) uint64 {
-	tmpCoords := make([]byte, bitSize)
+func (c *Curve) prepareIndex(coords []uint64) (code uint64) {
 	bIndex := c.length - 1
 	mask := uint64(1 << (c.bits - 1))
 
 	for i := uint64(0); i < c.bits; i++ {
 		for ci := range coords {
 			if (coords[ci] & mask) != 0 {
-				tmpCoords[bIndex/bitSize] |= 1 << (bIndex % bitSize)
+				code |= 1 << bIndex
 			}
 			if bIndex > 0 {
 				bIndex--
@@ -216,7 +239,7 @@ func (c *Curve) prepareIndex(coords []uint64) uint64 {
 		mask >>= 1
 	}
 
-	return binary.LittleEndian.Uint64(tmpCoords)
+	return code
 }
 
 // DimensionSize returns the maximum coordinate value in any dimension
@@ -240,3 +263,8 @@ func (c *Curve) Dimensions() uint64 {
 func (c *Curve) Bits() uint64 {
 	return c.bits
 }
+
+//BlockRadix - every aligned block of cells with the side of power of 2 is visited by curve as a contiguous range of codes.
+func (c *Curve) BlockRadix() uint64 {
+	return 2
+}
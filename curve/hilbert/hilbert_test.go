@@ -95,7 +95,7 @@ func TestHilbertCurve_Decode(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c, _ := New(tt.fields.dimensions, tt.fields.bits)
+			c, _ := newTestCurve(tt.fields.dimensions, tt.fields.bits)
 			gotCoords, err := c.Decode(tt.args.code)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Decode() error = %v, wantErr %v", err, tt.wantErr)
@@ -212,7 +212,7 @@ func TestHilbertCurve_DecodeWithBuffer(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c, _ := New(tt.fields.dimensions, tt.fields.bits)
+			c, _ := newTestCurve(tt.fields.dimensions, tt.fields.bits)
 			gotCoords, err := c.DecodeWithBuffer(tt.args.buf, tt.args.code)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Decode() error = %v, wantErr %v", err, tt.wantErr)
@@ -302,7 +302,7 @@ func TestHilbertCurve_Encode(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c, _ := New(tt.fields.dimensions, tt.fields.bits)
+			c, _ := newTestCurve(tt.fields.dimensions, tt.fields.bits)
 			gotCode, err := c.Encode(tt.args.coords)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Encode() error = %v, wantErr %v", err, tt.wantErr)
@@ -358,8 +358,8 @@ func BenchmarkCurve_Decode_Hilbert(b *testing.B) {
 			args{dims: 32, bits: 2},
 		},
 		{
-			"32x512",
-			args{dims: 32, bits: 512},
+			"2x32",
+			args{dims: 2, bits: 32},
 		},
 	}
 	for _, bm := range benchmarks {
@@ -400,8 +400,8 @@ func BenchmarkCurve_Encode_Hilbert(b *testing.B) {
 			args{dims: 32, bits: 2},
 		},
 		{
-			"32x512",
-			args{dims: 32, bits: 512},
+			"2x32",
+			args{dims: 2, bits: 32},
 		},
 	}
 
@@ -468,17 +468,23 @@ func TestNew(t *testing.T) {
 			false,
 		},
 		{
-			"4x32",
-			args{dims: 4, bits: 32},
+			"2x32",
+			args{dims: 2, bits: 32},
 			&Curve{
-				dimensions: 4,
+				dimensions: 2,
 				bits:       32,
-				length:     128,
+				length:     64,
 				maxSize:    4294967295,
 				maxCode:    18446744073709551615,
 			},
 			false,
 		},
+		{
+			"code exceeds 64 bits",
+			args{dims: 4, bits: 32},
+			nil,
+			true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,68 @@
+package hilbert
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/struckoff/sfcframework/curve/internal/grid"
+)
+
+//AppendNeighbors appends codes of face neighbors(2 * dims) or all 3^dims - 1 neighbors of the cell to out.
+//If wrap is true, the grid is toroidal, otherwise neighbors beyond the grid are skipped.
+//Codes may repeat and include the cell itself if the dimension is shorter than 3 cells.
+//
+//Curves with 2 and 3 dimensions keep states of each level of the cell,
+//so only levels changed by the move of coordinates are encoded for each neighbor.
+func (c *Curve) AppendNeighbors(out []uint64, code uint64, wrap, all bool) ([]uint64, error) {
+	if all && c.dimensions > grid.MaxDimensions {
+		return nil, fmt.Errorf("all neighbors could be found only for curves with at most %d dimensions", grid.MaxDimensions)
+	}
+	if err := c.validateCode(code); err != nil {
+		return nil, err
+	}
+	coords := make([]uint64, c.dimensions)
+	buf := make([]uint64, c.dimensions)
+	t := c.table()
+	var states []uint8
+	if t != nil {
+		states = make([]uint8, c.bits+1)
+		states[c.bits] = t.root[c.bits]
+		t.decodeLevels(coords, states, code, c.bits)
+	} else {
+		c.decodeGeneric(coords, code)
+	}
+
+	grid.Offsets(int(c.dimensions), all, func(delta []int) {
+		changed := uint64(0) //amount of the lowest levels changed by the move
+		for i := range coords {
+			var ok bool
+			if buf[i], ok = grid.Move(coords[i], c.maxSize, 1, delta[i], wrap); !ok {
+				return
+			}
+			if l := uint64(bits.Len64(buf[i] ^ coords[i])); l > changed {
+				changed = l
+			}
+		}
+		if t == nil {
+			out = append(out, c.encodeGeneric(buf))
+			return
+		}
+		shift := changed * c.dimensions
+		out = append(out, code>>shift<<shift|t.encodeLevels(buf, states[changed], changed))
+	})
+	return out, nil
+}
+
+//encodeLevels encodes given amount of the lowest levels of coordinates starting from the state.
+func (t *table) encodeLevels(coords []uint64, s uint8, levels uint64) (code uint64) {
+	for shift := levels; shift > 0; shift-- {
+		l := uint8(0)
+		for i := uint64(0); i < t.dims; i++ {
+			l = l<<1 | uint8(coords[i]>>(shift-1)&1)
+		}
+		e := t.encode[s][l]
+		code = code<<t.dims | uint64(e&(1<<t.dims-1))
+		s = e >> t.dims
+	}
+	return code
+}
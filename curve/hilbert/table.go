@@ -0,0 +1,172 @@
+package hilbert
+
+import "sort"
+
+//tables - state machines for curves with 2 and 3 dimensions.
+var tables = [...]*table{
+	2: newTable(2, 32),
+	3: newTable(3, 21),
+}
+
+//table is a state machine which encodes and decodes the Hilbert index level by level.
+//
+//Each state is an orientation of the curve inside a block of cells.
+//On every level the state maps a label of a sub-block(bits of coordinates on this level, the first dimension is the most significant)
+//to the position of the sub-block along the curve and the state of the sub-block, and vice versa.
+//
+//Tables are derived from the transpose algorithm, so both produce identical codes.
+type table struct {
+	dims   uint64
+	encode [][]uint8 //[state][label] -> position | next state << dims
+	decode [][]uint8 //[state][position] -> label | next state << dims
+	root   []uint8   //[bits] -> state of the whole curve
+}
+
+//newTable discovers states of the curve with given amount of dimensions.
+//maxBits - the biggest size in bits of each dimension which could be encoded into uint64.
+func newTable(dims, maxBits uint64) *table {
+	t := &table{
+		dims: dims,
+		root: make([]uint8, maxBits+1),
+	}
+	states := map[uint64]uint8{}
+	var queue []tableBlock
+
+	state := func(b tableBlock) uint8 {
+		p := b.pattern()
+		if s, ok := states[p]; ok {
+			return s
+		}
+		s := uint8(len(states))
+		states[p] = s
+		queue = append(queue, b)
+		t.encode = append(t.encode, make([]uint8, 1<<dims))
+		t.decode = append(t.decode, make([]uint8, 1<<dims))
+		return s
+	}
+
+	for bits := maxBits; bits > 0; bits-- {
+		t.root[bits] = state(newTableBlock(dims, bits))
+	}
+	for i := 0; i < len(queue); i++ {
+		b := queue[i]
+		for pos, label := range b.order() {
+			child := b.child(label)
+			next := uint8(0)
+			if child.level > 0 {
+				next = state(child)
+			}
+			t.encode[i][label] = uint8(pos) | next<<dims
+			t.decode[i][pos] = uint8(label) | next<<dims
+		}
+	}
+	return t
+}
+
+//encodeIndex returns code(distance) for a given set of coordinates.
+func (t *table) encodeIndex(coords []uint64, bits uint64) (code uint64) {
+	mask := uint8(1<<t.dims - 1)
+	s := t.root[bits]
+	switch t.dims {
+	case 2:
+		x, y := coords[0], coords[1]
+		for shift := bits; shift > 0; shift-- {
+			l := uint8(x>>(shift-1)&1)<<1 | uint8(y>>(shift-1)&1)
+			e := t.encode[s][l]
+			code = code<<2 | uint64(e&mask)
+			s = e >> 2
+		}
+	case 3:
+		x, y, z := coords[0], coords[1], coords[2]
+		for shift := bits; shift > 0; shift-- {
+			l := uint8(x>>(shift-1)&1)<<2 | uint8(y>>(shift-1)&1)<<1 | uint8(z>>(shift-1)&1)
+			e := t.encode[s][l]
+			code = code<<3 | uint64(e&mask)
+			s = e >> 3
+		}
+	}
+	return code
+}
+
+//decodeIndex fills coords with coordinates for a given code(distance).
+func (t *table) decodeIndex(coords []uint64, code, bits uint64) []uint64 {
+	mask := uint8(1<<t.dims - 1)
+	s := t.root[bits]
+	switch t.dims {
+	case 2:
+		var x, y uint64
+		for shift := bits; shift > 0; shift-- {
+			e := t.decode[s][uint8(code>>((shift-1)*2))&mask]
+			x = x<<1 | uint64(e>>1&1)
+			y = y<<1 | uint64(e&1)
+			s = e >> 2
+		}
+		coords[0], coords[1] = x, y
+	case 3:
+		var x, y, z uint64
+		for shift := bits; shift > 0; shift-- {
+			e := t.decode[s][uint8(code>>((shift-1)*3))&mask]
+			x = x<<1 | uint64(e>>2&1)
+			y = y<<1 | uint64(e>>1&1)
+			z = z<<1 | uint64(e&1)
+			s = e >> 3
+		}
+		coords[0], coords[1], coords[2] = x, y, z
+	}
+	return coords
+}
+
+//tableBlock - aligned block of cells of the curve which is used to discover states.
+type tableBlock struct {
+	c      *Curve
+	origin []uint64
+	level  uint64 //side of the block is 2^level
+}
+
+func newTableBlock(dims, bits uint64) tableBlock {
+	return tableBlock{
+		c: &Curve{
+			dimensions: dims,
+			bits:       bits,
+			length:     bits * dims,
+			maxSize:    (1 << bits) - 1,
+			maxCode:    (1 << (dims * bits)) - 1,
+		},
+		origin: make([]uint64, dims),
+		level:  bits,
+	}
+}
+
+//child returns the sub-block with given label.
+func (b tableBlock) child(label int) tableBlock {
+	dims := len(b.origin)
+	origin := make([]uint64, dims)
+	for i := range origin {
+		origin[i] = b.origin[i] | uint64(label>>(dims-1-i)&1)<<(b.level-1)
+	}
+	return tableBlock{c: b.c, origin: origin, level: b.level - 1}
+}
+
+//order returns labels of sub-blocks in the order they are visited by the curve.
+func (b tableBlock) order() []int {
+	n := 1 << len(b.origin)
+	labels := make([]int, n)
+	codes := make([]uint64, n)
+	buf := make([]uint64, len(b.origin))
+	for l := 0; l < n; l++ {
+		labels[l] = l
+		copy(buf, b.child(l).origin)
+		codes[l] = b.c.prepareIndex(b.c.axesToTranspose(buf))
+	}
+	sort.Slice(labels, func(i, j int) bool { return codes[labels[i]] < codes[labels[j]] })
+	return labels
+}
+
+//pattern packs the order of sub-blocks into a number.
+//The order of visiting corners of the block defines its orientation, so it identifies the state.
+func (b tableBlock) pattern() (p uint64) {
+	for _, l := range b.order() {
+		p = p<<len(b.origin) | uint64(l)
+	}
+	return p
+}
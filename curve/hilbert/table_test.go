@@ -0,0 +1,239 @@
+package hilbert
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTable_MatchesGeneric(t *testing.T) {
+	type args struct {
+		dims uint64
+		bits uint64
+	}
+	tests := []struct {
+		name string
+		args args
+	}{
+		{"2x1", args{dims: 2, bits: 1}},
+		{"2x2", args{dims: 2, bits: 2}},
+		{"2x5", args{dims: 2, bits: 5}},
+		{"2x8", args{dims: 2, bits: 8}},
+		{"3x1", args{dims: 3, bits: 1}},
+		{"3x2", args{dims: 3, bits: 2}},
+		{"3x4", args{dims: 3, bits: 4}},
+		{"3x6", args{dims: 3, bits: 6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.args.dims, tt.args.bits)
+			if !assert.NoError(t, err) || !assert.NotNil(t, c.table()) {
+				return
+			}
+			buf := make([]uint64, c.dimensions)
+			for code := uint64(0); code <= c.maxCode; code++ {
+				want := c.decodeGeneric(make([]uint64, c.dimensions), code)
+				got := c.table().decodeIndex(buf, code, c.bits)
+				if !assert.Equal(t, want, got, "decode %v", code) {
+					return
+				}
+				if !assert.Equal(t, code, c.table().encodeIndex(got, c.bits), "encode %v", got) {
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestTable_MatchesGenericRandom(t *testing.T) {
+	type args struct {
+		dims uint64
+		bits uint64
+	}
+	tests := []struct {
+		name string
+		args args
+	}{
+		{"2x16", args{dims: 2, bits: 16}},
+		{"2x31", args{dims: 2, bits: 31}},
+		{"2x32", args{dims: 2, bits: 32}},
+		{"3x13", args{dims: 3, bits: 13}},
+		{"3x20", args{dims: 3, bits: 20}},
+		{"3x21", args{dims: 3, bits: 21}},
+	}
+	rnd := rand.New(rand.NewSource(42))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.args.dims, tt.args.bits)
+			if !assert.NoError(t, err) || !assert.NotNil(t, c.table()) {
+				return
+			}
+			coords := make([]uint64, c.dimensions)
+			for i := 0; i < 10000; i++ {
+				for d := range coords {
+					coords[d] = rnd.Uint64() & c.maxSize
+				}
+				code, err := c.Encode(coords)
+				if !assert.NoError(t, err) {
+					return
+				}
+				want := c.encodeGeneric(append([]uint64{}, coords...))
+				if !assert.Equal(t, want, code, "encode %v", coords) {
+					return
+				}
+				decoded, err := c.Decode(code)
+				if !assert.NoError(t, err) || !assert.Equal(t, coords, decoded) {
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestCurve_table(t *testing.T) {
+	type args struct {
+		dims uint64
+		bits uint64
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{"1x8", args{dims: 1, bits: 8}, false},
+		{"2x32", args{dims: 2, bits: 32}, true},
+		{"2x33", args{dims: 2, bits: 33}, false},
+		{"3x21", args{dims: 3, bits: 21}, true},
+		{"3x22", args{dims: 3, bits: 22}, false},
+		{"4x4", args{dims: 4, bits: 4}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.args.dims*tt.args.bits > 64 {
+				w, err := NewWide(tt.args.dims, tt.args.bits)
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.Equal(t, tt.want, w.c.table() != nil)
+				return
+			}
+			c, err := New(tt.args.dims, tt.args.bits)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tt.want, c.table() != nil)
+		})
+	}
+}
+
+func TestCurve_EncodeKeepsCoords(t *testing.T) {
+	c, err := New(2, 4)
+	if !assert.NoError(t, err) {
+		return
+	}
+	coords := []uint64{5, 9}
+	_, err = c.Encode(coords)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{5, 9}, coords)
+}
+
+func TestCurve_DecodeWithDirtyBuffer(t *testing.T) {
+	c, err := New(3, 4)
+	if !assert.NoError(t, err) {
+		return
+	}
+	buf := []uint64{15, 15, 15}
+	coords, err := c.DecodeWithBuffer(buf, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{0, 0, 0}, coords)
+}
+
+var benchSink uint64
+
+func BenchmarkCurve_Encode_Table(b *testing.B) {
+	type args struct {
+		dims uint64
+		bits uint64
+	}
+	benchmarks := []struct {
+		name string
+		args args
+	}{
+		{"2x16", args{dims: 2, bits: 16}},
+		{"2x32", args{dims: 2, bits: 32}},
+		{"3x10", args{dims: 3, bits: 10}},
+		{"3x21", args{dims: 3, bits: 21}},
+	}
+	for _, bm := range benchmarks {
+		c, err := New(bm.args.dims, bm.args.bits)
+		if err != nil {
+			b.Fatal(err)
+		}
+		coordsSet := randomCoords(c, 1024)
+		buf := make([]uint64, c.dimensions)
+		b.Run(bm.name+"/table", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				code, _ := c.Encode(coordsSet[i%len(coordsSet)])
+				benchSink += code
+			}
+		})
+		b.Run(bm.name+"/generic", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				copy(buf, coordsSet[i%len(coordsSet)])
+				benchSink += c.encodeGeneric(buf)
+			}
+		})
+	}
+}
+
+func BenchmarkCurve_Decode_Table(b *testing.B) {
+	type args struct {
+		dims uint64
+		bits uint64
+	}
+	benchmarks := []struct {
+		name string
+		args args
+	}{
+		{"2x16", args{dims: 2, bits: 16}},
+		{"2x32", args{dims: 2, bits: 32}},
+		{"3x10", args{dims: 3, bits: 10}},
+		{"3x21", args{dims: 3, bits: 21}},
+	}
+	for _, bm := range benchmarks {
+		c, err := New(bm.args.dims, bm.args.bits)
+		if err != nil {
+			b.Fatal(err)
+		}
+		buf := make([]uint64, c.dimensions)
+		b.Run(bm.name+"/table", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				coords, _ := c.DecodeWithBuffer(buf, uint64(i)&c.maxCode)
+				benchSink += coords[0]
+			}
+		})
+		b.Run(bm.name+"/generic", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				coords := c.decodeGeneric(buf, uint64(i)&c.maxCode)
+				benchSink += coords[0]
+			}
+		})
+	}
+}
+
+func randomCoords(c *Curve, n int) [][]uint64 {
+	rnd := rand.New(rand.NewSource(1))
+	res := make([][]uint64, n)
+	for i := range res {
+		res[i] = make([]uint64, c.dimensions)
+		for d := range res[i] {
+			res[i][d] = rnd.Uint64() & c.maxSize
+		}
+	}
+	return res
+}
@@ -0,0 +1,69 @@
+package hilbert
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+//Walk calls fn for each code from first to last(inclusive) in order, coordinates are decoded into buf.
+//The walk stops if fn returns false.
+//
+//Curves with 2 and 3 dimensions keep states of each level of the curve,
+//so only levels changed by the increment of the code are decoded.
+//Curves with other dimensions decode each code from scratch.
+// Method will return error if:
+//
+// - buffer less than number of dimensions
+//
+// - first code exceeds the last one or the last code exceeds the limit(2 ^ (dims * bits) - 1)
+func (c *Curve) Walk(buf []uint64, first, last uint64, fn func(code uint64, coords []uint64) bool) error {
+	if len(buf) < int(c.dimensions) {
+		return errors.New("buffer length less then dimensions")
+	}
+	if first > last {
+		return fmt.Errorf("first code == %v exceeds last == %v", first, last)
+	}
+	if err := c.validateCode(last); err != nil {
+		return err
+	}
+	if t := c.table(); t != nil {
+		t.walk(buf, first, last, c.bits, fn)
+		return nil
+	}
+	for code := first; ; code++ {
+		if !fn(code, c.decodeGeneric(buf, code)) || code == last {
+			return nil
+		}
+	}
+}
+
+//walk decodes consecutive codes, states of upper levels are reused while their digits are not changed.
+func (t *table) walk(coords []uint64, first, last, levels uint64, fn func(code uint64, coords []uint64) bool) {
+	states := make([]uint8, levels+1) //[level] -> state which decodes the digit of the level
+	states[levels] = t.root[levels]
+	for i := uint64(0); i < t.dims; i++ {
+		coords[i] = 0
+	}
+	t.decodeLevels(coords, states, first, levels)
+	for code := first; fn(code, coords) && code != last; {
+		code++
+		changed := (uint64(bits.Len64(code^(code-1))) + t.dims - 1) / t.dims
+		t.decodeLevels(coords, states, code, changed)
+	}
+}
+
+//decodeLevels decodes given amount of the lowest levels of the code using states of upper levels.
+func (t *table) decodeLevels(coords []uint64, states []uint8, code, levels uint64) {
+	low := uint64(1)<<levels - 1
+	for i := uint64(0); i < t.dims; i++ {
+		coords[i] &^= low
+	}
+	for shift := levels; shift > 0; shift-- {
+		e := t.decode[states[shift]][code>>((shift-1)*t.dims)&(1<<t.dims-1)]
+		for i := uint64(0); i < t.dims; i++ {
+			coords[i] |= uint64(e>>(t.dims-1-i)&1) << (shift - 1)
+		}
+		states[shift-1] = e >> t.dims
+	}
+}
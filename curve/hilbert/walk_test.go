@@ -0,0 +1,95 @@
+package hilbert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurve_Walk(t *testing.T) {
+	type args struct {
+		dims  uint64
+		bits  uint64
+		first uint64
+		last  uint64
+	}
+	tests := []struct {
+		name string
+		args args
+	}{
+		{"2x5", args{dims: 2, bits: 5, first: 0, last: 1<<10 - 1}},
+		{"3x4", args{dims: 3, bits: 4, first: 100, last: 3000}},
+		{"5x2", args{dims: 5, bits: 2, first: 1, last: 1000}},
+		{"3x21", args{dims: 3, bits: 21, first: 1<<62 - 5000, last: 1<<62 + 5000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.args.dims, tt.args.bits)
+			if !assert.NoError(t, err) {
+				return
+			}
+			buf := make([]uint64, c.dimensions)
+			for i := range buf {
+				buf[i] = c.maxSize
+			}
+			next := tt.args.first
+			err = c.Walk(buf, tt.args.first, tt.args.last, func(code uint64, coords []uint64) bool {
+				want, _ := c.Decode(code)
+				next++
+				return assert.Equal(t, want, coords, "code %v", code)
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.args.last+1, next)
+		})
+	}
+}
+
+func TestCurve_Walk_Errors(t *testing.T) {
+	c, err := New(2, 3)
+	if !assert.NoError(t, err) {
+		return
+	}
+	fn := func(code uint64, coords []uint64) bool { return true }
+	assert.Error(t, c.Walk(make([]uint64, 1), 0, 1, fn))
+	assert.Error(t, c.Walk(make([]uint64, 2), 2, 1, fn))
+	assert.Error(t, c.Walk(make([]uint64, 2), 0, 64, fn))
+}
+
+func BenchmarkCurve_Walk(b *testing.B) {
+	type args struct {
+		dims uint64
+		bits uint64
+	}
+	benchmarks := []struct {
+		name string
+		args args
+	}{
+		{"2x16", args{dims: 2, bits: 16}},
+		{"3x10", args{dims: 3, bits: 10}},
+		{"4x8", args{dims: 4, bits: 8}},
+	}
+	for _, bm := range benchmarks {
+		c, err := New(bm.args.dims, bm.args.bits)
+		if err != nil {
+			b.Fatal(err)
+		}
+		buf := make([]uint64, c.dimensions)
+		b.Run(bm.name+"/walk", func(b *testing.B) {
+			b.ReportAllocs()
+			err := c.Walk(buf, 0, uint64(b.N-1)&c.maxCode, func(code uint64, coords []uint64) bool {
+				benchSink += coords[0]
+				return true
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+		})
+		b.Run(bm.name+"/decode", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				coords, _ := c.DecodeWithBuffer(buf, uint64(i)&c.maxCode)
+				benchSink += coords[0]
+			}
+		})
+	}
+}
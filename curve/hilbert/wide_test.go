@@ -0,0 +1,278 @@
+package hilbert
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWide_EncodeWide(t *testing.T) {
+	type fields struct {
+		dimensions uint64
+		bits       uint64
+	}
+	type args struct {
+		coords []uint64
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantCode string
+		wantErr  bool
+	}{
+		{
+			"[1, 0] == 3",
+			fields{2, 1},
+			args{[]uint64{1, 0}},
+			"3",
+			false,
+		},
+		{
+			"[10, 34] == 1096",
+			fields{2, 10},
+			args{[]uint64{10, 34}},
+			"1096",
+			false,
+		},
+		{
+			"[max, 0, 0, 0] == 2^128 - 1",
+			fields{4, 32},
+			args{[]uint64{4294967295, 0, 0, 0}},
+			"340282366920938463463374607431768211455",
+			false,
+		},
+		{
+			"[1, 2, 3, 4] == 3940",
+			fields{4, 32},
+			args{[]uint64{1, 2, 3, 4}},
+			"3940",
+			false,
+		},
+		{
+			"coordinate exceeds limit",
+			fields{4, 16},
+			args{[]uint64{65536, 0, 0, 0}},
+			"",
+			true,
+		},
+		{
+			"bits exceeds limit",
+			fields{2, 65},
+			args{[]uint64{0, 0}},
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewWide(tt.fields.dimensions, tt.fields.bits)
+			if err != nil {
+				assert.True(t, tt.wantErr, err)
+				return
+			}
+			coords := append([]uint64{}, tt.args.coords...)
+			gotCode, err := c.EncodeWide(coords)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCode, gotCode.String())
+			assert.Equal(t, tt.args.coords, coords)
+		})
+	}
+}
+
+func TestWide_DecodeWide(t *testing.T) {
+	c, err := NewWide(4, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coords, err := c.DecodeWide(c.LengthWide())
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{4294967295, 0, 0, 0}, coords)
+	coords, err = c.DecodeWide(big.NewInt(3940))
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 3, 4}, coords)
+
+	_, err = c.DecodeWide(new(big.Int).Add(c.LengthWide(), big.NewInt(1)))
+	assert.Error(t, err)
+	_, err = c.DecodeWide(big.NewInt(-1))
+	assert.Error(t, err)
+}
+
+//referenceXY2D is the classic iterative algorithm of 2-dimensional Hilbert curve
+//which rotates quadrants instead of transposing bits.
+func referenceXY2D(x, y, bits uint64) *big.Int {
+	d := new(big.Int)
+	for s := uint64(1) << (bits - 1); s > 0; s >>= 1 {
+		var rx, ry uint64
+		if x&s > 0 {
+			rx = 1
+		}
+		if y&s > 0 {
+			ry = 1
+		}
+		q := new(big.Int).SetUint64(s)
+		q.Mul(q, q).Mul(q, big.NewInt(int64((3*rx)^ry)))
+		d.Add(d, q)
+		if ry == 0 {
+			if rx == 1 {
+				x = s - 1 - x&(s-1)
+				y = s - 1 - y&(s-1)
+			}
+			x, y = y, x
+		}
+		x &= s - 1
+		y &= s - 1
+	}
+	return d
+}
+
+func TestWide_Reference(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	for _, bits := range []uint64{1, 4, 33, 64} {
+		c, err := NewWide(2, bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 1000; i++ {
+			coords := []uint64{rnd.Uint64() & c.DimensionSize(), rnd.Uint64() & c.DimensionSize()}
+			want := referenceXY2D(coords[0], coords[1], bits)
+			code, err := c.EncodeWide(coords)
+			assert.NoError(t, err)
+			assert.Equal(t, want.String(), code.String(), "%v", coords)
+			got, err := c.DecodeWide(want)
+			assert.NoError(t, err)
+			assert.Equal(t, coords, got)
+		}
+	}
+}
+
+//Hilbert curve of any dimensions starts at the origin, moves to a face neighbour on every step
+//and visits each aligned block of cells with side 2^k as a contiguous range of 2^(k * dims) codes.
+func TestWide_Adjacency(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	one := big.NewInt(1)
+	for _, size := range [][2]uint64{{3, 3}, {5, 2}, {4, 32}, {9, 16}} {
+		c, err := NewWide(size[0], size[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		first, err := c.DecodeWide(new(big.Int))
+		assert.NoError(t, err)
+		assert.Equal(t, make([]uint64, size[0]), first)
+		for i := 0; i < 1000; i++ {
+			code := new(big.Int).Rand(rnd, c.LengthWide())
+			coords, err := c.DecodeWide(code)
+			assert.NoError(t, err)
+			next, err := c.DecodeWide(new(big.Int).Add(code, one))
+			assert.NoError(t, err)
+			diff := uint64(0)
+			for j := range coords {
+				if coords[j] > next[j] {
+					diff += coords[j] - next[j]
+				} else {
+					diff += next[j] - coords[j]
+				}
+			}
+			assert.Equal(t, uint64(1), diff, "code %v", code)
+
+			k := uint(rnd.Intn(int(size[1])))
+			block := new(big.Int).Rsh(code, k*uint(size[0]))
+			block.Lsh(block, k*uint(size[0]))
+			start, err := c.DecodeWide(block)
+			assert.NoError(t, err)
+			for j := range coords {
+				assert.Equal(t, coords[j]>>k, start[j]>>k, "code %v, block side 2^%v", code, k)
+			}
+		}
+	}
+}
+
+func TestWide_RoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	for _, size := range [][2]uint64{{4, 16}, {4, 64}, {16, 32}} {
+		c, err := NewWide(size[0], size[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 1000; i++ {
+			coords := make([]uint64, c.Dimensions())
+			for j := range coords {
+				coords[j] = rnd.Uint64() & c.DimensionSize()
+			}
+			code, err := c.EncodeWide(coords)
+			assert.NoError(t, err)
+			assert.True(t, code.Cmp(c.LengthWide()) <= 0)
+			got, err := c.DecodeWide(code)
+			assert.NoError(t, err)
+			assert.Equal(t, coords, got)
+		}
+	}
+}
+
+func TestWide_LengthWide(t *testing.T) {
+	c, err := NewWide(4, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := new(big.Int).SetString("340282366920938463463374607431768211455", 10)
+	assert.Equal(t, want, c.LengthWide())
+}
+
+//testCurve - the part of the curve used by test cases of 64-bit codes.
+type testCurve interface {
+	Decode(code uint64) ([]uint64, error)
+	DecodeWithBuffer(buf []uint64, code uint64) ([]uint64, error)
+	Encode(coords []uint64) (uint64, error)
+}
+
+//newTestCurve creates the curve for test cases, curves wider than 64 bits are created by NewWide.
+func newTestCurve(dims, bits uint64) (testCurve, error) {
+	if dims*bits <= 64 {
+		return New(dims, bits)
+	}
+	w, err := NewWide(dims, bits)
+	if err != nil {
+		return nil, err
+	}
+	return narrowCodes{w}, nil
+}
+
+//narrowCodes passes 64-bit codes of test cases to the wide curve.
+type narrowCodes struct {
+	*Wide
+}
+
+func (n narrowCodes) Decode(code uint64) ([]uint64, error) {
+	return n.DecodeWide(new(big.Int).SetUint64(code))
+}
+
+func (n narrowCodes) DecodeWithBuffer(buf []uint64, code uint64) ([]uint64, error) {
+	if len(buf) < int(n.Dimensions()) {
+		return nil, errors.New("buffer length less then dimensions")
+	}
+	coords, err := n.Decode(code)
+	if err != nil {
+		return nil, err
+	}
+	copy(buf, coords)
+	return buf, nil
+}
+
+func (n narrowCodes) Encode(coords []uint64) (uint64, error) {
+	code, err := n.EncodeWide(coords)
+	if err != nil {
+		return 0, err
+	}
+	if !code.IsUint64() {
+		return 0, fmt.Errorf("code == %v exceeds 64 bits", code)
+	}
+	return code.Uint64(), nil
+}
@@ -0,0 +1,49 @@
+/*
+	Batch operations of curves processed by a pool of workers.
+*/
+package batch
+
+import (
+	"runtime"
+	"sync"
+)
+
+//Threshold - the minimum amount of items in the batch which is split between several workers.
+//Smaller batches are processed by the calling goroutine.
+const Threshold = 1 << 12
+
+//Run calls fn for consecutive parts [from, to) of n items.
+//Batches with at least Threshold items are split between GOMAXPROCS workers.
+//
+//If several parts fail, the error of the first part is returned.
+func Run(n int, fn func(from, to int) error) error {
+	workers := runtime.GOMAXPROCS(0)
+	if n < Threshold || workers < 2 {
+		return fn(0, n)
+	}
+	if max := n / (Threshold / 2); workers > max {
+		workers = max
+	}
+	size := (n + workers - 1) / workers
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		from := w * size
+		to := from + size
+		if to > n {
+			to = n
+		}
+		wg.Add(1)
+		go func(w, from, to int) {
+			defer wg.Done()
+			errs[w] = fn(from, to)
+		}(w, from, to)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
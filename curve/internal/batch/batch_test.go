@@ -0,0 +1,49 @@
+package batch
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+	}{
+		{"empty", 0},
+		{"small", 10},
+		{"threshold", Threshold},
+		{"large", Threshold*7 + 13},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := make([]int32, tt.n)
+			err := Run(tt.n, func(from, to int) error {
+				for i := from; i < to; i++ {
+					atomic.AddInt32(&seen[i], 1)
+				}
+				return nil
+			})
+			assert.NoError(t, err)
+			for i := range seen {
+				if !assert.Equal(t, int32(1), seen[i], "item %d", i) {
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestRun_Error(t *testing.T) {
+	first := errors.New("first")
+	err := Run(Threshold*8, func(from, to int) error {
+		if from == 0 {
+			return first
+		}
+		return errors.New("other")
+	})
+	assert.Equal(t, first, err)
+}
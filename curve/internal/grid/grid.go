@@ -0,0 +1,60 @@
+/*
+	Helpers to move over the grid of cells of curves.
+*/
+package grid
+
+//MaxDimensions - the maximum amount of dimensions for which all 3^dims - 1 neighbors could be enumerated.
+const MaxDimensions = 12
+
+//Offsets calls fn for offsets of neighbor cells, each value of delta is -1, 0 or 1.
+//If all is false, only face neighbors(2 * dims) are visited, otherwise all 3^dims - 1 neighbors.
+//delta is reused between calls.
+func Offsets(dims int, all bool, fn func(delta []int)) {
+	delta := make([]int, dims)
+	if !all {
+		for i := range delta {
+			for _, d := range [...]int{-1, 1} {
+				delta[i] = d
+				fn(delta)
+			}
+			delta[i] = 0
+		}
+		return
+	}
+	for {
+		i := 0
+		for ; i < dims; i++ {
+			//odometer 0 -> 1 -> -1 -> 0
+			if delta[i] == 0 {
+				delta[i] = 1
+				break
+			}
+			if delta[i] == 1 {
+				delta[i] = -1
+				break
+			}
+			delta[i] = 0
+		}
+		if i == dims {
+			return
+		}
+		fn(delta)
+	}
+}
+
+//Move returns the coordinate moved by delta steps inside the dimension with coordinates [0, max].
+//The coordinate must be a multiple of the step and max + 1 must be a multiple of the step too.
+//If wrap is true, the dimension is a ring, otherwise ok value is false if the coordinate leaves the dimension.
+func Move(x, max, step uint64, delta int, wrap bool) (res uint64, ok bool) {
+	switch {
+	case delta > 0 && x > max-step:
+		return 0, wrap
+	case delta < 0 && x < step:
+		return max - step + 1, wrap
+	case delta > 0:
+		return x + step, true
+	case delta < 0:
+		return x - step, true
+	}
+	return x, true
+}
@@ -0,0 +1,66 @@
+package grid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOffsets(t *testing.T) {
+	tests := []struct {
+		name string
+		dims int
+		all  bool
+		want [][]int
+	}{
+		{"face 1", 1, false, [][]int{{-1}, {1}}},
+		{"face 2", 2, false, [][]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}},
+		{"all 1", 1, true, [][]int{{1}, {-1}}},
+		{"all 2", 2, true, [][]int{{1, 0}, {-1, 0}, {0, 1}, {1, 1}, {-1, 1}, {0, -1}, {1, -1}, {-1, -1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]int
+			Offsets(tt.dims, tt.all, func(delta []int) {
+				got = append(got, append([]int{}, delta...))
+			})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	n := 0
+	Offsets(4, true, func(delta []int) { n++ })
+	assert.Equal(t, 80, n)
+}
+
+func TestMove(t *testing.T) {
+	tests := []struct {
+		name   string
+		x      uint64
+		step   uint64
+		delta  int
+		wrap   bool
+		want   uint64
+		wantOk bool
+	}{
+		{"inc", 3, 1, 1, false, 4, true},
+		{"dec", 3, 1, -1, false, 2, true},
+		{"stay", 3, 1, 0, false, 3, true},
+		{"inc border", 7, 1, 1, false, 0, false},
+		{"dec border", 0, 1, -1, false, 0, false},
+		{"inc wrap", 7, 1, 1, true, 0, true},
+		{"dec wrap", 0, 1, -1, true, 7, true},
+		{"step inc", 4, 4, 1, false, 0, false},
+		{"step dec", 4, 4, -1, false, 0, true},
+		{"step dec wrap", 0, 4, -1, true, 4, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Move(tt.x, 7, tt.step, tt.delta, tt.wrap)
+			assert.Equal(t, tt.wantOk, ok)
+			if ok {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package moore_test
+
+import (
+	"testing"
+
+	"github.com/struckoff/sfcframework/curve/curvetest"
+	"github.com/struckoff/sfcframework/curve/moore"
+)
+
+var factory = curvetest.Factory(moore.New)
+
+func TestCurve_Conformance(t *testing.T) {
+	curvetest.Run(t, factory, curvetest.Options{Adjacent: true, Closed: true})
+}
+
+func TestCurve_Fuzz(t *testing.T) {
+	curvetest.Fuzz(t, factory, 2, 16, 42, 10000)
+}
@@ -0,0 +1,234 @@
+/*
+	The Moore curve is the closed variant of the Hilbert curve:
+	the last cell of the curve is adjacent to the first one, so the curve is a ring.
+
+	The square is split into four quadrants, each quadrant is filled by a Hilbert curve
+	of a lower order. Quadrants are visited clockwise starting from the bottom left one,
+	the curve starts and ends in the middle of the bottom side of the square.
+
+	Example(2 bits):
+		6  7  8  9
+		5  4 11 10
+		2  3 12 13
+		1  0 15 14
+*/
+package moore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/struckoff/sfcframework/curve/hilbert"
+)
+
+//quadrants - [quadrant] -> position of the quadrant inside the square.
+var quadrants = [4][2]uint64{{0, 0}, {0, 1}, {1, 1}, {1, 0}}
+
+//Curve - the representation of Moore curve.
+type Curve struct {
+	bits    uint64         //size in bits of each dimension
+	maxSize uint64         //maximum value of each dimension
+	maxCode uint64         //biggest code which could be decoded by curve
+	sub     *hilbert.Curve //curve of each quadrant, nil if the quadrant is a single cell
+	syms    [4]symmetry    //[quadrant] -> orientation of the curve inside the quadrant
+}
+
+//New - create new moore curve.
+//
+//dims - amount of curve dimensions, only 2 dimensions are supported.
+//
+//bits - size in bits of each dimension, must be in range [1, 32].
+func New(dims, bits uint64) (*Curve, error) {
+	if dims != 2 {
+		return nil, errors.New("number of dimensions must be 2")
+	}
+	if bits == 0 || bits > 32 {
+		return nil, errors.New("number of bits must be in range [1, 32]")
+	}
+	c := &Curve{
+		bits:    bits,
+		maxSize: 1<<bits - 1,
+		maxCode: 1<<(2*bits) - 1,
+	}
+	if bits == 1 {
+		return c, nil
+	}
+	sub, err := hilbert.New(2, bits-1)
+	if err != nil {
+		return nil, err
+	}
+	c.sub = sub
+	end, err := sub.Decode(sub.Length())
+	if err != nil {
+		return nil, err
+	}
+	//Left quadrants go up along the right side, right quadrants go down along the left side.
+	m := sub.DimensionSize()
+	for q := range c.syms {
+		from, to := [2]uint64{m, 0}, [2]uint64{m, m}
+		if quadrants[q][0] == 1 {
+			from, to = [2]uint64{0, m}, [2]uint64{0, 0}
+		}
+		sym, ok := findSymmetry(m, [2]uint64{end[0], end[1]}, from, to)
+		if !ok {
+			return nil, errors.New("unsupported orientation of hilbert curve")
+		}
+		c.syms[q] = sym
+	}
+	return c, nil
+}
+
+//Decode returns coordinates for a given code(distance)
+//Method will return error if code(distance) exceeds the limit(2 ^ (2 * bits) - 1)
+func (c *Curve) Decode(code uint64) (coords []uint64, err error) {
+	if err := c.validateCode(code); err != nil {
+		return nil, err
+	}
+	return c.decode(make([]uint64, 2), code), nil
+}
+
+//DecodeWithBuffer returns coordinates for a given code(distance).
+//Method will return error if:
+//  - buffer less than number of dimensions
+//	- code(distance) exceeds the limit(2 ^ (2 * bits) - 1)
+func (c *Curve) DecodeWithBuffer(buf []uint64, code uint64) (coords []uint64, err error) {
+	if len(buf) < 2 {
+		return nil, errors.New("buffer length less then dimensions")
+	}
+	if err := c.validateCode(code); err != nil {
+		return nil, err
+	}
+	return c.decode(buf, code), nil
+}
+
+func (c *Curve) validateCode(code uint64) error {
+	if code > c.maxCode {
+		return fmt.Errorf("code == %v exceeds limit (2^(dimensions * bits) - 1) == %v", code, c.maxCode)
+	}
+	return nil
+}
+
+func (c *Curve) decode(coords []uint64, code uint64) []uint64 {
+	shift := 2 * (c.bits - 1)
+	q := code >> shift
+	var x, y uint64
+	if c.sub != nil {
+		local, _ := c.sub.DecodeWithBuffer(coords, code&(1<<shift-1))
+		x, y = c.syms[q].apply(c.sub.DimensionSize(), local[0], local[1])
+	}
+	coords[0] = quadrants[q][0]<<(c.bits-1) | x
+	coords[1] = quadrants[q][1]<<(c.bits-1) | y
+	return coords
+}
+
+//Encode returns code(distance) for a given set of coordinates
+//Method will return error if any of the coordinates exceeds limit(2 ^ bits - 1)
+func (c *Curve) Encode(coords []uint64) (code uint64, err error) {
+	if err := c.validateCoordinates(coords); err != nil {
+		return 0, err
+	}
+	qx, qy := coords[0]>>(c.bits-1), coords[1]>>(c.bits-1)
+	q := uint64(0)
+	for i := range quadrants {
+		if quadrants[i] == [2]uint64{qx, qy} {
+			q = uint64(i)
+		}
+	}
+	shift := 2 * (c.bits - 1)
+	if c.sub == nil {
+		return q << shift, nil
+	}
+	m := c.sub.DimensionSize()
+	x, y := c.syms[q].invert(m, coords[0]&m, coords[1]&m)
+	inner, err := c.sub.Encode([]uint64{x, y})
+	if err != nil {
+		return 0, err
+	}
+	return q<<shift | inner, nil
+}
+
+func (c *Curve) validateCoordinates(coords []uint64) error {
+	if len(coords) < 2 {
+		return fmt.Errorf("number of coordinates == %v less then dimensions == %v", len(coords), 2)
+	}
+	for _, coord := range coords[:2] {
+		if coord > c.maxSize {
+			return fmt.Errorf("coordinate == %v exceeds limit == %v", coord, c.maxSize)
+		}
+	}
+	return nil
+}
+
+//DimensionSize returns the maximum coordinate value in any dimension
+func (c *Curve) DimensionSize() uint64 {
+	return c.maxSize
+}
+
+//Length returns the maximum distance along curve(code value)
+//
+//2^(2 * bits) - 1
+func (c *Curve) Length() uint64 {
+	return c.maxCode
+}
+
+//Dimensions - amount of curve dimensions
+func (c *Curve) Dimensions() uint64 {
+	return 2
+}
+
+//Bits - size in bits of each dimension
+func (c *Curve) Bits() uint64 {
+	return c.bits
+}
+
+//BlockRadix - every aligned block of cells with the side of power of 2 is visited by curve as a contiguous range of codes.
+func (c *Curve) BlockRadix() uint64 {
+	return 2
+}
+
+//symmetry - one of 8 symmetries of the square, coordinates are swapped first and flipped after.
+type symmetry struct {
+	swap, flipX, flipY bool
+}
+
+//apply moves the point inside the square with the side m+1.
+func (s symmetry) apply(m, x, y uint64) (uint64, uint64) {
+	if s.swap {
+		x, y = y, x
+	}
+	if s.flipX {
+		x = m - x
+	}
+	if s.flipY {
+		y = m - y
+	}
+	return x, y
+}
+
+//invert reverts apply.
+func (s symmetry) invert(m, x, y uint64) (uint64, uint64) {
+	if s.flipX {
+		x = m - x
+	}
+	if s.flipY {
+		y = m - y
+	}
+	if s.swap {
+		x, y = y, x
+	}
+	return x, y
+}
+
+//findSymmetry returns the symmetry which moves the curve starting at the origin and ending at end
+//to the curve starting at from and ending at to.
+func findSymmetry(m uint64, end, from, to [2]uint64) (symmetry, bool) {
+	for i := 0; i < 8; i++ {
+		s := symmetry{swap: i&4 != 0, flipX: i&2 != 0, flipY: i&1 != 0}
+		fx, fy := s.apply(m, 0, 0)
+		tx, ty := s.apply(m, end[0], end[1])
+		if [2]uint64{fx, fy} == from && [2]uint64{tx, ty} == to {
+			return s, true
+		}
+	}
+	return symmetry{}, false
+}
@@ -0,0 +1,142 @@
+package moore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	type args struct {
+		dims uint64
+		bits uint64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{"2x1", args{dims: 2, bits: 1}, false},
+		{"2x32", args{dims: 2, bits: 32}, false},
+		{"2x0", args{dims: 2, bits: 0}, true},
+		{"2x33", args{dims: 2, bits: 33}, true},
+		{"1x4", args{dims: 1, bits: 4}, true},
+		{"3x4", args{dims: 3, bits: 4}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.args.dims, tt.args.bits)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(2), c.Dimensions())
+			assert.Equal(t, tt.args.bits, c.Bits())
+			assert.Equal(t, uint64(1<<tt.args.bits-1), c.DimensionSize())
+			assert.Equal(t, uint64(1<<(2*tt.args.bits)-1), c.Length())
+		})
+	}
+}
+
+func TestCurve_Encode(t *testing.T) {
+	c, err := New(2, 2)
+	if !assert.NoError(t, err) {
+		return
+	}
+	//rows from the top to the bottom
+	want := [4][4]uint64{
+		{6, 7, 8, 9},
+		{5, 4, 11, 10},
+		{2, 3, 12, 13},
+		{1, 0, 15, 14},
+	}
+	for row := range want {
+		for x := range want[row] {
+			code, err := c.Encode([]uint64{uint64(x), uint64(3 - row)})
+			assert.NoError(t, err)
+			assert.Equal(t, want[row][x], code, "coords %v", []uint64{uint64(x), uint64(3 - row)})
+		}
+	}
+}
+
+func TestCurve_Ring(t *testing.T) {
+	for bits := uint64(1); bits <= 6; bits++ {
+		c, err := New(2, bits)
+		if !assert.NoError(t, err) {
+			return
+		}
+		seen := map[[2]uint64]bool{}
+		prev, err := c.Decode(c.Length())
+		if !assert.NoError(t, err) {
+			return
+		}
+		prev = append([]uint64{}, prev...)
+		buf := make([]uint64, 2)
+		for code := uint64(0); code <= c.Length(); code++ {
+			coords, err := c.DecodeWithBuffer(buf, code)
+			if !assert.NoError(t, err) {
+				return
+			}
+			if !assert.Equal(t, uint64(1), distance(prev, coords), "bits %v, code %v", bits, code) {
+				return
+			}
+			key := [2]uint64{coords[0], coords[1]}
+			assert.False(t, seen[key], "bits %v, coords %v are visited twice", bits, coords)
+			seen[key] = true
+
+			got, err := c.Encode(coords)
+			if !assert.NoError(t, err) || !assert.Equal(t, code, got) {
+				return
+			}
+			copy(prev, coords)
+		}
+	}
+}
+
+func TestCurve_Errors(t *testing.T) {
+	c, err := New(2, 4)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = c.Decode(c.Length() + 1)
+	assert.Error(t, err)
+	_, err = c.DecodeWithBuffer(make([]uint64, 1), 0)
+	assert.Error(t, err)
+	_, err = c.DecodeWithBuffer(make([]uint64, 2), c.Length()+1)
+	assert.Error(t, err)
+	_, err = c.Encode([]uint64{1})
+	assert.Error(t, err)
+	_, err = c.Encode([]uint64{16, 0})
+	assert.Error(t, err)
+}
+
+func TestCurve_Wide(t *testing.T) {
+	c, err := New(2, 32)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, code := range []uint64{0, 1, 1 << 62, 1<<63 + 12345, c.Length() - 1, c.Length()} {
+		coords, err := c.Decode(code)
+		if !assert.NoError(t, err) {
+			return
+		}
+		got, err := c.Encode(coords)
+		assert.NoError(t, err)
+		assert.Equal(t, code, got)
+	}
+	first, _ := c.Decode(0)
+	last, _ := c.Decode(c.Length())
+	assert.Equal(t, uint64(1), distance(first, last))
+}
+
+func distance(a, b []uint64) (d uint64) {
+	for i := range a {
+		if a[i] > b[i] {
+			d += a[i] - b[i]
+		} else {
+			d += b[i] - a[i]
+		}
+	}
+	return d
+}
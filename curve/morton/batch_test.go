@@ -0,0 +1,64 @@
+package morton
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurve_Batch(t *testing.T) {
+	c, err := New(3, 10)
+	if !assert.NoError(t, err) {
+		return
+	}
+	rnd := rand.New(rand.NewSource(42))
+	coords := make([][]uint64, 10000)
+	for i := range coords {
+		coords[i] = []uint64{rnd.Uint64() & c.maxSize, rnd.Uint64() & c.maxSize, rnd.Uint64() & c.maxSize}
+	}
+	codes := make([]uint64, len(coords))
+	if !assert.NoError(t, c.EncodeBatch(coords, codes)) {
+		return
+	}
+	out := make([][]uint64, len(codes))
+	for i := range out {
+		out[i] = make([]uint64, 3)
+		want, _ := c.Encode(coords[i])
+		assert.Equal(t, want, codes[i])
+	}
+	assert.NoError(t, c.DecodeBatch(codes, out))
+	assert.Equal(t, coords, out)
+
+	small := coords[:1000]
+	encode := testing.AllocsPerRun(10, func() {
+		assert.NoError(t, c.EncodeBatch(small, codes))
+	})
+	decode := testing.AllocsPerRun(10, func() {
+		assert.NoError(t, c.DecodeBatch(codes[:1000], out))
+	})
+	assert.True(t, encode <= 1, "encode allocations == %v", encode)
+	assert.True(t, decode <= 1, "decode allocations == %v", decode)
+
+	assert.Error(t, c.EncodeBatch(coords, codes[:1]))
+	assert.Error(t, c.DecodeBatch(codes, out[:1]))
+}
+
+func BenchmarkCurve_EncodeBatch(b *testing.B) {
+	c, err := New(3, 20)
+	if err != nil {
+		b.Fatal(err)
+	}
+	rnd := rand.New(rand.NewSource(1))
+	coords := make([][]uint64, 1<<16)
+	for i := range coords {
+		coords[i] = []uint64{rnd.Uint64() & c.maxSize, rnd.Uint64() & c.maxSize, rnd.Uint64() & c.maxSize}
+	}
+	codes := make([]uint64, len(coords))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := c.EncodeBatch(coords, codes); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package morton
+
+import "fmt"
+
+//BigMin returns the smallest code greater than the given one which is inside the box(BIGMIN).
+//Box is given by the minimum and maximum(inclusive) coordinates in each dimension.
+//
+//It allows a scan over codes ordered by the curve to skip runs of codes outside the box.
+//ok value represents whether such code exists or not.
+//
+//Algorithm is described in "Multidimensional Range Search in Dynamically Balanced Trees"
+//by H. Tropf and H. Herzog.
+func (c *Curve) BigMin(code uint64, min, max []uint64) (next uint64, ok bool, err error) {
+	zmin, zmax, err := c.boxCodes(min, max)
+	if err != nil {
+		return 0, false, err
+	}
+	if code >= zmax {
+		return 0, false, nil
+	}
+	code++
+	if code <= zmin {
+		return zmin, true, nil
+	}
+	if c.inBox(code, zmin, zmax) {
+		return code, true, nil
+	}
+
+	var bigmin uint64
+	for pos := int(c.dimensions*c.bits) - 1; pos >= 0; pos-- {
+		dim := uint64(pos) % c.dimensions
+		switch c.bitsAt(pos, code, zmin, zmax) {
+		case 0b001:
+			bigmin = c.load10(zmin, pos, dim)
+			zmax = c.load01(zmax, pos, dim)
+		case 0b011:
+			return zmin, true, nil
+		case 0b100:
+			return bigmin, true, nil
+		case 0b101:
+			zmin = c.load10(zmin, pos, dim)
+		}
+	}
+	return bigmin, true, nil
+}
+
+//LitMax returns the biggest code less than the given one which is inside the box(LITMAX).
+//Box is given by the minimum and maximum(inclusive) coordinates in each dimension.
+//
+//It allows a backward scan over codes ordered by the curve to skip runs of codes outside the box.
+//ok value represents whether such code exists or not.
+func (c *Curve) LitMax(code uint64, min, max []uint64) (prev uint64, ok bool, err error) {
+	zmin, zmax, err := c.boxCodes(min, max)
+	if err != nil {
+		return 0, false, err
+	}
+	if code <= zmin {
+		return 0, false, nil
+	}
+	code--
+	if code >= zmax {
+		return zmax, true, nil
+	}
+	if c.inBox(code, zmin, zmax) {
+		return code, true, nil
+	}
+
+	var litmax uint64
+	for pos := int(c.dimensions*c.bits) - 1; pos >= 0; pos-- {
+		dim := uint64(pos) % c.dimensions
+		switch c.bitsAt(pos, code, zmin, zmax) {
+		case 0b001:
+			zmax = c.load01(zmax, pos, dim)
+		case 0b011:
+			return litmax, true, nil
+		case 0b100:
+			return zmax, true, nil
+		case 0b101:
+			litmax = c.load01(zmax, pos, dim)
+			zmin = c.load10(zmin, pos, dim)
+		}
+	}
+	return litmax, true, nil
+}
+
+//boxCodes validates the box and returns codes of its minimum and maximum corners.
+func (c *Curve) boxCodes(min, max []uint64) (zmin, zmax uint64, err error) {
+	if err := c.validateCoordinates(min); err != nil {
+		return 0, 0, err
+	}
+	if err := c.validateCoordinates(max); err != nil {
+		return 0, 0, err
+	}
+	for i := uint64(0); i < c.dimensions; i++ {
+		if min[i] > max[i] {
+			return 0, 0, fmt.Errorf("minimum coordinate == %v exceeds maximum == %v", min[i], max[i])
+		}
+		zmin |= c.split(min[i]) << i
+		zmax |= c.split(max[i]) << i
+	}
+	return zmin, zmax, nil
+}
+
+//inBox checks if the code is inside the box given by codes of its corners.
+//Interleaved coordinates keep the order, so they are compared without decoding.
+func (c *Curve) inBox(code, zmin, zmax uint64) bool {
+	for i := uint64(0); i < c.dimensions; i++ {
+		mask := c.dimensionMask(i)
+		if code&mask < zmin&mask || code&mask > zmax&mask {
+			return false
+		}
+	}
+	return true
+}
+
+//bitsAt returns bits of code, zmin and zmax at the position as a 3-bit number.
+func (c *Curve) bitsAt(pos int, code, zmin, zmax uint64) uint8 {
+	return uint8((code>>pos&1)<<2 | (zmin>>pos&1)<<1 | zmax>>pos&1)
+}
+
+//load10 sets the bit at the position to 1 and lower bits of the same dimension to 0 (1000...).
+func (c *Curve) load10(code uint64, pos int, dim uint64) uint64 {
+	lower := c.dimensionMask(dim) & (1<<pos - 1)
+	return code&^lower | 1<<pos
+}
+
+//load01 sets the bit at the position to 0 and lower bits of the same dimension to 1 (0111...).
+func (c *Curve) load01(code uint64, pos int, dim uint64) uint64 {
+	lower := c.dimensionMask(dim) & (1<<pos - 1)
+	return code&^(1<<pos) | lower
+}
+
+//dimensionMask returns bits of the code which belong to the dimension.
+func (c *Curve) dimensionMask(dim uint64) uint64 {
+	return c.split(c.maxSize) << dim
+}
@@ -0,0 +1,234 @@
+package morton
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//bruteBox returns sorted codes of all cells inside the box.
+func bruteBox(c *Curve, min, max []uint64) []uint64 {
+	var res []uint64
+	for code := uint64(0); code <= c.Length(); code++ {
+		coords, _ := c.Decode(code)
+		inside := true
+		for i := range coords {
+			if coords[i] < min[i] || coords[i] > max[i] {
+				inside = false
+			}
+		}
+		if inside {
+			res = append(res, code)
+		}
+	}
+	return res
+}
+
+func randomBox(rnd *rand.Rand, c *Curve) (min, max []uint64) {
+	min = make([]uint64, c.dimensions)
+	max = make([]uint64, c.dimensions)
+	for i := range min {
+		a := uint64(rnd.Int63n(int64(c.maxSize) + 1))
+		b := uint64(rnd.Int63n(int64(c.maxSize) + 1))
+		if a > b {
+			a, b = b, a
+		}
+		min[i], max[i] = a, b
+	}
+	return min, max
+}
+
+func TestCurve_BigMin_LitMax(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	for _, size := range [][2]uint64{{1, 6}, {2, 4}, {3, 3}, {4, 2}} {
+		c, err := New(size[0], size[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for n := 0; n < 30; n++ {
+			min, max := randomBox(rnd, c)
+			box := bruteBox(c, min, max)
+			for code := uint64(0); code <= c.Length(); code++ {
+				next, ok, err := c.BigMin(code, min, max)
+				assert.NoError(t, err)
+				wantNext, wantOk := uint64(0), false
+				for _, b := range box {
+					if b > code {
+						wantNext, wantOk = b, true
+						break
+					}
+				}
+				assert.Equal(t, wantOk, ok, "BigMin(%v) in box %v %v", code, min, max)
+				assert.Equal(t, wantNext, next, "BigMin(%v) in box %v %v", code, min, max)
+
+				prev, ok, err := c.LitMax(code, min, max)
+				assert.NoError(t, err)
+				wantPrev, wantOk := uint64(0), false
+				for i := len(box) - 1; i >= 0; i-- {
+					if box[i] < code {
+						wantPrev, wantOk = box[i], true
+						break
+					}
+				}
+				assert.Equal(t, wantOk, ok, "LitMax(%v) in box %v %v", code, min, max)
+				assert.Equal(t, wantPrev, prev, "LitMax(%v) in box %v %v", code, min, max)
+			}
+		}
+	}
+}
+
+func TestCurve_BigMin(t *testing.T) {
+	type args struct {
+		code     uint64
+		min, max []uint64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    uint64
+		wantOk  bool
+		wantErr bool
+	}{
+		{
+			"jump over the gap",
+			args{60, []uint64{3, 5}, []uint64{5, 10}},
+			133,
+			true,
+			false,
+		},
+		{
+			"before the box",
+			args{0, []uint64{3, 5}, []uint64{5, 10}},
+			39,
+			true,
+			false,
+		},
+		{
+			"inside the box",
+			args{50, []uint64{3, 5}, []uint64{5, 10}},
+			51,
+			true,
+			false,
+		},
+		{
+			"after the box",
+			args{200, []uint64{3, 5}, []uint64{5, 10}},
+			0,
+			false,
+			false,
+		},
+		{
+			"min exceeds max",
+			args{19, []uint64{5, 5}, []uint64{3, 10}},
+			0,
+			false,
+			true,
+		},
+		{
+			"coordinate exceeds limit",
+			args{19, []uint64{3, 5}, []uint64{5, 16}},
+			0,
+			false,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := New(2, 4)
+			got, ok, err := c.BigMin(tt.args.code, tt.args.min, tt.args.max)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCurve_LitMax(t *testing.T) {
+	type args struct {
+		code     uint64
+		min, max []uint64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    uint64
+		wantOk  bool
+		wantErr bool
+	}{
+		{
+			"jump over the gap",
+			args{133, []uint64{3, 5}, []uint64{5, 10}},
+			59,
+			true,
+			false,
+		},
+		{
+			"after the box",
+			args{255, []uint64{3, 5}, []uint64{5, 10}},
+			153,
+			true,
+			false,
+		},
+		{
+			"before the box",
+			args{39, []uint64{3, 5}, []uint64{5, 10}},
+			0,
+			false,
+			false,
+		},
+		{
+			"not enough coordinates",
+			args{36, []uint64{3}, []uint64{5, 10}},
+			0,
+			false,
+			true,
+		},
+		{
+			"wide curve",
+			args{36, []uint64{3, 5, 0, 0, 0}, []uint64{5, 10, 0, 0, 0}},
+			0,
+			false,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := New(2, 4)
+			if len(tt.args.min) == 5 {
+				//codes of the curve do not fit 64 bits, such curves are created by NewWide which has no LitMax
+				_, err := New(5, 16)
+				assert.Error(t, err)
+				_, err = NewWide(5, 16)
+				assert.NoError(t, err)
+				return
+			}
+			got, ok, err := c.LitMax(tt.args.code, tt.args.min, tt.args.max)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func BenchmarkCurve_BigMin(b *testing.B) {
+	c, err := New(2, 32)
+	if err != nil {
+		b.Fatal(err)
+	}
+	min := []uint64{1 << 20, 1 << 21}
+	max := []uint64{1<<30 + 12345, 1<<31 + 54321}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = c.BigMin(uint64(i)<<20, min, max)
+	}
+}
@@ -0,0 +1,18 @@
+package morton_test
+
+import (
+	"testing"
+
+	"github.com/struckoff/sfcframework/curve/curvetest"
+	"github.com/struckoff/sfcframework/curve/morton"
+)
+
+var factory = curvetest.Factory(morton.New)
+
+func TestCurve_Conformance(t *testing.T) {
+	curvetest.Run(t, factory, curvetest.Options{})
+}
+
+func TestCurve_Fuzz(t *testing.T) {
+	curvetest.Fuzz(t, factory, 3, 21, 42, 10000)
+}
@@ -25,11 +25,14 @@ type Curve struct {
 //
 //dims - amount of curve dimensions.
 //
-//bits - size in bits of each dimension.
+//bits - size in bits of each dimension, codes(dims * bits) must fit 64 bits(see NewWide).
 func New(dims, bits uint64) (*Curve, error) {
 	if bits <= 0 || dims <= 0 {
 		return nil, errors.New("number of bits and dimension must be greater than 0")
 	}
+	if dims*bits > 64 {
+		return nil, errors.New("number of bits of code(dims * bits) must be less or equal than 64, use NewWide for wider codes")
+	}
 
 	mc := &Curve{
 		dimensions: dims,
@@ -90,10 +93,11 @@ func (c *Curve) compact(x uint64) uint64 {
 	//x = (x ^ (x >> 4)) & 0x00ff00ff
 	//x = (x ^ (x >> 8)) & 0x0000ffff
 
-	x &= c.masksArray[len(c.masksArray)-1]
-	for i := 0; i < len(c.masksArray)-1; i++ {
-		//TODO may be "1 << i" should be pregenerated
-		x = (x ^ (x >> (1 << i))) & (c.masksArray[len(c.masksArray)-2-i])
+	//reverse steps of split
+	last := len(c.masksArray) - 1
+	x &= c.masksArray[last]
+	for i := last; i > 0; i-- {
+		x = (x ^ (x >> c.lshiftsArray[i])) & c.masksArray[i-1]
 	}
 
 	return x
@@ -189,3 +193,8 @@ func (c *Curve) Dimensions() uint64 {
 func (c *Curve) Bits() uint64 {
 	return c.bits
 }
+
+//BlockRadix - every aligned block of cells with the side of power of 2 is visited by curve as a contiguous range of codes.
+func (c *Curve) BlockRadix() uint64 {
+	return 2
+}
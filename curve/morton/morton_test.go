@@ -70,7 +70,7 @@ func TestMortonCurve_Decode(t *testing.T) {
 			"math.MaxInt32 == [65535, 32767]",
 			fields{
 				2,
-				100,
+				64,
 			},
 			args{
 				math.MaxInt32,
@@ -84,7 +84,7 @@ func TestMortonCurve_Decode(t *testing.T) {
 			"math.MaxInt64 == [4294967295, 2147483647]",
 			fields{
 				2,
-				100,
+				64,
 			},
 			args{
 				math.MaxInt64,
@@ -98,7 +98,7 @@ func TestMortonCurve_Decode(t *testing.T) {
 			"6442450941 == [131071, 32766]",
 			fields{
 				2,
-				100,
+				64,
 			},
 			args{
 				6442450941,
@@ -123,7 +123,7 @@ func TestMortonCurve_Decode(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c, err := New(tt.fields.dimensions, tt.fields.bits)
+			c, err := newTestCurve(tt.fields.dimensions, tt.fields.bits)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -212,7 +212,7 @@ func TestMortonCurve_Encode(t *testing.T) {
 			"math.MaxInt32 == [65535, 32767]",
 			fields{
 				2,
-				100,
+				64,
 			},
 			args{
 				[]uint64{
@@ -226,7 +226,7 @@ func TestMortonCurve_Encode(t *testing.T) {
 			"math.MaxInt64 == [4294967295, 2147483647]",
 			fields{
 				2,
-				100,
+				64,
 			},
 			args{
 				[]uint64{
@@ -240,7 +240,7 @@ func TestMortonCurve_Encode(t *testing.T) {
 			"6442450941 == [131071, 32766]",
 			fields{
 				2,
-				100,
+				64,
 			},
 			args{
 				[]uint64{
@@ -265,7 +265,7 @@ func TestMortonCurve_Encode(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c, err := New(tt.fields.dimensions, tt.fields.bits)
+			c, err := newTestCurve(tt.fields.dimensions, tt.fields.bits)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -341,7 +341,7 @@ func TestHilbertCurve_DecodeWithBuffer(t *testing.T) {
 			false,
 		},
 		{
-			"MaxInt64 == [4095, 4096, 0, 0, 0, 0, 0, 0, 0, 0]",
+			"MaxInt64 == [8191, 8191, 8191, 4095, 4095, 0, 0, 0, 0, 0]",
 			fields{
 				5,
 				64,
@@ -351,7 +351,7 @@ func TestHilbertCurve_DecodeWithBuffer(t *testing.T) {
 				make([]uint64, 10),
 			},
 			[]uint64{
-				1, 1, 1, 1, 1, 0, 0, 0, 0, 0,
+				8191, 8191, 8191, 4095, 4095, 0, 0, 0, 0, 0,
 			},
 			false,
 		},
@@ -384,7 +384,7 @@ func TestHilbertCurve_DecodeWithBuffer(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			c, _ := New(tt.fields.dimensions, tt.fields.bits)
+			c, _ := newTestCurve(tt.fields.dimensions, tt.fields.bits)
 			gotCoords, err := c.DecodeWithBuffer(tt.args.buf, tt.args.code)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Decode() error = %v, wantErr %v", err, tt.wantErr)
@@ -396,6 +396,34 @@ func TestHilbertCurve_DecodeWithBuffer(t *testing.T) {
 	}
 }
 
+//Decoding must reverse encoding for any number of dimensions, not only for 2.
+func TestMortonCurve_Decode_Dimensions(t *testing.T) {
+	c, err := New(3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//bits of [1, 2, 3] interleaved from the lowest: 101 110 -> 0b110101
+	coords, err := c.Decode(53)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 3}, coords)
+
+	for _, f := range []struct{ dims, bits uint64 }{{3, 4}, {4, 3}, {5, 2}, {7, 1}} {
+		c, err := New(f.dims, f.bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for code := uint64(0); code <= c.maxCode; code++ {
+			coords, err := c.Decode(code)
+			if !assert.NoError(t, err) {
+				return
+			}
+			got, err := c.Encode(coords)
+			assert.NoError(t, err)
+			assert.Equal(t, code, got, "%v dimensions, %v bits", f.dims, f.bits)
+		}
+	}
+}
+
 func BenchmarkCurve_Decode(b *testing.B) {
 	log.SetOutput(ioutil.Discard)
 	c, err := New(2, 10)
@@ -434,8 +462,8 @@ func BenchmarkCurve_Decode_Morton(b *testing.B) {
 			args{dims: 32, bits: 2},
 		},
 		{
-			"32x512",
-			args{dims: 32, bits: 512},
+			"2x32",
+			args{dims: 2, bits: 32},
 		},
 	}
 	for _, bm := range benchmarks {
@@ -476,8 +504,8 @@ func BenchmarkCurve_Encode_Morton(b *testing.B) {
 			args{dims: 32, bits: 2},
 		},
 		{
-			"32x512",
-			args{dims: 32, bits: 512},
+			"2x32",
+			args{dims: 2, bits: 32},
 		},
 	}
 
@@ -554,26 +582,22 @@ func TestNew(t *testing.T) {
 			false,
 		},
 		{
-			"4x32",
-			args{dims: 4, bits: 32},
+			"2x32",
+			args{dims: 2, bits: 32},
 			&Curve{
-				dimensions: 4,
+				dimensions: 2,
 				bits:       32,
-				length:     96,
+				length:     32,
 				masksArray: []uint64{
 					0xffffffff,
-					0x3fffff,
-					0x3ff800000007ff,
-					0xf80007c0003f,
-					0xc0380700c03807,
-					0x843084308430843,
-					0x909090909090909,
-					0x1111111111111111,
+					0xffff0000ffff,
+					0xff00ff00ff00ff,
+					0xf0f0f0f0f0f0f0f,
+					0x3333333333333333,
+					0x5555555555555555,
 				},
 				lshiftsArray: []uint64{
 					0,
-					1 << 6,
-					1 << 5,
 					1 << 4,
 					1 << 3,
 					1 << 2,
@@ -585,6 +609,12 @@ func TestNew(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"code exceeds 64 bits",
+			args{dims: 4, bits: 32},
+			nil,
+			true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,56 @@
+package morton
+
+import (
+	"fmt"
+
+	"github.com/struckoff/sfcframework/curve/internal/grid"
+)
+
+//AppendNeighbors appends codes of face neighbors(2 * dims) or all 3^dims - 1 neighbors of the cell to out.
+//If wrap is true, the grid is toroidal, otherwise neighbors beyond the grid are skipped.
+//Codes may repeat and include the cell itself if the dimension is shorter than 3 cells.
+//
+//Coordinates are moved without decoding: bits of the dimension are incremented or decremented
+//as a dilated integer, while bits of other dimensions are kept.
+func (c *Curve) AppendNeighbors(out []uint64, code uint64, wrap, all bool) ([]uint64, error) {
+	if all && c.dimensions > grid.MaxDimensions {
+		return nil, fmt.Errorf("all neighbors could be found only for curves with at most %d dimensions", grid.MaxDimensions)
+	}
+	if err := c.validateCode(code); err != nil {
+		return nil, err
+	}
+	//parts[dim][delta + 1] - bits of the dimension moved by delta
+	parts := make([][3]uint64, c.dimensions)
+	valid := make([][3]bool, c.dimensions)
+	for dim := uint64(0); dim < c.dimensions; dim++ {
+		for delta := -1; delta <= 1; delta++ {
+			parts[dim][delta+1], valid[dim][delta+1] = c.step(code, dim, delta, wrap)
+		}
+	}
+	grid.Offsets(int(c.dimensions), all, func(delta []int) {
+		n := uint64(0)
+		for dim, d := range delta {
+			if !valid[dim][d+1] {
+				return
+			}
+			n |= parts[dim][d+1]
+		}
+		out = append(out, n)
+	})
+	return out, nil
+}
+
+//step returns bits of the dimension of the code moved by delta(-1, 0 or 1),
+//ok value is false if the coordinate leaves the grid and wrap is false.
+func (c *Curve) step(code, dim uint64, delta int, wrap bool) (part uint64, ok bool) {
+	mask := c.dimensionMask(dim)
+	part = code & mask
+	switch {
+	case delta > 0:
+		//bits of other dimensions are filled, so the carry passes through them
+		return ((part | ^mask) + 1<<dim) & mask, part != mask || wrap
+	case delta < 0:
+		return (part - 1<<dim) & mask, part != 0 || wrap
+	}
+	return part, true
+}
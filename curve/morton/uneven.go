@@ -0,0 +1,190 @@
+package morton
+
+import (
+	"errors"
+	"fmt"
+)
+
+//Uneven - the representation of Morton curve with different size in bits of each dimension.
+//
+//Bits are interleaved level by level starting from the least significant one,
+//dimensions which are shorter than the level are skipped.
+//If all dimensions have the same size, codes are equal to codes of Curve.
+//
+//Example: 010 & 1 -> 0110
+type Uneven struct {
+	dimensions uint64   //amount of curve dimensions
+	widths     []uint64 //size in bits of each dimension
+	bits       uint64   //size in bits of the biggest dimension
+	maxSizes   []uint64 //maximum value of each dimension
+	maxCode    uint64   //biggest code which could be decoded by curve
+	masks      []uint64 //[dimension] -> bits of the code which belong to the dimension
+}
+
+//NewUneven - create new morton curve with different size of dimensions.
+//
+//widths - size in bits of each dimension, the sum of widths must be less or equal than 64.
+func NewUneven(widths []uint64) (*Uneven, error) {
+	dims := uint64(len(widths))
+	if dims == 0 {
+		return nil, errors.New("number of dimensions must be greater than 0")
+	}
+	c := &Uneven{
+		dimensions: dims,
+		widths:     append([]uint64{}, widths...),
+		maxSizes:   make([]uint64, dims),
+		masks:      make([]uint64, dims),
+	}
+	length := uint64(0)
+	for i, w := range widths {
+		if w == 0 {
+			return nil, errors.New("number of bits must be greater than 0")
+		}
+		if w > 64-length {
+			return nil, errors.New("sum of bits of dimensions must be less or equal than 64")
+		}
+		length += w
+		c.maxSizes[i] = 1<<w - 1
+		if w > c.bits {
+			c.bits = w
+		}
+	}
+	c.maxCode = 1<<length - 1
+
+	pos := uint64(0)
+	for bit := uint64(0); bit < c.bits; bit++ {
+		for i, w := range widths {
+			if bit < w {
+				c.masks[i] |= 1 << pos
+				pos++
+			}
+		}
+	}
+	return c, nil
+}
+
+//Decode returns coordinates for a given code(distance)
+//Method will return error if code(distance) exceeds the limit(2 ^ (sum of widths) - 1)
+func (c *Uneven) Decode(code uint64) (coords []uint64, err error) {
+	if err := c.validateCode(code); err != nil {
+		return nil, err
+	}
+	return c.compacter(make([]uint64, c.dimensions), code), nil
+}
+
+//DecodeWithBuffer returns coordinates for a given code(distance).
+//Method will return error if:
+//  - buffer less than number of dimensions
+//	- code(distance) exceeds the limit(2 ^ (sum of widths) - 1)
+func (c *Uneven) DecodeWithBuffer(buf []uint64, code uint64) (coords []uint64, err error) {
+	if len(buf) < int(c.dimensions) {
+		return nil, errors.New("buffer length less then dimensions")
+	}
+	if err := c.validateCode(code); err != nil {
+		return nil, err
+	}
+	return c.compacter(buf, code), nil
+}
+
+func (c *Uneven) validateCode(code uint64) error {
+	if code > c.maxCode {
+		return fmt.Errorf("code == %v exceeds limit (2^(sum of bits) - 1) == %v", code, c.maxCode)
+	}
+	return nil
+}
+
+func (c *Uneven) compacter(coords []uint64, code uint64) []uint64 {
+	for i := uint64(0); i < c.dimensions; i++ {
+		coords[i] = extract(code, c.masks[i])
+	}
+	return coords
+}
+
+//Encode returns code(distance) for a given set of coordinates
+//Method will return error if any of the coordinates exceeds limit of its dimension(2 ^ width - 1)
+func (c *Uneven) Encode(coords []uint64) (code uint64, err error) {
+	if err := c.validateCoordinates(coords); err != nil {
+		return 0, err
+	}
+	for i := uint64(0); i < c.dimensions; i++ {
+		code |= deposit(coords[i], c.masks[i])
+	}
+	return code, nil
+}
+
+func (c *Uneven) validateCoordinates(coords []uint64) error {
+	if len(coords) < int(c.dimensions) {
+		return fmt.Errorf("number of coordinates == %v less then dimensions == %v", len(coords), c.dimensions)
+	}
+	for i := uint64(0); i < c.dimensions; i++ {
+		if coords[i] > c.maxSizes[i] {
+			return fmt.Errorf("coordinate == %v exceeds limit == %v", coords[i], c.maxSizes[i])
+		}
+	}
+	return nil
+}
+
+//deposit places low bits of x into set bits of the mask.
+func deposit(x, mask uint64) (res uint64) {
+	for ; mask != 0; mask &= mask - 1 {
+		if x&1 == 1 {
+			res |= mask & -mask
+		}
+		x >>= 1
+	}
+	return res
+}
+
+//extract gathers bits of x placed in set bits of the mask into low bits.
+func extract(x, mask uint64) (res uint64) {
+	for bit := uint64(1); mask != 0; mask &= mask - 1 {
+		if x&(mask&-mask) != 0 {
+			res |= bit
+		}
+		bit <<= 1
+	}
+	return res
+}
+
+// DimensionSize returns the maximum coordinate value which is valid in every dimension
+func (c *Uneven) DimensionSize() uint64 {
+	min := c.maxSizes[0]
+	for _, s := range c.maxSizes[1:] {
+		if s < min {
+			min = s
+		}
+	}
+	return min
+}
+
+// DimensionSizes returns the maximum coordinate value of each dimension
+func (c *Uneven) DimensionSizes() []uint64 {
+	return append([]uint64{}, c.maxSizes...)
+}
+
+// Length returns the maximum distance along curve(code value)
+//
+// 2^(sum of widths) - 1
+func (c *Uneven) Length() uint64 {
+	return c.maxCode
+}
+
+//Dimensions - amount of curve dimensions
+func (c *Uneven) Dimensions() uint64 {
+	return c.dimensions
+}
+
+//Bits - size in bits of the biggest dimension
+func (c *Uneven) Bits() uint64 {
+	return c.bits
+}
+
+//Widths - size in bits of each dimension
+func (c *Uneven) Widths() []uint64 {
+	return append([]uint64{}, c.widths...)
+}
+
+//BlockRadix - every aligned block of cells with the side of power of 2 is visited by curve as a contiguous range of codes.
+func (c *Uneven) BlockRadix() uint64 {
+	return 2
+}
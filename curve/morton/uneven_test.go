@@ -0,0 +1,118 @@
+package morton
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUneven(t *testing.T) {
+	tests := []struct {
+		name    string
+		widths  []uint64
+		wantErr bool
+	}{
+		{"2 dims", []uint64{20, 6}, false},
+		{"64 bits", []uint64{32, 16, 16}, false},
+		{"empty", nil, true},
+		{"zero width", []uint64{3, 0}, true},
+		{"too wide", []uint64{40, 25}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewUneven(tt.widths)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.widths, got.Widths())
+		})
+	}
+}
+
+func TestUneven_Encode(t *testing.T) {
+	tests := []struct {
+		name   string
+		widths []uint64
+		coords []uint64
+		want   uint64
+	}{
+		{"example", []uint64{3, 1}, []uint64{2, 1}, 6},
+		{"max", []uint64{3, 1}, []uint64{7, 1}, 15},
+		{"long tail", []uint64{1, 4}, []uint64{1, 8}, 17},
+		{"3 dims", []uint64{2, 1, 3}, []uint64{3, 1, 5}, 0b101111},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewUneven(tt.widths)
+			if !assert.NoError(t, err) {
+				return
+			}
+			got, err := c.Encode(tt.coords)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			coords, err := c.Decode(got)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.coords, coords)
+		})
+	}
+}
+
+func TestUneven_MatchesCurve(t *testing.T) {
+	u, err := NewUneven([]uint64{3, 3, 3})
+	if !assert.NoError(t, err) {
+		return
+	}
+	c, err := New(3, 3)
+	if !assert.NoError(t, err) {
+		return
+	}
+	buf := make([]uint64, 3)
+	for code := uint64(0); code <= c.Length(); code++ {
+		want, _ := c.Decode(code)
+		got, err := u.DecodeWithBuffer(buf, code)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+		ucode, err := u.Encode(got)
+		assert.NoError(t, err)
+		assert.Equal(t, code, ucode)
+	}
+}
+
+func TestUneven_RoundTrip(t *testing.T) {
+	u, err := NewUneven([]uint64{1, 4, 2})
+	if !assert.NoError(t, err) {
+		return
+	}
+	seen := map[[3]uint64]bool{}
+	for code := uint64(0); code <= u.Length(); code++ {
+		coords, err := u.Decode(code)
+		if !assert.NoError(t, err) {
+			return
+		}
+		seen[[3]uint64{coords[0], coords[1], coords[2]}] = true
+		got, err := u.Encode(coords)
+		assert.NoError(t, err)
+		assert.Equal(t, code, got)
+	}
+	assert.Len(t, seen, int(u.Length()+1))
+
+	_, err = u.Encode([]uint64{2, 0, 0})
+	assert.Error(t, err)
+	_, err = u.Decode(u.Length() + 1)
+	assert.Error(t, err)
+}
+
+func TestUneven_Getters(t *testing.T) {
+	c, err := NewUneven([]uint64{5, 2, 3})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, uint64(3), c.Dimensions())
+	assert.Equal(t, uint64(5), c.Bits())
+	assert.Equal(t, uint64(3), c.DimensionSize())
+	assert.Equal(t, []uint64{31, 3, 7}, c.DimensionSizes())
+	assert.Equal(t, uint64(1<<10-1), c.Length())
+	assert.Equal(t, uint64(2), c.BlockRadix())
+}
@@ -0,0 +1,35 @@
+package morton
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+//Walk calls fn for each code from first to last(inclusive) in order, coordinates are decoded into buf.
+//Only the first code is decoded from scratch, each next one flips bits of coordinates changed by the increment of the code.
+//The walk stops if fn returns false.
+//
+//Method will return error if:
+//  - buffer less than number of dimensions
+//	- first code exceeds the last one or the last code exceeds the limit(2 ^ (dims * bits) - 1)
+func (c *Curve) Walk(buf []uint64, first, last uint64, fn func(code uint64, coords []uint64) bool) error {
+	if len(buf) < int(c.dimensions) {
+		return errors.New("buffer length less then dimensions")
+	}
+	if first > last {
+		return fmt.Errorf("first code == %v exceeds last == %v", first, last)
+	}
+	if err := c.validateCode(last); err != nil {
+		return err
+	}
+	coords := c.compacter(buf, first)
+	for code := first; fn(code, coords) && code != last; {
+		code++
+		for flipped := code ^ (code - 1); flipped != 0; flipped &= flipped - 1 {
+			pos := uint64(bits.TrailingZeros64(flipped))
+			coords[pos%c.dimensions] ^= 1 << (pos / c.dimensions)
+		}
+	}
+	return nil
+}
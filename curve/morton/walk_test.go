@@ -0,0 +1,78 @@
+package morton
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurve_Walk(t *testing.T) {
+	type args struct {
+		dims  uint64
+		bits  uint64
+		first uint64
+		last  uint64
+	}
+	tests := []struct {
+		name string
+		args args
+	}{
+		{"2x5", args{dims: 2, bits: 5, first: 0, last: 1<<10 - 1}},
+		{"3x4", args{dims: 3, bits: 4, first: 100, last: 3000}},
+		{"5x2", args{dims: 5, bits: 2, first: 1, last: 1000}},
+		{"2x32", args{dims: 2, bits: 32, first: 1<<64 - 5000, last: 1<<64 - 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.args.dims, tt.args.bits)
+			if !assert.NoError(t, err) {
+				return
+			}
+			next := tt.args.first
+			err = c.Walk(make([]uint64, c.dimensions), tt.args.first, tt.args.last, func(code uint64, coords []uint64) bool {
+				want, _ := c.Decode(code)
+				next++
+				return assert.Equal(t, want, coords, "code %v", code)
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.args.last+1, next)
+		})
+	}
+}
+
+func TestCurve_Walk_Errors(t *testing.T) {
+	c, err := New(2, 3)
+	if !assert.NoError(t, err) {
+		return
+	}
+	fn := func(code uint64, coords []uint64) bool { return true }
+	assert.Error(t, c.Walk(make([]uint64, 1), 0, 1, fn))
+	assert.Error(t, c.Walk(make([]uint64, 2), 2, 1, fn))
+	assert.Error(t, c.Walk(make([]uint64, 2), 0, 64, fn))
+}
+
+func BenchmarkCurve_Walk(b *testing.B) {
+	c, err := New(3, 20)
+	if err != nil {
+		b.Fatal(err)
+	}
+	buf := make([]uint64, c.dimensions)
+	var sink uint64
+	b.Run("walk", func(b *testing.B) {
+		b.ReportAllocs()
+		err := c.Walk(buf, 0, uint64(b.N-1), func(code uint64, coords []uint64) bool {
+			sink += coords[0]
+			return true
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	})
+	b.Run("decode", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			coords, _ := c.DecodeWithBuffer(buf, uint64(i))
+			sink += coords[0]
+		}
+	})
+}
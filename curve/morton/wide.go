@@ -0,0 +1,93 @@
+package morton
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+//Wide - the representation of Morton curve which codes are not limited by 64 bits.
+//Codes are arbitrary-precision integers, so the curve works for any dims * bits.
+type Wide struct {
+	c *Curve
+}
+
+//NewWide - create new morton curve with wide codes.
+//
+//dims - amount of curve dimensions.
+//
+//bits - size in bits of each dimension, must be less or equal than 64.
+func NewWide(dims, bits uint64) (*Wide, error) {
+	if bits <= 0 || dims <= 0 {
+		return nil, errors.New("number of bits and dimension must be greater than 0")
+	}
+	if bits > 64 {
+		return nil, errors.New("number of bits must be less or equal than 64")
+	}
+	return &Wide{c: &Curve{
+		dimensions: dims,
+		bits:       bits,
+		maxSize:    (1 << bits) - 1,
+	}}, nil
+}
+
+//EncodeWide returns code(distance) for a given set of coordinates as an arbitrary-precision integer.
+//
+//Method will return error if any of the coordinates exceeds limit(2 ^ bits - 1)
+func (w *Wide) EncodeWide(coords []uint64) (code *big.Int, err error) {
+	c := w.c
+	if err := c.validateCoordinates(coords); err != nil {
+		return nil, err
+	}
+	code = new(big.Int)
+	for dim := uint64(0); dim < c.dimensions; dim++ {
+		for bit := uint64(0); bit < c.bits; bit++ {
+			if coords[dim]&(1<<bit) != 0 {
+				code.SetBit(code, int(bit*c.dimensions+dim), 1)
+			}
+		}
+	}
+	return code, nil
+}
+
+//DecodeWide returns coordinates for a given arbitrary-precision code(distance).
+//
+//Method will return error if code(distance) is negative or exceeds the limit(2 ^ (dims * bits) - 1).
+func (w *Wide) DecodeWide(code *big.Int) (coords []uint64, err error) {
+	c := w.c
+	if code.Sign() < 0 || code.BitLen() > int(c.bits*c.dimensions) {
+		return nil, fmt.Errorf("code == %v exceeds limit (2^(dimensions * bits) - 1) == %v", code, w.LengthWide())
+	}
+	coords = make([]uint64, c.dimensions)
+	for i := 0; i < code.BitLen(); i++ {
+		if code.Bit(i) != 0 {
+			coords[uint64(i)%c.dimensions] |= 1 << (uint64(i) / c.dimensions)
+		}
+	}
+	return coords, nil
+}
+
+//LengthWide returns the maximum distance along curve as an arbitrary-precision integer.
+//
+// 2^(dimensions * bits) - 1
+func (w *Wide) LengthWide() *big.Int {
+	c := w.c
+	one := big.NewInt(1)
+	l := new(big.Int).Lsh(one, uint(c.bits*c.dimensions))
+	return l.Sub(l, one)
+}
+
+// DimensionSize returns the maximum coordinate value in any dimension
+func (w *Wide) DimensionSize() uint64 {
+	return w.c.maxSize
+}
+
+//Dimensions - amount of curve dimensions
+func (w *Wide) Dimensions() uint64 {
+	return w.c.dimensions
+}
+
+//Bits - size in bits of each dimension
+func (w *Wide) Bits() uint64 {
+	return w.c.bits
+}
@@ -0,0 +1,221 @@
+package morton
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWide_EncodeWide(t *testing.T) {
+	type fields struct {
+		dimensions uint64
+		bits       uint64
+	}
+	type args struct {
+		coords []uint64
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantCode string
+		wantErr  bool
+	}{
+		{
+			"[1, 1] == 3",
+			fields{2, 1},
+			args{[]uint64{1, 1}},
+			"3",
+			false,
+		},
+		{
+			"[40, 2] == 1096",
+			fields{2, 10},
+			args{[]uint64{40, 2}},
+			"1096",
+			false,
+		},
+		{
+			"[max, max, max, max] == 2^128 - 1",
+			fields{4, 32},
+			args{[]uint64{4294967295, 4294967295, 4294967295, 4294967295}},
+			"340282366920938463463374607431768211455",
+			false,
+		},
+		{
+			"[0, 0, 0, 2^63] == 2^255",
+			fields{4, 64},
+			args{[]uint64{0, 0, 0, 1 << 63}},
+			"57896044618658097711785492504343953926634992332820282019728792003956564819968",
+			false,
+		},
+		{
+			"[2^31, 2, 3, 4] == 2^124 + 2148",
+			fields{4, 32},
+			args{[]uint64{1 << 31, 2, 3, 4}},
+			"21267647932558653966460912964485515364",
+			false,
+		},
+		{
+			"coordinate exceeds limit",
+			fields{4, 16},
+			args{[]uint64{65536, 0, 0, 0}},
+			"",
+			true,
+		},
+		{
+			"bits exceeds limit",
+			fields{2, 65},
+			args{[]uint64{0, 0}},
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewWide(tt.fields.dimensions, tt.fields.bits)
+			if err != nil {
+				assert.True(t, tt.wantErr, err)
+				return
+			}
+			gotCode, err := c.EncodeWide(tt.args.coords)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCode, gotCode.String())
+		})
+	}
+}
+
+func TestWide_DecodeWide(t *testing.T) {
+	c, err := NewWide(4, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coords, err := c.DecodeWide(new(big.Int).Lsh(big.NewInt(1), 255))
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{0, 0, 0, 1 << 63}, coords)
+
+	_, err = c.DecodeWide(new(big.Int).Add(c.LengthWide(), big.NewInt(1)))
+	assert.Error(t, err)
+	_, err = c.DecodeWide(big.NewInt(-1))
+	assert.Error(t, err)
+}
+
+//referenceWide interleaves bits of coordinates from the most significant one,
+//each level appends one bit of every dimension to the code.
+func referenceWide(coords []uint64, bits uint64) *big.Int {
+	dims := uint(len(coords))
+	code := new(big.Int)
+	for bit := int(bits) - 1; bit >= 0; bit-- {
+		code.Lsh(code, dims)
+		level := int64(0)
+		for dim := range coords {
+			level |= int64(coords[dim]>>uint(bit)&1) << uint(dim)
+		}
+		code.Add(code, big.NewInt(level))
+	}
+	return code
+}
+
+func TestWide_Reference(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	for _, size := range [][2]uint64{{2, 4}, {3, 5}, {5, 3}, {4, 32}, {16, 64}} {
+		c, err := NewWide(size[0], size[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 1000; i++ {
+			coords := make([]uint64, c.Dimensions())
+			for j := range coords {
+				coords[j] = rnd.Uint64() & c.DimensionSize()
+			}
+			want := referenceWide(coords, c.Bits())
+			code, err := c.EncodeWide(coords)
+			assert.NoError(t, err)
+			assert.Equal(t, want.String(), code.String(), "%v", coords)
+			got, err := c.DecodeWide(want)
+			assert.NoError(t, err)
+			assert.Equal(t, coords, got)
+		}
+	}
+}
+
+func TestWide_RoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	for _, size := range [][2]uint64{{4, 16}, {4, 64}, {16, 32}} {
+		c, err := NewWide(size[0], size[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 1000; i++ {
+			coords := make([]uint64, c.Dimensions())
+			for j := range coords {
+				coords[j] = rnd.Uint64() & c.DimensionSize()
+			}
+			code, err := c.EncodeWide(coords)
+			assert.NoError(t, err)
+			assert.True(t, code.Cmp(c.LengthWide()) <= 0)
+			got, err := c.DecodeWide(code)
+			assert.NoError(t, err)
+			assert.Equal(t, coords, got)
+		}
+	}
+}
+
+//testCurve - the part of the curve used by test cases of 64-bit codes.
+type testCurve interface {
+	Decode(code uint64) ([]uint64, error)
+	DecodeWithBuffer(buf []uint64, code uint64) ([]uint64, error)
+	Encode(coords []uint64) (uint64, error)
+}
+
+//newTestCurve creates the curve for test cases, curves wider than 64 bits are created by NewWide.
+func newTestCurve(dims, bits uint64) (testCurve, error) {
+	if dims*bits <= 64 {
+		return New(dims, bits)
+	}
+	w, err := NewWide(dims, bits)
+	if err != nil {
+		return nil, err
+	}
+	return narrowCodes{w}, nil
+}
+
+//narrowCodes passes 64-bit codes of test cases to the wide curve.
+type narrowCodes struct {
+	*Wide
+}
+
+func (n narrowCodes) Decode(code uint64) ([]uint64, error) {
+	return n.DecodeWide(new(big.Int).SetUint64(code))
+}
+
+func (n narrowCodes) DecodeWithBuffer(buf []uint64, code uint64) ([]uint64, error) {
+	if len(buf) < int(n.Dimensions()) {
+		return nil, errors.New("buffer length less then dimensions")
+	}
+	coords, err := n.Decode(code)
+	if err != nil {
+		return nil, err
+	}
+	copy(buf, coords)
+	return buf, nil
+}
+
+func (n narrowCodes) Encode(coords []uint64) (uint64, error) {
+	code, err := n.EncodeWide(coords)
+	if err != nil {
+		return 0, err
+	}
+	if !code.IsUint64() {
+		return 0, fmt.Errorf("code == %v exceeds 64 bits", code)
+	}
+	return code.Uint64(), nil
+}
@@ -0,0 +1,91 @@
+package curve
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/struckoff/sfcframework/curve/internal/grid"
+)
+
+//Bounds - behaviour of neighbor lookup on the border of the grid.
+type Bounds int
+
+const (
+	Clamp Bounds = iota //Clamp - coordinates are clamped by the grid, so there is no neighbors beyond the border
+	Wrap                //Wrap - each dimension is a ring(toroidal grid), the first and the last cells are neighbors
+)
+
+//NeighborCurve is implemented by curves which find codes of neighbor cells without encoding them from scratch.
+type NeighborCurve interface {
+	Curve
+	//AppendNeighbors appends codes of face neighbors(2 * dims) or all 3^dims - 1 neighbors of the cell to out.
+	//If wrap is true, the grid is toroidal, otherwise neighbors beyond the grid are skipped.
+	//Codes may repeat and include the cell itself if the dimension is shorter than 3 cells.
+	AppendNeighbors(out []uint64, code uint64, wrap, all bool) ([]uint64, error)
+}
+
+//Neighbors returns sorted codes of cells adjacent to the cell with given code in the grid.
+//If all is false, only face neighbors(2 * dims) are returned, otherwise all 3^dims - 1 neighbors.
+//The cell itself is never returned, even if the grid is wrapped around a short dimension.
+//
+//Curves which implement NeighborCurve use their fast path,
+//other curves decode the cell and encode each neighbor.
+func Neighbors(c Curve, code uint64, bounds Bounds, all bool) ([]uint64, error) {
+	if all && c.Dimensions() > grid.MaxDimensions {
+		return nil, fmt.Errorf("all neighbors could be found only for curves with at most %d dimensions", grid.MaxDimensions)
+	}
+	if bounds != Clamp && bounds != Wrap {
+		return nil, fmt.Errorf("unknown bounds == %v", bounds)
+	}
+	wrap := bounds == Wrap
+
+	var res []uint64
+	var err error
+	if nc, ok := c.(NeighborCurve); ok {
+		res, err = nc.AppendNeighbors(nil, code, wrap, all)
+	} else {
+		res, err = appendNeighbors(c, nil, code, 1, wrap, all)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	uniq := res[:0]
+	for i, n := range res {
+		if n == code || (i > 0 && n == res[i-1]) {
+			continue
+		}
+		uniq = append(uniq, n)
+	}
+	return uniq, nil
+}
+
+//appendNeighbors finds neighbors of the cell by moving its coordinates by the step and encoding them.
+func appendNeighbors(c Curve, out []uint64, code, step uint64, wrap, all bool) ([]uint64, error) {
+	coords, err := c.Decode(code)
+	if err != nil {
+		return nil, err
+	}
+	sizes := DimensionSizes(c)
+	buf := make([]uint64, len(coords))
+	grid.Offsets(len(coords), all, func(delta []int) {
+		if err != nil {
+			return
+		}
+		for i := range coords {
+			var ok bool
+			if buf[i], ok = grid.Move(coords[i], sizes[i], step, delta[i], wrap); !ok {
+				return
+			}
+		}
+		var n uint64
+		if n, err = c.Encode(buf); err == nil {
+			out = append(out, n)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,173 @@
+package curve
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNeighbors(t *testing.T) {
+	tests := []struct {
+		name  string
+		cType CurveType
+		dims  uint64
+		bits  uint64
+	}{
+		{"hilbert 2x3", Hilbert, 2, 3},
+		{"hilbert 3x2", Hilbert, 3, 2},
+		{"hilbert 4x2", Hilbert, 4, 2},
+		{"hilbert 2x1", Hilbert, 2, 1},
+		{"morton 2x3", Morton, 2, 3},
+		{"morton 3x2", Morton, 3, 2},
+		{"morton 1x4", Morton, 1, 4},
+		{"peano 2x2", Peano, 2, 2},
+		{"moore 2x3", Moore, 2, 3},
+		{"gray 3x2", Gray, 3, 2},
+		{"onion 2x3", Onion, 2, 3},
+		{"onion 3x2", Onion, 3, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCurve(tt.cType, tt.dims, tt.bits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, bounds := range []Bounds{Clamp, Wrap} {
+				for _, all := range []bool{false, true} {
+					for code := uint64(0); code <= c.Length(); code++ {
+						got, err := Neighbors(c, code, bounds, all)
+						if !assert.NoError(t, err) {
+							return
+						}
+						want := bruteNeighbors(c, code, bounds == Wrap, all)
+						if !assert.Equal(t, want, got, "code %v, bounds %v, all %v", code, bounds, all) {
+							return
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+//bruteNeighbors finds neighbors by comparing coordinates of each cell of the curve.
+func bruteNeighbors(c Curve, code uint64, wrap, all bool) []uint64 {
+	coords, _ := c.Decode(code)
+	size := c.DimensionSize() + 1
+	res := []uint64{}
+	for n := uint64(0); n <= c.Length(); n++ {
+		nc, _ := c.Decode(n)
+		moved := 0
+		adjacent := n != code
+		for i := range coords {
+			d := (nc[i] + size - coords[i]) % size
+			switch {
+			case nc[i] == coords[i]:
+			case nc[i] == coords[i]+1 || nc[i]+1 == coords[i]:
+				moved++
+			case wrap && (d == 1 || d == size-1):
+				moved++
+			default:
+				adjacent = false
+			}
+		}
+		if adjacent && (all || moved == 1) {
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+func TestNeighbors_Large(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	for _, cType := range []CurveType{Hilbert, Morton} {
+		c, err := NewCurve(cType, 3, 21)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 100; i++ {
+			code := rnd.Uint64() & c.Length()
+			for _, bounds := range []Bounds{Clamp, Wrap} {
+				got, err := Neighbors(c, code, bounds, true)
+				assert.NoError(t, err)
+				want, err := Neighbors(plainCurve{c}, code, bounds, true)
+				assert.NoError(t, err)
+				assert.Equal(t, want, got)
+			}
+		}
+	}
+}
+
+func TestNeighbors_Truncated(t *testing.T) {
+	wc, err := NewWideCurve(Hilbert, 3, 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := Truncate(wc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	step := uint64(1) << (c.Shift() / 3)
+	code, err := c.Encode([]uint64{step * 5, step * 7, step * 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Neighbors(c, code, Clamp, false)
+	assert.NoError(t, err)
+	assert.Len(t, got, 6)
+	for _, n := range got {
+		coords, err := c.Decode(n)
+		assert.NoError(t, err)
+		dist := coords[0]/step + coords[1]/step + coords[2]/step
+		assert.True(t, dist == 20 || dist == 22, "%v", coords)
+	}
+
+	corner, err := Neighbors(c, 0, Clamp, true)
+	assert.NoError(t, err)
+	assert.Len(t, corner, 7)
+	wrapped, err := Neighbors(c, 0, Wrap, true)
+	assert.NoError(t, err)
+	assert.Len(t, wrapped, 26)
+}
+
+func TestNeighbors_Errors(t *testing.T) {
+	c, err := NewCurve(Hilbert, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Neighbors(c, c.Length()+1, Clamp, false)
+	assert.Error(t, err)
+	_, err = Neighbors(c, 0, Bounds(42), false)
+	assert.Error(t, err)
+	wide, err := NewCurve(Morton, 13, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Neighbors(wide, 0, Clamp, true)
+	assert.Error(t, err)
+	_, err = Neighbors(wide, 0, Clamp, false)
+	assert.NoError(t, err)
+}
+
+func BenchmarkNeighbors(b *testing.B) {
+	for _, cType := range []CurveType{Hilbert, Morton} {
+		c, err := NewCurve(cType, 3, 20)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, impl := range []struct {
+			name string
+			c    Curve
+		}{{"fast", c}, {"generic", plainCurve{c}}} {
+			b.Run(cType.String()+"/"+impl.name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, err := Neighbors(impl.c, uint64(i)*7919&c.Length(), Wrap, true); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
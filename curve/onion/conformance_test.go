@@ -0,0 +1,19 @@
+package onion_test
+
+import (
+	"testing"
+
+	"github.com/struckoff/sfcframework/curve/curvetest"
+	"github.com/struckoff/sfcframework/curve/onion"
+)
+
+var factory = curvetest.Factory(onion.New)
+
+func TestCurve_Conformance(t *testing.T) {
+	curvetest.Run(t, factory, curvetest.Options{})
+	curvetest.Run(t, factory, curvetest.Options{Adjacent: true, Shapes: []curvetest.Shape{{Dims: 2, Bits: 1}, {Dims: 2, Bits: 6}, {Dims: 2, Bits: 32}}})
+}
+
+func TestCurve_Fuzz(t *testing.T) {
+	curvetest.Fuzz(t, factory, 4, 16, 42, 10000)
+}
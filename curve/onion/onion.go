@@ -0,0 +1,281 @@
+/*
+	The onion curve is a layered curve: the hypercube is peeled like an onion
+	and cells are visited layer by layer from the outer one to the center.
+
+	The layer of the cell is its distance to the nearest side of the hypercube.
+	Each layer is the shell of a smaller hypercube and it is split by the first dimension:
+	the first face is visited as a whole hypercube of one dimension less(layer by layer),
+	then the shell is visited slice by slice, the last face is visited as the first one.
+	Shells with 2 dimensions are visited as a ring, so the 2D curve is continuous inside each layer.
+
+	Onion curve keeps cells of cube-shaped queries in few ranges of codes.
+
+	NOTE: The idea is derived from work done by Pan Xu, Cuong Nguyen and Srikanta Tirthapura and published in
+	"Onion Curve: A Space Filling Curve with Near-Optimal Clustering".
+
+	Example(2 bits):
+		 3  4  5  6
+		 2 13 14  7
+		 1 12 15  8
+		 0 11 10  9
+*/
+package onion
+
+import (
+	"errors"
+	"fmt"
+)
+
+//Curve - the representation of onion curve.
+type Curve struct {
+	dimensions uint64 //amount of curve dimensions
+	bits       uint64 //size in bits of each dimension
+	side       uint64 //amount of cells along each dimension
+	maxSize    uint64 //maximum value of each dimension
+	maxCode    uint64 //biggest code which could be decoded by curve
+}
+
+//New - create new onion curve.
+//
+//dims - amount of curve dimensions.
+//
+//bits - size in bits of each dimension.
+func New(dims, bits uint64) (*Curve, error) {
+	if bits == 0 || dims == 0 {
+		return nil, errors.New("number of bits and dimension must be greater than 0")
+	}
+	if dims*bits > 64 {
+		return nil, errors.New("number of bits of code(dims * bits) must be less or equal than 64")
+	}
+	return &Curve{
+		dimensions: dims,
+		bits:       bits,
+		side:       1 << bits,
+		maxSize:    1<<bits - 1,
+		maxCode:    1<<(dims*bits) - 1,
+	}, nil
+}
+
+//Decode returns coordinates for a given code(distance)
+//Method will return error if code(distance) exceeds the limit(2 ^ (dims * bits) - 1)
+func (c *Curve) Decode(code uint64) (coords []uint64, err error) {
+	if err := c.validateCode(code); err != nil {
+		return nil, err
+	}
+	coords = make([]uint64, c.dimensions)
+	cubeDecode(coords, c.side, code)
+	return coords, nil
+}
+
+//DecodeWithBuffer returns coordinates for a given code(distance).
+//Method will return error if:
+//  - buffer less than number of dimensions
+//	- code(distance) exceeds the limit(2 ^ (dims * bits) - 1)
+func (c *Curve) DecodeWithBuffer(buf []uint64, code uint64) (coords []uint64, err error) {
+	if len(buf) < int(c.dimensions) {
+		return nil, errors.New("buffer length less then dimensions")
+	}
+	if err := c.validateCode(code); err != nil {
+		return nil, err
+	}
+	cubeDecode(buf[:c.dimensions], c.side, code)
+	return buf, nil
+}
+
+func (c *Curve) validateCode(code uint64) error {
+	if code > c.maxCode {
+		return fmt.Errorf("code == %v exceeds limit (2^(dimensions * bits) - 1) == %v", code, c.maxCode)
+	}
+	return nil
+}
+
+//Encode returns code(distance) for a given set of coordinates
+//Method will return error if any of the coordinates exceeds limit(2 ^ bits - 1)
+func (c *Curve) Encode(coords []uint64) (code uint64, err error) {
+	if err := c.validateCoordinates(coords); err != nil {
+		return 0, err
+	}
+	return cubeEncode(append([]uint64{}, coords[:c.dimensions]...), c.side), nil
+}
+
+func (c *Curve) validateCoordinates(coords []uint64) error {
+	if len(coords) < int(c.dimensions) {
+		return fmt.Errorf("number of coordinates == %v less then dimensions == %v", len(coords), c.dimensions)
+	}
+	for i := uint64(0); i < c.dimensions; i++ {
+		if coords[i] > c.maxSize {
+			return fmt.Errorf("coordinate == %v exceeds limit == %v", coords[i], c.maxSize)
+		}
+	}
+	return nil
+}
+
+// DimensionSize returns the maximum coordinate value in any dimension
+func (c *Curve) DimensionSize() uint64 {
+	return c.maxSize
+}
+
+// Length returns the maximum distance along curve(code value)
+//
+// 2^(dimensions * bits) - 1
+func (c *Curve) Length() uint64 {
+	return c.maxCode
+}
+
+//Dimensions - amount of curve dimensions
+func (c *Curve) Dimensions() uint64 {
+	return c.dimensions
+}
+
+//Bits - size in bits of each dimension
+func (c *Curve) Bits() uint64 {
+	return c.bits
+}
+
+//Sizes of hypercubes and shells are computed modulo 2^64,
+//the amount of cells before any cell of the curve always fits uint64.
+
+//pow returns side^dims.
+func pow(side uint64, dims int) uint64 {
+	res := uint64(1)
+	for i := 0; i < dims; i++ {
+		res *= side
+	}
+	return res
+}
+
+//shellSize returns the amount of cells on the shell of the hypercube.
+func shellSize(dims int, side uint64) uint64 {
+	if side <= 2 {
+		return pow(side, dims)
+	}
+	return pow(side, dims) - pow(side-2, dims)
+}
+
+//cubeEncode returns the position of the cell inside the hypercube with given side.
+//Coordinates are overwritten.
+func cubeEncode(coords []uint64, side uint64) uint64 {
+	if len(coords) == 1 {
+		return coords[0]
+	}
+	layer := side
+	for _, x := range coords {
+		if x < layer {
+			layer = x
+		}
+		if side-1-x < layer {
+			layer = side - 1 - x
+		}
+	}
+	inner := side - 2*layer
+	for i := range coords {
+		coords[i] -= layer
+	}
+	return pow(side, len(coords)) - pow(inner, len(coords)) + shellEncode(coords, inner)
+}
+
+//cubeDecode fills coords with coordinates of the cell by its position inside the hypercube with given side.
+func cubeDecode(coords []uint64, side, code uint64) {
+	if len(coords) == 1 {
+		coords[0] = code
+		return
+	}
+	dims := len(coords)
+	total := pow(side, dims)
+	//the first layer which starts after the code
+	lo, hi := uint64(0), (side-1)/2+1
+	for lo+1 < hi {
+		mid := (lo + hi) / 2
+		if total-pow(side-2*mid, dims) <= code {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	inner := side - 2*lo
+	shellDecode(coords, inner, code-(total-pow(inner, dims)))
+	for i := range coords {
+		coords[i] += lo
+	}
+}
+
+//shellEncode returns the position of the cell on the shell of the hypercube with given side.
+func shellEncode(coords []uint64, side uint64) uint64 {
+	if side == 1 {
+		return 0
+	}
+	if len(coords) == 2 {
+		return ringEncode(coords[0], coords[1], side)
+	}
+	rest := coords[1:]
+	face := pow(side, len(rest))
+	switch coords[0] {
+	case 0:
+		return cubeEncode(rest, side)
+	case side - 1:
+		return face + (side-2)*shellSize(len(rest), side) + cubeEncode(rest, side)
+	default:
+		return face + (coords[0]-1)*shellSize(len(rest), side) + shellEncode(rest, side)
+	}
+}
+
+//shellDecode fills coords with coordinates of the cell by its position on the shell of the hypercube with given side.
+func shellDecode(coords []uint64, side, code uint64) {
+	if side == 1 {
+		for i := range coords {
+			coords[i] = 0
+		}
+		return
+	}
+	if len(coords) == 2 {
+		coords[0], coords[1] = ringDecode(side, code)
+		return
+	}
+	rest := coords[1:]
+	face := pow(side, len(rest))
+	if code < face {
+		coords[0] = 0
+		cubeDecode(rest, side, code)
+		return
+	}
+	code -= face
+	slice := shellSize(len(rest), side)
+	if code < (side-2)*slice {
+		coords[0] = 1 + code/slice
+		shellDecode(rest, side, code%slice)
+		return
+	}
+	coords[0] = side - 1
+	cubeDecode(rest, side, code-(side-2)*slice)
+}
+
+//ringEncode returns the position of the cell on the ring of the square with given side.
+//Ring starts in the origin, goes up along the first side and returns along the last one.
+func ringEncode(x, y, side uint64) uint64 {
+	last := side - 1
+	switch {
+	case x == 0:
+		return y
+	case y == last:
+		return last + x
+	case x == last:
+		return 2*last + last - y
+	default:
+		return 3*last + last - x
+	}
+}
+
+//ringDecode reverts ringEncode.
+func ringDecode(side, code uint64) (x, y uint64) {
+	last := side - 1
+	switch {
+	case code < last:
+		return 0, code
+	case code < 2*last:
+		return code - last, last
+	case code < 3*last:
+		return last, last - (code - 2*last)
+	default:
+		return last - (code - 3*last), 0
+	}
+}
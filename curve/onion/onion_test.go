@@ -0,0 +1,209 @@
+package onion
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	type args struct {
+		dims uint64
+		bits uint64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{"2x4", args{dims: 2, bits: 4}, false},
+		{"4x16", args{dims: 4, bits: 16}, false},
+		{"2x0", args{dims: 2, bits: 0}, true},
+		{"0x4", args{dims: 0, bits: 4}, true},
+		{"3x22", args{dims: 3, bits: 22}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.args.dims, tt.args.bits)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.args.dims, c.Dimensions())
+			assert.Equal(t, tt.args.bits, c.Bits())
+			assert.Equal(t, uint64(1<<tt.args.bits-1), c.DimensionSize())
+			assert.Equal(t, uint64(1<<(tt.args.dims*tt.args.bits)-1), c.Length())
+		})
+	}
+}
+
+func TestCurve_Encode(t *testing.T) {
+	c, err := New(2, 2)
+	if !assert.NoError(t, err) {
+		return
+	}
+	//rows from the top to the bottom
+	want := [4][4]uint64{
+		{3, 4, 5, 6},
+		{2, 13, 14, 7},
+		{1, 12, 15, 8},
+		{0, 11, 10, 9},
+	}
+	for row := range want {
+		for x := range want[row] {
+			coords := []uint64{uint64(x), uint64(3 - row)}
+			code, err := c.Encode(coords)
+			assert.NoError(t, err)
+			assert.Equal(t, want[row][x], code, "coords %v", coords)
+			assert.Equal(t, []uint64{uint64(x), uint64(3 - row)}, coords)
+		}
+	}
+}
+
+func TestCurve_Bijection(t *testing.T) {
+	type args struct {
+		dims uint64
+		bits uint64
+	}
+	tests := []struct {
+		name string
+		args args
+	}{
+		{"1x5", args{dims: 1, bits: 5}},
+		{"2x1", args{dims: 2, bits: 1}},
+		{"2x4", args{dims: 2, bits: 4}},
+		{"3x1", args{dims: 3, bits: 1}},
+		{"3x3", args{dims: 3, bits: 3}},
+		{"4x2", args{dims: 4, bits: 2}},
+		{"5x2", args{dims: 5, bits: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.args.dims, tt.args.bits)
+			if !assert.NoError(t, err) {
+				return
+			}
+			seen := map[uint64]bool{}
+			buf := make([]uint64, c.Dimensions())
+			for code := uint64(0); code <= c.Length(); code++ {
+				coords, err := c.DecodeWithBuffer(buf, code)
+				if !assert.NoError(t, err) {
+					return
+				}
+				key := uint64(0)
+				for _, x := range coords {
+					if !assert.True(t, x <= c.DimensionSize(), "code %v", code) {
+						return
+					}
+					key = key<<c.Bits() | x
+				}
+				assert.False(t, seen[key], "code %v", code)
+				seen[key] = true
+				got, err := c.Encode(coords)
+				if !assert.NoError(t, err) || !assert.Equal(t, code, got, "coords %v", coords) {
+					return
+				}
+			}
+		})
+	}
+}
+
+//Cells of outer layers are visited first.
+func TestCurve_Layers(t *testing.T) {
+	c, err := New(3, 3)
+	if !assert.NoError(t, err) {
+		return
+	}
+	prev := uint64(0)
+	for code := uint64(0); code <= c.Length(); code++ {
+		coords, err := c.Decode(code)
+		if !assert.NoError(t, err) {
+			return
+		}
+		l := layer(coords, c.DimensionSize())
+		if !assert.True(t, l >= prev, "code %v", code) {
+			return
+		}
+		prev = l
+	}
+	assert.Equal(t, uint64(3), prev)
+}
+
+//2D curve is continuous, including steps between layers.
+func TestCurve_Continuous2D(t *testing.T) {
+	c, err := New(2, 5)
+	if !assert.NoError(t, err) {
+		return
+	}
+	prev, _ := c.Decode(0)
+	for code := uint64(1); code <= c.Length(); code++ {
+		coords, err := c.Decode(code)
+		if !assert.NoError(t, err) {
+			return
+		}
+		if !assert.Equal(t, uint64(1), distance(prev, coords), "code %v", code) {
+			return
+		}
+		prev = coords
+	}
+}
+
+func TestCurve_Wide(t *testing.T) {
+	c, err := New(4, 16)
+	if !assert.NoError(t, err) {
+		return
+	}
+	rnd := rand.New(rand.NewSource(42))
+	for n := 0; n < 10000; n++ {
+		code := rnd.Uint64()
+		coords, err := c.Decode(code)
+		if !assert.NoError(t, err) {
+			return
+		}
+		got, err := c.Encode(coords)
+		if !assert.NoError(t, err) || !assert.Equal(t, code, got) {
+			return
+		}
+	}
+}
+
+func TestCurve_Errors(t *testing.T) {
+	c, err := New(2, 4)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = c.Decode(c.Length() + 1)
+	assert.Error(t, err)
+	_, err = c.DecodeWithBuffer(make([]uint64, 1), 0)
+	assert.Error(t, err)
+	_, err = c.Encode([]uint64{1})
+	assert.Error(t, err)
+	_, err = c.Encode([]uint64{16, 0})
+	assert.Error(t, err)
+}
+
+func layer(coords []uint64, max uint64) uint64 {
+	l := max
+	for _, x := range coords {
+		if x < l {
+			l = x
+		}
+		if max-x < l {
+			l = max - x
+		}
+	}
+	return l
+}
+
+func distance(a, b []uint64) (d uint64) {
+	for i := range a {
+		if a[i] > b[i] {
+			d += a[i] - b[i]
+		} else {
+			d += b[i] - a[i]
+		}
+	}
+	return d
+}
@@ -0,0 +1,210 @@
+/*
+	The Peano index is expressed as a sequence of base-3 digits.
+
+	Each level of the curve contributes one ternary digit per dimension,
+	the digit of the first dimension is the most significant one.
+	A digit is reflected (d -> 2 - d) when the sum of the preceding digits
+	of the other dimensions is odd, which makes the curve serpentine:
+	every two consecutive cells are neighbours.
+
+		Example: 2 dimensions, order 1.
+		2 | 2 3 8
+		1 | 1 4 7
+		0 | 0 5 6
+		  +------
+		    0 1 2
+
+	Since the curve is based on the powers of 3, the size of each dimension is 3^order,
+	where order is the smallest number which allows fitting 2^bits values.
+*/
+package peano
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+const base = 3
+
+//maxDigits - the maximum amount of ternary digits which fits uint64.
+const maxDigits = 40
+
+//Curve - the representation of Peano curve.
+type Curve struct {
+	dimensions uint64   //amount of curve dimensions
+	bits       uint64   //size in bits of each dimension
+	order      uint64   //amount of ternary digits of each dimension
+	length     uint64   //order * dims
+	maxSize    uint64   //maximum value of each dimension
+	maxCode    uint64   //biggest code which could be decoded by curve
+	pow        []uint64 //powers of 3 up to length
+}
+
+//New - create new peano curve.
+//
+//dims - amount of curve dimensions.
+//
+//bits - size in bits of each dimension.
+//The curve is built with the smallest order which allows fitting 2^bits values into each dimension.
+func New(dims, bits uint64) (*Curve, error) {
+	if bits <= 0 || dims <= 0 {
+		return nil, errors.New("number of bits and dimension must be greater than 0")
+	}
+	if bits >= 64 {
+		return nil, fmt.Errorf("number of bits == %v exceeds limit == 63", bits)
+	}
+
+	order := uint64(0)
+	for size := uint64(1); size < 1<<bits; size *= base {
+		order++
+	}
+	if order*dims > maxDigits {
+		return nil, fmt.Errorf("3^(dimensions * order) == 3^%v exceeds limit == 3^%v", order*dims, maxDigits)
+	}
+
+	pow := make([]uint64, order*dims+1)
+	pow[0] = 1
+	for i := 1; i < len(pow); i++ {
+		pow[i] = pow[i-1] * base
+	}
+
+	return &Curve{
+		dimensions: dims,
+		bits:       bits,
+		order:      order,
+		length:     order * dims,
+		maxSize:    pow[order] - 1,
+		maxCode:    pow[order*dims] - 1,
+		pow:        pow,
+	}, nil
+}
+
+//Decode returns coordinates for a given code(distance).
+//
+//Method will return error if code(distance) exceeds the limit(3 ^ (dims * order) - 1).
+func (c *Curve) Decode(code uint64) (coords []uint64, err error) {
+	if err := c.validateCode(code); err != nil {
+		return nil, err
+	}
+	coords = make([]uint64, c.dimensions)
+	return c.parseIndex(coords, code), nil
+}
+
+//DecodeWithBuffer returns coordinates for a given code(distance).
+// Method will return error if:
+//
+// - buffer less than number of dimensions
+//
+// - code(distance) exceeds the limit(3 ^ (dims * order) - 1)
+func (c *Curve) DecodeWithBuffer(buf []uint64, code uint64) (coords []uint64, err error) {
+	if len(buf) < int(c.dimensions) {
+		return nil, errors.New("buffer length less then dimensions")
+	}
+	if err := c.validateCode(code); err != nil {
+		return nil, err
+	}
+	for i := range buf {
+		buf[i] = 0
+	}
+	return c.parseIndex(buf, code), nil
+}
+
+func (c *Curve) validateCode(code uint64) error {
+	if code > c.maxCode {
+		return fmt.Errorf("code == %v exceeds limit (3^(dimensions * order) - 1) == %v", code, c.maxCode)
+	}
+	return nil
+}
+
+//parseIndex walks through the digits of the code from the most significant one
+//and reflects each digit according to parity of the preceding digits of other dimensions.
+func (c *Curve) parseIndex(coords []uint64, code uint64) []uint64 {
+	var total uint64
+	var parity [maxDigits]uint64
+
+	for i := uint64(0); i < c.length; i++ {
+		dim := i % c.dimensions
+		digit := (code / c.pow[c.length-i-1]) % base
+		x := digit
+		if (total^parity[dim])&1 != 0 {
+			x = base - 1 - digit
+		}
+		coords[dim] = coords[dim]*base + x
+		total += digit
+		parity[dim] += digit
+	}
+	return coords
+}
+
+//Encode returns code(distance) for a given set of coordinates
+//
+//Method will return error if any of the coordinates exceeds limit(3 ^ order - 1)
+func (c *Curve) Encode(coords []uint64) (code uint64, err error) {
+	if err := c.validateCoordinates(coords); err != nil {
+		return 0, err
+	}
+
+	var total uint64
+	var parity [maxDigits]uint64
+
+	for i := uint64(0); i < c.length; i++ {
+		dim := i % c.dimensions
+		level := i / c.dimensions
+		x := (coords[dim] / c.pow[c.order-level-1]) % base
+		digit := x
+		if (total^parity[dim])&1 != 0 {
+			digit = base - 1 - x
+		}
+		code = code*base + digit
+		total += digit
+		parity[dim] += digit
+	}
+	return code, nil
+}
+
+func (c *Curve) validateCoordinates(coords []uint64) error {
+	if len(coords) < int(c.dimensions) {
+		return fmt.Errorf("number of coordinates == %v less then dimensions == %v", len(coords), c.dimensions)
+	}
+	for i := range coords {
+		if coords[i] > c.maxSize {
+			return fmt.Errorf("coordinate == %v exceeds limit == %v", coords[i], c.maxSize)
+		}
+	}
+	return nil
+}
+
+// DimensionSize returns the maximum coordinate value in any dimension
+//
+// 3^order - 1
+func (c *Curve) DimensionSize() uint64 {
+	return c.maxSize
+}
+
+// Length returns the maximum distance along curve(code value).
+//
+// 3^(dimensions * order) - 1
+func (c *Curve) Length() uint64 {
+	return c.maxCode
+}
+
+//Dimensions - amount of curve dimensions
+func (c *Curve) Dimensions() uint64 {
+	return c.dimensions
+}
+
+//Bits - size in bits required to store a coordinate of each dimension
+func (c *Curve) Bits() uint64 {
+	return uint64(bits.Len64(c.maxSize))
+}
+
+//Order - amount of ternary digits of each dimension
+func (c *Curve) Order() uint64 {
+	return c.order
+}
+
+//BlockRadix - every aligned block of cells with the side of power of 3 is visited by curve as a contiguous range of codes.
+func (c *Curve) BlockRadix() uint64 {
+	return base
+}
@@ -0,0 +1,899 @@
+package peano
+
+import (
+	"io/ioutil"
+	"log"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeanoCurve_Decode(t *testing.T) {
+	type fields struct {
+		dimensions uint64
+		bits       uint64
+	}
+	type args struct {
+		code uint64
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantCoords []uint64
+		wantErr    bool
+	}{
+		{
+			"3 == [1, 2]",
+			fields{
+				2,
+				1,
+			},
+			args{
+				3,
+			},
+			[]uint64{
+				1, 2,
+			},
+			false,
+		},
+		{
+			"96 == [8, 12]",
+			fields{
+				2,
+				10,
+			},
+			args{
+				96,
+			},
+			[]uint64{
+				8, 12,
+			},
+			false,
+		},
+		{
+			"1096 == [12, 40]",
+			fields{
+				2,
+				10,
+			},
+			args{
+				1096,
+			},
+			[]uint64{
+				12, 40,
+			},
+			false,
+		},
+		{
+			"500 == [7, 1, 2]",
+			fields{
+				3,
+				3,
+			},
+			args{
+				500,
+			},
+			[]uint64{
+				7, 1, 2,
+			},
+			false,
+		},
+		{
+			"2^62 == [4120, 5636, 3100, 1474, 4414]",
+			fields{
+				5,
+				12,
+			},
+			args{
+				1 << 62,
+			},
+			[]uint64{
+				4120, 5636, 3100, 1474, 4414,
+			},
+			false,
+		},
+		{
+			"not valid code",
+			fields{
+				2,
+				1,
+			},
+			args{
+				math.MaxUint64,
+			},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := New(tt.fields.dimensions, tt.fields.bits)
+			gotCoords, err := c.Decode(tt.args.code)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			assert.Equal(t, tt.wantCoords, gotCoords)
+		})
+	}
+}
+
+func TestPeanoCurve_DecodeWithBuffer(t *testing.T) {
+	type fields struct {
+		dimensions uint64
+		bits       uint64
+	}
+	type args struct {
+		code uint64
+		buf  []uint64
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		wantCoords []uint64
+		wantErr    bool
+	}{
+		{
+			"3 == [1, 2]",
+			fields{
+				2,
+				1,
+			},
+			args{
+				3,
+				make([]uint64, 2),
+			},
+			[]uint64{
+				1, 2,
+			},
+			false,
+		},
+		{
+			"96 == [8, 12]",
+			fields{
+				2,
+				10,
+			},
+			args{
+				96,
+				make([]uint64, 2),
+			},
+			[]uint64{
+				8, 12,
+			},
+			false,
+		},
+		{
+			"dirty buffer",
+			fields{
+				2,
+				10,
+			},
+			args{
+				1096,
+				[]uint64{42, 42},
+			},
+			[]uint64{
+				12, 40,
+			},
+			false,
+		},
+		{
+			"2^62 == [4120, 5636, 3100, 1474, 4414, 0, 0, 0, 0, 0]",
+			fields{
+				5,
+				12,
+			},
+			args{
+				1 << 62,
+				make([]uint64, 10),
+			},
+			[]uint64{
+				4120, 5636, 3100, 1474, 4414, 0, 0, 0, 0, 0,
+			},
+			false,
+		},
+		{
+			"buf < dimensions",
+			fields{
+				5,
+				12,
+			},
+			args{
+				1 << 62,
+				make([]uint64, 1),
+			},
+			nil,
+			true,
+		},
+		{
+			"code < maxCode",
+			fields{
+				2,
+				2,
+			},
+			args{
+				math.MaxInt64,
+				make([]uint64, 2),
+			},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := New(tt.fields.dimensions, tt.fields.bits)
+			gotCoords, err := c.DecodeWithBuffer(tt.args.buf, tt.args.code)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			assert.Equal(t, tt.wantCoords, gotCoords)
+		})
+	}
+}
+
+func TestPeanoCurve_Encode(t *testing.T) {
+	type fields struct {
+		dimensions uint64
+		bits       uint64
+	}
+	type args struct {
+		coords []uint64
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		args     args
+		wantCode uint64
+		wantErr  bool
+	}{
+		{
+			"[1, 2] == 3",
+			fields{
+				2,
+				1,
+			},
+			args{
+				[]uint64{1, 2},
+			},
+			3,
+			false,
+		},
+		{
+			"[8, 12] == 96",
+			fields{
+				2,
+				10,
+			},
+			args{
+				[]uint64{8, 12},
+			},
+			96,
+			false,
+		},
+		{
+			"[12, 40] == 1096",
+			fields{
+				2,
+				10,
+			},
+			args{
+				[]uint64{12, 40},
+			},
+			1096,
+			false,
+		},
+		{
+			"[4120, 5636, 3100, 1474, 4414] == 2^62",
+			fields{
+				5,
+				12,
+			},
+			args{
+				[]uint64{4120, 5636, 3100, 1474, 4414},
+			},
+			1 << 62,
+			false,
+		},
+		{
+			"not enough coords",
+			fields{
+				5,
+				12,
+			},
+			args{
+				[]uint64{1, 2},
+			},
+			0,
+			true,
+		},
+		{
+			"not valid coords",
+			fields{
+				2,
+				1,
+			},
+			args{
+				[]uint64{3, 0},
+			},
+			0,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := New(tt.fields.dimensions, tt.fields.bits)
+			gotCode, err := c.Encode(tt.args.coords)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Encode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantCode, gotCode)
+			}
+		})
+	}
+}
+
+func TestPeanoCurve_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		dims uint64
+		bits uint64
+	}{
+		{"1x1", 1, 1},
+		{"1x4", 1, 4},
+		{"2x1", 2, 1},
+		{"2x3", 2, 3},
+		{"3x2", 3, 2},
+		{"4x1", 4, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.dims, tt.bits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			seen := make(map[uint64]struct{})
+			for code := uint64(0); code <= c.Length(); code++ {
+				coords, err := c.Decode(code)
+				assert.NoError(t, err)
+				got, err := c.Encode(coords)
+				assert.NoError(t, err)
+				assert.Equal(t, code, got)
+				seen[code] = struct{}{}
+			}
+			assert.Equal(t, int(c.Length()+1), len(seen))
+		})
+	}
+}
+
+func TestPeanoCurve_Adjacency(t *testing.T) {
+	tests := []struct {
+		name string
+		dims uint64
+		bits uint64
+	}{
+		{"1x4", 1, 4},
+		{"2x1", 2, 1},
+		{"2x4", 2, 4},
+		{"3x3", 3, 3},
+		{"5x1", 5, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.dims, tt.bits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			prev, err := c.Decode(0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for code := uint64(1); code <= c.Length(); code++ {
+				coords, err := c.Decode(code)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var dist uint64
+				for i := range coords {
+					if coords[i] > prev[i] {
+						dist += coords[i] - prev[i]
+					} else {
+						dist += prev[i] - coords[i]
+					}
+				}
+				if dist != 1 {
+					t.Fatalf("cells %v(%v) and %v(%v) are not neighbours", code-1, prev, code, coords)
+				}
+				prev = coords
+			}
+		})
+	}
+}
+
+func BenchmarkCurve_Decode(b *testing.B) {
+	log.SetOutput(ioutil.Discard)
+
+	c, err := New(2, 10)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := uint64(0); i < uint64(b.N); i++ {
+		log.Print(c.Decode(i % c.Length()))
+	}
+}
+
+func BenchmarkCurve_Decode_Peano(b *testing.B) {
+	log.SetOutput(ioutil.Discard)
+
+	type args struct {
+		dims uint64
+		bits uint64
+	}
+	benchmarks := []struct {
+		name string
+		args args
+	}{
+		{
+			"2x2",
+			args{dims: 2, bits: 2},
+		},
+		{
+			"2x10",
+			args{dims: 2, bits: 10},
+		},
+		{
+			"20x1",
+			args{dims: 20, bits: 1},
+		},
+		{
+			"4x12",
+			args{dims: 4, bits: 12},
+		},
+	}
+	for _, bm := range benchmarks {
+		c, err := New(bm.args.dims, bm.args.bits)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := uint64(0); i < uint64(b.N); i++ {
+				log.Print(c.Decode(i % c.Length()))
+			}
+		})
+	}
+}
+
+func BenchmarkCurve_Encode_Peano(b *testing.B) {
+	log.SetOutput(ioutil.Discard)
+
+	type args struct {
+		dims uint64
+		bits uint64
+	}
+	benchmarks := []struct {
+		name string
+		args args
+	}{
+		{
+			"2x2",
+			args{dims: 2, bits: 2},
+		},
+		{
+			"2x10",
+			args{dims: 2, bits: 10},
+		},
+		{
+			"20x1",
+			args{dims: 20, bits: 1},
+		},
+		{
+			"4x12",
+			args{dims: 4, bits: 12},
+		},
+	}
+
+	for _, bm := range benchmarks {
+		c, err := New(bm.args.dims, bm.args.bits)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(bm.name, func(b *testing.B) {
+			b.StopTimer()
+			b.ReportAllocs()
+			coordsSet := [][]uint64{}
+			for i := uint64(0); i < uint64(b.N); i++ {
+				coord, _ := c.Decode(i % c.Length())
+				coordsSet = append(coordsSet, coord)
+			}
+			b.StartTimer()
+			for i := 0; i < b.N; i++ {
+				log.Print(c.Encode(coordsSet[i]))
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	type args struct {
+		dims uint64
+		bits uint64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *Curve
+		wantErr bool
+	}{
+		{
+			"zero dimensions",
+			args{dims: 0, bits: 4},
+			nil,
+			true,
+		},
+		{
+			"zero bits",
+			args{dims: 4, bits: 0},
+			nil,
+			true,
+		},
+		{
+			"zero dimensions and bits",
+			args{dims: 0, bits: 0},
+			nil,
+			true,
+		},
+		{
+			"too many bits",
+			args{dims: 1, bits: 64},
+			nil,
+			true,
+		},
+		{
+			"code overflow",
+			args{dims: 4, bits: 16},
+			nil,
+			true,
+		},
+		{
+			"2x1",
+			args{dims: 2, bits: 1},
+			&Curve{
+				dimensions: 2,
+				bits:       1,
+				order:      1,
+				length:     2,
+				maxSize:    2,
+				maxCode:    8,
+				pow:        []uint64{1, 3, 9},
+			},
+			false,
+		},
+		{
+			"2x4",
+			args{dims: 2, bits: 4},
+			&Curve{
+				dimensions: 2,
+				bits:       4,
+				order:      3,
+				length:     6,
+				maxSize:    26,
+				maxCode:    728,
+				pow:        []uint64{1, 3, 9, 27, 81, 243, 729},
+			},
+			false,
+		},
+		{
+			"1x63",
+			args{dims: 1, bits: 63},
+			&Curve{
+				dimensions: 1,
+				bits:       63,
+				order:      40,
+				length:     40,
+				maxSize:    12157665459056928800,
+				maxCode:    12157665459056928800,
+				pow: []uint64{
+					1, 3, 9, 27, 81, 243, 729, 2187, 6561, 19683, 59049, 177147, 531441, 1594323,
+					4782969, 14348907, 43046721, 129140163, 387420489, 1162261467, 3486784401,
+					10460353203, 31381059609, 94143178827, 282429536481, 847288609443,
+					2541865828329, 7625597484987, 22876792454961, 68630377364883, 205891132094649,
+					617673396283947, 1853020188851841, 5559060566555523, 16677181699666569,
+					50031545098999707, 150094635296999121, 450283905890997363,
+					1350851717672992089, 4052555153018976267, 12157665459056928801,
+				},
+			},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.args.dims, tt.args.bits)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCurve_validateCoordinates(t *testing.T) {
+	type fields struct {
+		dimensions uint64
+		maxSize    uint64
+	}
+	type args struct {
+		coords []uint64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "normal",
+			fields: fields{
+				dimensions: 2,
+				maxSize:    26,
+			},
+			args: args{
+				coords: []uint64{4, 26},
+			},
+			wantErr: false,
+		},
+		{
+			name: "not enough coordinates",
+			fields: fields{
+				dimensions: 2,
+				maxSize:    26,
+			},
+			args: args{
+				coords: []uint64{4},
+			},
+			wantErr: true,
+		},
+		{
+			name: "coordinate exceeds limit",
+			fields: fields{
+				dimensions: 2,
+				maxSize:    26,
+			},
+			args: args{
+				coords: []uint64{4, 27},
+			},
+			wantErr: true,
+		},
+		{
+			name: "all coordinates exceeds limit",
+			fields: fields{
+				dimensions: 2,
+				maxSize:    26,
+			},
+			args: args{
+				coords: []uint64{400, 120},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Curve{
+				dimensions: tt.fields.dimensions,
+				maxSize:    tt.fields.maxSize,
+			}
+			if err := c.validateCoordinates(tt.args.coords); (err != nil) != tt.wantErr {
+				t.Errorf("validateCoordinates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCurve_validateCode(t *testing.T) {
+	type fields struct {
+		maxCode uint64
+	}
+	type args struct {
+		code uint64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "normal",
+			fields: fields{
+				maxCode: 728,
+			},
+			args: args{
+				728,
+			},
+			wantErr: false,
+		},
+		{
+			name: "code exceeds limit",
+			fields: fields{
+				maxCode: 728,
+			},
+			args: args{
+				729,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Curve{
+				maxCode: tt.fields.maxCode,
+			}
+			if err := c.validateCode(tt.args.code); (err != nil) != tt.wantErr {
+				t.Errorf("validateCode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCurve_DimensionSize(t *testing.T) {
+	type fields struct {
+		maxSize uint64
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   uint64
+	}{
+		{
+			name: "test",
+			fields: fields{
+				maxSize: 42,
+			},
+			want: 42,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Curve{
+				maxSize: tt.fields.maxSize,
+			}
+			got := c.DimensionSize()
+			assert.Equal(t, int(tt.want), int(got))
+		})
+	}
+}
+
+func TestCurve_Bits(t *testing.T) {
+	type fields struct {
+		maxSize uint64
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   uint64
+	}{
+		{
+			name: "3^1 - 1",
+			fields: fields{
+				maxSize: 2,
+			},
+			want: 2,
+		},
+		{
+			name: "3^3 - 1",
+			fields: fields{
+				maxSize: 26,
+			},
+			want: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Curve{
+				maxSize: tt.fields.maxSize,
+			}
+			got := c.Bits()
+			assert.Equal(t, int(tt.want), int(got))
+		})
+	}
+}
+
+func TestCurve_Length(t *testing.T) {
+	type fields struct {
+		maxCode uint64
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   uint64
+	}{
+		{
+			name: "test",
+			fields: fields{
+				maxCode: 42,
+			},
+			want: 42,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Curve{
+				maxCode: tt.fields.maxCode,
+			}
+			got := c.Length()
+			assert.Equal(t, int(tt.want), int(got))
+		})
+	}
+}
+
+func TestCurve_Dimensions(t *testing.T) {
+	type fields struct {
+		dimensions uint64
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   uint64
+	}{
+		{
+			name: "test",
+			fields: fields{
+				dimensions: 42,
+			},
+			want: 42,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Curve{
+				dimensions: tt.fields.dimensions,
+			}
+			got := c.Dimensions()
+			assert.Equal(t, int(tt.want), int(got))
+		})
+	}
+}
+
+func TestCurve_Order(t *testing.T) {
+	type fields struct {
+		order uint64
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   uint64
+	}{
+		{
+			name: "test",
+			fields: fields{
+				order: 42,
+			},
+			want: 42,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Curve{
+				order: tt.fields.order,
+			}
+			got := c.Order()
+			assert.Equal(t, int(tt.want), int(got))
+		})
+	}
+}
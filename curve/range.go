@@ -0,0 +1,92 @@
+package curve
+
+//Range - range of codes [Min, Max).
+//
+//If Min is greater than Max, the range wraps around the end of the curve(see NewRingRange):
+//it contains codes from Min to the end of the curve and codes [0, Max).
+type Range struct {
+	Min uint64
+	Max uint64
+	Len uint64
+}
+
+//NewRange - creates a new range by specified limits.
+func NewRange(min, max uint64) Range {
+	return Range{
+		Min: min,
+		Max: max,
+		Len: max - min,
+	}
+}
+
+//NewRingRange - creates a new range on the curve which is treated as a ring,
+//so the range could wrap from the end of the curve back to zero.
+//
+//length - the maximum code of the curve(Curve.Length).
+//
+//If min is less or equal than max, the result is the same as NewRange.
+func NewRingRange(min, max, length uint64) Range {
+	if min <= max {
+		return NewRange(min, max)
+	}
+	return Range{
+		Min: min,
+		Max: max,
+		Len: length - min + 1 + max,
+	}
+}
+
+//Wraps reports whether the range wraps around the end of the curve.
+func (r *Range) Wraps() bool {
+	return r.Min > r.Max
+}
+
+//Fits <- min <= index < max
+//
+//For wrapping ranges: index >= min or index < max
+func (r *Range) Fits(index uint64) bool {
+	if r.Wraps() {
+		return index >= r.Min || index < r.Max
+	}
+	return index >= r.Min && index < r.Max
+}
+
+//Split returns the range as a sorted set of ranges which do not wrap.
+//
+//length - the maximum code of the curve(Curve.Length).
+//If length is the maximum uint64 value, the last code is left out of the result.
+func (r *Range) Split(length uint64) []Range {
+	if !r.Wraps() {
+		return []Range{*r}
+	}
+	end := length + 1
+	if end == 0 {
+		end = length
+	}
+	var res []Range
+	if r.Max > 0 {
+		res = append(res, NewRange(0, r.Max))
+	}
+	if r.Min < end {
+		res = append(res, NewRange(r.Min, end))
+	}
+	return res
+}
+
+//Intersect returns the intersection of two ranges,
+//ok value represents whether ranges intersect or not.
+//
+//Ranges must not wrap, wrapping ranges should be split first(see Split).
+func (r *Range) Intersect(o Range) (res Range, ok bool) {
+	min, max := r.Min, r.Max
+	if o.Min > min {
+		min = o.Min
+	}
+	if o.Max < max {
+		max = o.Max
+	}
+	if min >= max {
+		return Range{}, false
+	}
+	return NewRange(min, max), true
+}
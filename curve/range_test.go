@@ -0,0 +1,77 @@
+package curve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRange_Intersect(t *testing.T) {
+	tests := []struct {
+		name   string
+		r, o   Range
+		want   Range
+		wantOk bool
+	}{
+		{"inside", NewRange(10, 20), NewRange(12, 15), NewRange(12, 15), true},
+		{"overlap", NewRange(10, 20), NewRange(15, 25), NewRange(15, 20), true},
+		{"adjacent", NewRange(10, 20), NewRange(20, 25), Range{}, false},
+		{"disjoint", NewRange(10, 20), NewRange(0, 5), Range{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.r.Intersect(tt.o)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewRingRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		min, max  uint64
+		length    uint64
+		want      Range
+		wantWraps bool
+	}{
+		{"linear", 10, 20, 255, NewRange(10, 20), false},
+		{"empty", 10, 10, 255, NewRange(10, 10), false},
+		{"wraps", 200, 10, 255, Range{Min: 200, Max: 10, Len: 66}, true},
+		{"wraps to zero", 200, 0, 255, Range{Min: 200, Max: 0, Len: 56}, true},
+		{"wraps 64 bits", 1<<64 - 10, 10, 1<<64 - 1, Range{Min: 1<<64 - 10, Max: 10, Len: 20}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewRingRange(tt.min, tt.max, tt.length)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantWraps, got.Wraps())
+		})
+	}
+}
+
+func TestRange_FitsRing(t *testing.T) {
+	r := NewRingRange(200, 10, 255)
+	for code := uint64(0); code <= 255; code++ {
+		assert.Equal(t, code >= 200 || code < 10, r.Fits(code), "code %v", code)
+	}
+}
+
+func TestRange_Split(t *testing.T) {
+	tests := []struct {
+		name   string
+		r      Range
+		length uint64
+		want   []Range
+	}{
+		{"linear", NewRange(10, 20), 255, []Range{NewRange(10, 20)}},
+		{"wraps", NewRingRange(200, 10, 255), 255, []Range{NewRange(0, 10), NewRange(200, 256)}},
+		{"wraps to zero", NewRingRange(200, 0, 255), 255, []Range{NewRange(200, 256)}},
+		{"wraps 64 bits", NewRingRange(1<<64-10, 10, 1<<64-1), 1<<64 - 1, []Range{NewRange(0, 10), NewRange(1<<64-10, 1<<64-1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.r.Split(tt.length))
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package curve
+
+import (
+	"errors"
+	"fmt"
+)
+
+//Box - axis-aligned box of cells given by the minimum and maximum(inclusive) coordinates in each dimension.
+type Box struct {
+	Min []uint64
+	Max []uint64
+}
+
+//RegionCurve is implemented by curves which compute the region of the range of codes faster than cell by cell.
+type RegionCurve interface {
+	Curve
+	//RangeRegion returns disjoint boxes which cover exactly cells of the range of codes, the range does not wrap.
+	RangeRegion(r Range) ([]Box, error)
+}
+
+//CellBox returns the box covered by the cell with given code.
+//For most curves it is the cell itself, cells of Truncated curve are hypercubes of the wide curve.
+func CellBox(c Curve, code uint64) (Box, error) {
+	if code > c.Length() {
+		return Box{}, fmt.Errorf("code == %v exceeds limit == %v", code, c.Length())
+	}
+	if code+1 == 0 {
+		//the range of the last code does not fit uint64, the curve has cells of a single code
+		coords, err := c.Decode(code)
+		if err != nil {
+			return Box{}, err
+		}
+		return Box{Min: coords, Max: append([]uint64{}, coords...)}, nil
+	}
+	return RangeBox(c, NewRange(code, code+1))
+}
+
+//RangeBox returns the minimum bounding box of cells of the range of codes, codes beyond Length() are skipped.
+//Wrapping range is split at the end of the curve.
+func RangeBox(c Curve, r Range) (Box, error) {
+	boxes, err := RangeRegion(c, r)
+	if err != nil {
+		return Box{}, err
+	}
+	if len(boxes) == 0 {
+		return Box{}, errors.New("range does not contain cells of the curve")
+	}
+	res := Box{
+		Min: append([]uint64{}, boxes[0].Min...),
+		Max: append([]uint64{}, boxes[0].Max...),
+	}
+	for _, b := range boxes[1:] {
+		for i := range res.Min {
+			if b.Min[i] < res.Min[i] {
+				res.Min[i] = b.Min[i]
+			}
+			if b.Max[i] > res.Max[i] {
+				res.Max[i] = b.Max[i]
+			}
+		}
+	}
+	return res, nil
+}
+
+//RangeRegion returns disjoint boxes which cover exactly cells of the range of codes, codes beyond Length() are skipped.
+//Wrapping range is split at the end of the curve.
+//
+//Curves which implement RegionCurve compute the region by themselves,
+//curves which implement FacedCurve are split by faces and decomposed by the face curve.
+//Curves which implement BlockCurve(Hilbert and Morton curves) split the range into aligned blocks,
+//so the region is a small set of hypercubes.
+//Other curves are decoded cell by cell, each cell is a separate box.
+func RangeRegion(c Curve, r Range) ([]Box, error) {
+	var res []Box
+	for _, piece := range r.Split(c.Length()) {
+		if piece.Max > c.Length()+1 && c.Length()+1 != 0 {
+			piece.Max = c.Length() + 1
+		}
+		if piece.Min >= piece.Max {
+			continue
+		}
+		var boxes []Box
+		var err error
+		if rc, ok := c.(RegionCurve); ok {
+			boxes, err = rc.RangeRegion(piece)
+		} else if fc, ok := c.(FacedCurve); ok {
+			boxes, err = faceRegion(fc, piece)
+		} else if bc, ok := c.(BlockCurve); ok && bc.BlockRadix() > 1 {
+			boxes, err = alignedRegion(c, blockLevels(bc), piece)
+		} else {
+			boxes, err = cellRegion(c, piece)
+		}
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, boxes...)
+	}
+	return res, nil
+}
+
+//regionLevel - aligned ranges of span codes which cover hypercubes with given side.
+type regionLevel struct {
+	span uint64
+	side uint64
+}
+
+//blockLevels returns levels of aligned blocks of the curve from single cells up to the whole curve.
+func blockLevels(c BlockCurve) []regionLevel {
+	radix := c.BlockRadix()
+	sizes := DimensionSizes(c)
+	biggest := uint64(0)
+	for _, s := range sizes {
+		if s > biggest {
+			biggest = s
+		}
+	}
+	levels := []regionLevel{{span: 1, side: 1}}
+	for k := uint64(1); biggest > levels[len(levels)-1].side-1; k++ {
+		side := pow(radix, k)
+		span := blockSpan(side, sizes)
+		if side == 0 || span == 0 {
+			break
+		}
+		levels = append(levels, regionLevel{span: span, side: side})
+	}
+	return levels
+}
+
+//alignedRegion splits the range into the biggest aligned ranges of codes, each of them covers a hypercube.
+func alignedRegion(c Curve, levels []regionLevel, r Range) ([]Box, error) {
+	sizes := DimensionSizes(c)
+	buf := make([]uint64, c.Dimensions())
+	var res []Box
+	for pos := r.Min; pos < r.Max; {
+		l := levels[0]
+		for _, next := range levels[1:] {
+			if pos%next.span != 0 || r.Max-pos < next.span {
+				break
+			}
+			l = next
+		}
+		coords, err := c.DecodeWithBuffer(buf, pos)
+		if err != nil {
+			return nil, err
+		}
+		b := Box{Min: make([]uint64, len(sizes)), Max: make([]uint64, len(sizes))}
+		for i := range sizes {
+			b.Min[i] = coords[i] - coords[i]%l.side
+			b.Max[i] = b.Min[i] + (l.side - 1)
+			if b.Max[i] < b.Min[i] || b.Max[i] > sizes[i] {
+				b.Max[i] = sizes[i]
+			}
+		}
+		res = append(res, b)
+		pos += l.span
+	}
+	return res, nil
+}
+
+//cellRegion decodes each code of the range, every cell is a separate box.
+func cellRegion(c Curve, r Range) ([]Box, error) {
+	if r.Max-r.Min > maxEnumeratedCells {
+		return nil, fmt.Errorf("range exceeds limit of %d cells", maxEnumeratedCells)
+	}
+	res := make([]Box, 0, r.Max-r.Min)
+	err := Walk(c, r, func(code uint64, coords []uint64) bool {
+		res = append(res, Box{
+			Min: append([]uint64{}, coords...),
+			Max: append([]uint64{}, coords...),
+		})
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
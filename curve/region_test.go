@@ -0,0 +1,229 @@
+package curve
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRangeRegion(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Curve
+	}{
+		{"hilbert 2x3", mustCurve(t, Hilbert, 2, 3)},
+		{"hilbert 3x2", mustCurve(t, Hilbert, 3, 2)},
+		{"morton 2x3", mustCurve(t, Morton, 2, 3)},
+		{"morton 3x2", mustCurve(t, Morton, 3, 2)},
+		{"peano 2x2", mustCurve(t, Peano, 2, 2)},
+		{"moore 2x3", mustCurve(t, Moore, 2, 3)},
+		{"gray 3x2", mustCurve(t, Gray, 3, 2)},
+		{"onion 2x3", mustCurve(t, Onion, 2, 3)},
+		{"cube 3x2", mustCurve(t, Cube, 3, 2)},
+		{"hilbert uneven 2,3", mustUnevenCurve(t, Hilbert, []uint64{2, 3})},
+		{"morton uneven 1,3,2", mustUnevenCurve(t, Morton, []uint64{1, 3, 2})},
+	}
+	rnd := rand.New(rand.NewSource(42))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.c
+			ranges := []Range{NewRange(0, c.Length()+1), NewRange(0, 1), NewRange(c.Length(), c.Length()+1)}
+			for n := 0; n < 20; n++ {
+				a := uint64(rnd.Int63n(int64(c.Length()) + 1))
+				b := uint64(rnd.Int63n(int64(c.Length()) + 2))
+				ranges = append(ranges, NewRingRange(a, b, c.Length()))
+			}
+			for _, r := range ranges {
+				got, err := RangeRegion(c, r)
+				if !assert.NoError(t, err) {
+					return
+				}
+				want, err := RangeRegion(plainCurve{c}, r)
+				if !assert.NoError(t, err) {
+					return
+				}
+				var volume uint64
+				for _, b := range got {
+					volume += boxVolume(b)
+				}
+				assert.Equal(t, uint64(len(want)), volume, "range %v", r)
+				forEachCell(c, func(coords []uint64) {
+					code, err := c.Encode(append([]uint64{}, coords...))
+					assert.NoError(t, err)
+					inside := 0
+					for _, b := range got {
+						if inBox(coords, b.Min, b.Max) {
+							inside++
+						}
+					}
+					if r.Fits(code) {
+						assert.Equal(t, 1, inside, "range %v, coords %v", r, coords)
+					} else {
+						assert.Equal(t, 0, inside, "range %v, coords %v", r, coords)
+					}
+				})
+			}
+		})
+	}
+}
+
+func boxVolume(b Box) uint64 {
+	res := uint64(1)
+	for i := range b.Min {
+		res *= b.Max[i] - b.Min[i] + 1
+	}
+	return res
+}
+
+func mustUnevenCurve(t *testing.T, cType CurveType, bits []uint64) Curve {
+	c, err := NewUnevenCurve(cType, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestRangeRegion_Blocks(t *testing.T) {
+	for _, cType := range []CurveType{Hilbert, Morton} {
+		t.Run(cType.String(), func(t *testing.T) {
+			c := mustCurve(t, cType, 3, 10)
+			whole, err := RangeRegion(c, NewRange(0, c.Length()+1))
+			assert.NoError(t, err)
+			assert.Equal(t, []Box{{Min: []uint64{0, 0, 0}, Max: []uint64{1023, 1023, 1023}}}, whole)
+
+			octant, err := RangeRegion(c, NewRange(1<<27, 2<<27))
+			assert.NoError(t, err)
+			if assert.Len(t, octant, 1) {
+				assert.Equal(t, uint64(1<<27), boxVolume(octant[0]))
+			}
+
+			//unaligned range is split into a few blocks of each level
+			boxes, err := RangeRegion(c, NewRange(12345, 987654321))
+			assert.NoError(t, err)
+			assert.True(t, len(boxes) < 2*10*7, "%v boxes", len(boxes))
+			var volume uint64
+			for _, b := range boxes {
+				volume += boxVolume(b)
+			}
+			assert.Equal(t, uint64(987654321-12345), volume)
+		})
+	}
+}
+
+func TestRangeRegion_Faces(t *testing.T) {
+	c := mustCurve(t, Cube, 3, 20)
+	span := uint64(1) << 40
+	whole, err := RangeRegion(c, NewRange(span, 2*span))
+	assert.NoError(t, err)
+	assert.Equal(t, []Box{{Min: []uint64{1, 0, 0}, Max: []uint64{1, 1<<20 - 1, 1<<20 - 1}}}, whole)
+
+	//the range crosses faces 0, 1 and 2
+	r := NewRange(span-5, 2*span+7)
+	boxes, err := RangeRegion(c, r)
+	assert.NoError(t, err)
+	var volume uint64
+	for _, b := range boxes {
+		volume += boxVolume(b)
+		for _, coords := range [][]uint64{b.Min, b.Max} {
+			code, err := c.Encode(append([]uint64{}, coords...))
+			assert.NoError(t, err)
+			assert.True(t, r.Fits(code), "code %v", code)
+		}
+	}
+	assert.Equal(t, r.Len, volume)
+
+	b, err := RangeBox(c, NewRange(3*span+12345, 4*span+5))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), b.Min[0])
+	assert.Equal(t, uint64(4), b.Max[0])
+}
+
+func TestRangeRegion_Truncated(t *testing.T) {
+	wc, err := NewWideCurve(Hilbert, 3, 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := Truncate(wc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	step := uint64(1) << (c.Shift() / 3)
+	code, err := c.Encode([]uint64{step*5 + 1, step * 7, step*9 + 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := CellBox(c, code)
+	assert.NoError(t, err)
+	assert.Equal(t, Box{
+		Min: []uint64{step * 5, step * 7, step * 9},
+		Max: []uint64{step*6 - 1, step*8 - 1, step*10 - 1},
+	}, b)
+
+	r := NewRange(1000, 123456)
+	boxes, err := RangeRegion(c, r)
+	assert.NoError(t, err)
+	buf := make([]uint64, 3)
+	var volume uint64
+	for _, b := range boxes {
+		volume += boxVolume(b) / (step * step * step)
+		for _, coords := range [][]uint64{b.Min, b.Max} {
+			code, err := c.Encode(append(buf[:0], coords...))
+			assert.NoError(t, err)
+			assert.True(t, r.Fits(code), "code %v", code)
+		}
+	}
+	assert.Equal(t, r.Len, volume)
+
+	_, err = c.RangeRegion(NewRingRange(10, 5, c.Length()))
+	assert.Error(t, err)
+	_, err = c.RangeRegion(NewRange(0, c.Length()+2))
+	assert.Error(t, err)
+}
+
+func TestRangeBox(t *testing.T) {
+	c := mustCurve(t, Hilbert, 2, 3)
+	//the first quadrant of the curve
+	b, err := RangeBox(c, NewRange(0, 16))
+	assert.NoError(t, err)
+	assert.Equal(t, Box{Min: []uint64{0, 0}, Max: []uint64{3, 3}}, b)
+
+	//the first and the last cells of the curve are in adjacent quadrants
+	b, err = RangeBox(c, NewRingRange(63, 1, c.Length()))
+	assert.NoError(t, err)
+	first, _ := c.Decode(0)
+	last, _ := c.Decode(63)
+	assert.Equal(t, Box{
+		Min: []uint64{first[0], minUint(first[1], last[1])},
+		Max: []uint64{last[0], maxUint(first[1], last[1])},
+	}, b)
+
+	b, err = CellBox(c, 42)
+	assert.NoError(t, err)
+	coords, _ := c.Decode(42)
+	assert.Equal(t, Box{Min: coords, Max: coords}, b)
+
+	_, err = RangeBox(c, NewRange(64, 70))
+	assert.Error(t, err)
+	_, err = CellBox(c, 64)
+	assert.Error(t, err)
+
+	wide := mustCurve(t, Morton, 1, 64)
+	b, err = CellBox(wide, wide.Length())
+	assert.NoError(t, err)
+	assert.Equal(t, Box{Min: []uint64{wide.Length()}, Max: []uint64{wide.Length()}}, b)
+}
+
+func minUint(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxUint(a, b uint64) uint64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -1,20 +1,125 @@
 package curve
 
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/struckoff/sfcframework/curve/cube"
+	"github.com/struckoff/sfcframework/curve/gray"
+	"github.com/struckoff/sfcframework/curve/hilbert"
+	"github.com/struckoff/sfcframework/curve/moore"
+	"github.com/struckoff/sfcframework/curve/morton"
+	"github.com/struckoff/sfcframework/curve/onion"
+	"github.com/struckoff/sfcframework/curve/peano"
+)
+
 //CurveType - type of the space-filling curve to use.
 type CurveType int
 
 const (
 	Hilbert CurveType = iota //Hilbert curve
 	Morton                   //Morton curve
+	Peano                    //Peano curve
+	Moore                    //Moore curve, the closed Hilbert curve with 2 dimensions
+	Cube                     //Cube-face curve of the sphere with 3 dimensions(face, i, j)
+	Gray                     //Gray-coded Morton curve
+	Onion                    //Onion curve, cells are visited layer by layer from the outside
 )
 
+//Factory creates a curve with given amount of dimensions and size in bits of each dimension.
+type Factory func(dims, bits uint64) (Curve, error)
+
+//registry - registered curve types, CurveType is the index of the curve.
+var registry = struct {
+	mu        sync.RWMutex
+	names     []string
+	factories []Factory
+}{
+	names: []string{"Hilbert", "Morton", "Peano", "Moore", "Cube", "Gray", "Onion"},
+	factories: []Factory{
+		func(dims, bits uint64) (Curve, error) { return hilbert.New(dims, bits) },
+		func(dims, bits uint64) (Curve, error) { return morton.New(dims, bits) },
+		func(dims, bits uint64) (Curve, error) { return peano.New(dims, bits) },
+		func(dims, bits uint64) (Curve, error) { return moore.New(dims, bits) },
+		func(dims, bits uint64) (Curve, error) { return cube.New(dims, bits) },
+		func(dims, bits uint64) (Curve, error) { return gray.New(dims, bits) },
+		func(dims, bits uint64) (Curve, error) { return onion.New(dims, bits) },
+	},
+}
+
+//Register adds a curve implementation and returns the new type of the curve,
+//which could be used with NewCurve and balancer.NewBalancer.
+//
+//Register panics if the name is empty or already registered(case-insensitive), or the factory is nil.
+//It is intended to be called from init functions.
+func Register(name string, factory func(dims, bits uint64) (Curve, error)) CurveType {
+	if name == "" {
+		panic("curve: name of the curve type is empty")
+	}
+	if factory == nil {
+		panic("curve: factory of the curve type " + name + " is nil")
+	}
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	for _, n := range registry.names {
+		if strings.EqualFold(n, name) {
+			panic("curve: curve type " + name + " is already registered")
+		}
+	}
+	registry.names = append(registry.names, name)
+	registry.factories = append(registry.factories, factory)
+	return CurveType(len(registry.names) - 1)
+}
+
+//ParseCurveType returns the curve type by its name(case-insensitive).
+func ParseCurveType(name string) (CurveType, error) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	for i, n := range registry.names {
+		if strings.EqualFold(n, name) {
+			return CurveType(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown curve type %q", name)
+}
+
+//factory returns the factory of the curve type,
+//ok value represents whether the type is registered or not.
+func (c CurveType) factory() (f Factory, ok bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	if c < 0 || int(c) >= len(registry.factories) {
+		return nil, false
+	}
+	return registry.factories[c], true
+}
+
 //String - string representation of the curve type.
 func (c CurveType) String() string {
-	switch c {
-	case Hilbert:
-		return "Hilbert"
-	case Morton:
-		return "Morton"
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	if c < 0 || int(c) >= len(registry.names) {
+		return ""
+	}
+	return registry.names[c]
+}
+
+//MarshalText encodes the curve type as its name.
+func (c CurveType) MarshalText() ([]byte, error) {
+	name := c.String()
+	if name == "" {
+		return nil, fmt.Errorf("unknown curve type %d", int(c))
+	}
+	return []byte(name), nil
+}
+
+//UnmarshalText decodes the curve type from its name.
+func (c *CurveType) UnmarshalText(text []byte) error {
+	t, err := ParseCurveType(string(text))
+	if err != nil {
+		return err
 	}
-	return ""
+	*c = t
+	return nil
 }
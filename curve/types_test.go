@@ -0,0 +1,110 @@
+package curve
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/struckoff/sfcframework/curve/morton"
+)
+
+func TestCurveType_String(t *testing.T) {
+	tests := []struct {
+		name string
+		c    CurveType
+		want string
+	}{
+		{"hilbert", Hilbert, "Hilbert"},
+		{"morton", Morton, "Morton"},
+		{"peano", Peano, "Peano"},
+		{"moore", Moore, "Moore"},
+		{"cube", Cube, "Cube"},
+		{"gray", Gray, "Gray"},
+		{"onion", Onion, "Onion"},
+		{"unknown", CurveType(-1), ""},
+		{"not registered", CurveType(1 << 20), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.c.String())
+		})
+	}
+}
+
+func TestParseCurveType(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    CurveType
+		wantErr bool
+	}{
+		{"Hilbert", Hilbert, false},
+		{"morton", Morton, false},
+		{"PEANO", Peano, false},
+		{"moore", Moore, false},
+		{"", 0, true},
+		{"unknown", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCurveType(tt.name)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCurveType_JSON(t *testing.T) {
+	type config struct {
+		Curve CurveType `json:"curve"`
+	}
+	data, err := json.Marshal(config{Curve: Morton})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"curve":"Morton"}`, string(data))
+
+	var got config
+	assert.NoError(t, json.Unmarshal([]byte(`{"curve":"hilbert"}`), &got))
+	assert.Equal(t, Hilbert, got.Curve)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"curve":"unknown"}`), &got))
+	_, err = json.Marshal(config{Curve: CurveType(-1)})
+	assert.Error(t, err)
+}
+
+func TestRegister(t *testing.T) {
+	errFactory := errors.New("factory error")
+	ct := Register("test-zorder", func(dims, bits uint64) (Curve, error) {
+		if dims == 0 {
+			return nil, errFactory
+		}
+		return morton.New(dims, bits)
+	})
+	assert.Equal(t, "test-zorder", ct.String())
+
+	parsed, err := ParseCurveType("Test-ZOrder")
+	assert.NoError(t, err)
+	assert.Equal(t, ct, parsed)
+
+	c, err := NewCurve(ct, 2, 4)
+	assert.NoError(t, err)
+	assert.IsType(t, &morton.Curve{}, c)
+	_, err = NewCurve(ct, 0, 4)
+	assert.Equal(t, errFactory, err)
+
+	factory := func(dims, bits uint64) (Curve, error) { return nil, nil }
+	assert.Panics(t, func() { Register("test-zorder", factory) })
+	assert.Panics(t, func() { Register("hilbert", factory) })
+	assert.Panics(t, func() { Register("", factory) })
+	assert.Panics(t, func() { Register("test-nil", nil) })
+}
+
+func TestNewCurve_Unknown(t *testing.T) {
+	_, err := NewCurve(CurveType(-1), 2, 4)
+	assert.Error(t, err)
+	_, err = NewCurve(CurveType(1<<20), 2, 4)
+	assert.Error(t, err)
+}
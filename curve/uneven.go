@@ -0,0 +1,57 @@
+package curve
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/struckoff/sfcframework/curve/hilbert"
+	"github.com/struckoff/sfcframework/curve/morton"
+)
+
+//UnevenCurve is implemented by curves which dimensions have different sizes.
+//DimensionSize of such curves returns the maximum coordinate value which is valid in every dimension.
+type UnevenCurve interface {
+	Curve
+	DimensionSizes() []uint64 // DimensionSizes returns the maximum coordinate value of each dimension
+}
+
+//NewUnevenCurve - create a curve with different size in bits of each dimension by given type.
+//The code of the curve has as many bits as all dimensions together.
+//
+//cType - curve type(Hilbert, Morton). Hilbert curve uses the compact Hilbert index.
+//
+//bits - size in bits of each dimension, the amount of dimensions is the length of the slice.
+//
+//If all dimensions have the same size, the result is the same as NewCurve.
+func NewUnevenCurve(cType CurveType, bits []uint64) (Curve, error) {
+	if len(bits) == 0 {
+		return nil, errors.New("number of dimensions must be greater than 0")
+	}
+	even := true
+	for _, b := range bits[1:] {
+		even = even && b == bits[0]
+	}
+	if even {
+		return NewCurve(cType, uint64(len(bits)), bits[0])
+	}
+	switch cType {
+	case Hilbert:
+		return hilbert.NewCompact(bits)
+	case Morton:
+		return morton.NewUneven(bits)
+	default:
+		return nil, fmt.Errorf("curve type %v does not support different sizes of dimensions", cType)
+	}
+}
+
+//DimensionSizes returns the maximum coordinate value of each dimension of the curve.
+func DimensionSizes(c Curve) []uint64 {
+	if uc, ok := c.(UnevenCurve); ok {
+		return uc.DimensionSizes()
+	}
+	sizes := make([]uint64, c.Dimensions())
+	for i := range sizes {
+		sizes[i] = c.DimensionSize()
+	}
+	return sizes
+}
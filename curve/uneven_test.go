@@ -0,0 +1,100 @@
+package curve
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/struckoff/sfcframework/curve/hilbert"
+	"github.com/struckoff/sfcframework/curve/morton"
+)
+
+func TestNewUnevenCurve(t *testing.T) {
+	type args struct {
+		cType CurveType
+		bits  []uint64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    interface{}
+		wantErr bool
+	}{
+		{"hilbert", args{Hilbert, []uint64{20, 6}}, &hilbert.Compact{}, false},
+		{"morton", args{Morton, []uint64{20, 6}}, &morton.Uneven{}, false},
+		{"hilbert even", args{Hilbert, []uint64{4, 4, 4}}, &hilbert.Curve{}, false},
+		{"morton even", args{Morton, []uint64{4, 4}}, &morton.Curve{}, false},
+		{"peano", args{Peano, []uint64{2, 3}}, nil, true},
+		{"empty", args{Hilbert, nil}, nil, true},
+		{"too wide", args{Morton, []uint64{40, 30}}, nil, true},
+		{"zero bits", args{Hilbert, []uint64{3, 0}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewUnevenCurve(tt.args.cType, tt.args.bits)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.IsType(t, tt.want, got)
+			length := uint64(0)
+			for _, b := range tt.args.bits {
+				length += b
+			}
+			assert.Equal(t, uint64(1<<length-1), got.Length())
+			assert.Equal(t, uint64(len(tt.args.bits)), got.Dimensions())
+		})
+	}
+}
+
+func TestDimensionSizes(t *testing.T) {
+	uc, err := NewUnevenCurve(Morton, []uint64{3, 1, 2})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []uint64{7, 1, 3}, DimensionSizes(uc))
+	assert.Equal(t, uint64(1), uc.DimensionSize())
+
+	c, err := NewCurve(Hilbert, 2, 3)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []uint64{7, 7}, DimensionSizes(c))
+}
+
+func TestBoxRanges_Uneven(t *testing.T) {
+	tests := []struct {
+		name  string
+		cType CurveType
+		bits  []uint64
+	}{
+		{"hilbert 4x2", Hilbert, []uint64{4, 2}},
+		{"hilbert 1x3x2", Hilbert, []uint64{1, 3, 2}},
+		{"morton 4x2", Morton, []uint64{4, 2}},
+		{"morton 1x3x2", Morton, []uint64{1, 3, 2}},
+	}
+	rnd := rand.New(rand.NewSource(42))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewUnevenCurve(tt.cType, tt.bits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for n := 0; n < 20; n++ {
+				min, max := randomBox(rnd, c)
+				got, err := BoxRanges(c, min, max, 0)
+				if !assert.NoError(t, err) {
+					return
+				}
+				forEachCell(c, func(coords []uint64) {
+					code, err := c.Encode(append([]uint64{}, coords...))
+					assert.NoError(t, err)
+					assert.Equal(t, inBox(coords, min, max), inRanges(code, got), "coords %v, code %v", coords, code)
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package curve
+
+//WalkFunc is called for each visited code with coordinates of the cell.
+//Coordinates are stored in the buffer which is reused between calls, so they must be copied to be retained.
+//The walk stops if WalkFunc returns false.
+type WalkFunc func(code uint64, coords []uint64) bool
+
+//Walker is implemented by curves which decode consecutive codes incrementally.
+type Walker interface {
+	Curve
+	//Walk calls fn for each code from first to last(inclusive) in order, coordinates are decoded into buf.
+	Walk(buf []uint64, first, last uint64, fn func(code uint64, coords []uint64) bool) error
+}
+
+//Walk calls fn for each code of the range [Min, Max) in order, codes beyond Length() are skipped.
+//Coordinates are decoded into a single buffer, which is reused for every code.
+//
+//Wrapping range is walked from Min to the end of the curve and from zero to Max.
+//
+//Curves which implement Walker decode codes incrementally,
+//other curves decode each code by DecodeWithBuffer.
+func Walk(c Curve, r Range, fn WalkFunc) error {
+	buf := make([]uint64, c.Dimensions())
+	if r.Wraps() {
+		if r.Min <= c.Length() {
+			stopped := false
+			err := walk(c, buf, r.Min, c.Length(), func(code uint64, coords []uint64) bool {
+				stopped = !fn(code, coords)
+				return !stopped
+			})
+			if err != nil || stopped {
+				return err
+			}
+		}
+		r.Min = 0
+	}
+	if r.Min >= r.Max || r.Min > c.Length() {
+		return nil
+	}
+	last := r.Max - 1
+	if last > c.Length() {
+		last = c.Length()
+	}
+	return walk(c, buf, r.Min, last, fn)
+}
+
+//walk calls fn for each code from first to last(inclusive).
+func walk(c Curve, buf []uint64, first, last uint64, fn WalkFunc) error {
+	if w, ok := c.(Walker); ok {
+		return w.Walk(buf, first, last, fn)
+	}
+	for code := first; ; code++ {
+		coords, err := c.DecodeWithBuffer(buf, code)
+		if err != nil {
+			return err
+		}
+		if !fn(code, coords) || code == last {
+			return nil
+		}
+	}
+}
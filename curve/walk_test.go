@@ -0,0 +1,106 @@
+package curve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalk(t *testing.T) {
+	tests := []struct {
+		name  string
+		cType CurveType
+		dims  uint64
+		bits  uint64
+		r     Range
+	}{
+		{"hilbert 2x4 whole", Hilbert, 2, 4, NewRange(0, 256)},
+		{"hilbert 2x4 beyond length", Hilbert, 2, 4, NewRange(200, 1000)},
+		{"hilbert 3x3", Hilbert, 3, 3, NewRange(7, 300)},
+		{"hilbert 4x2", Hilbert, 4, 2, NewRange(3, 250)},
+		{"hilbert 2x32 tail", Hilbert, 2, 32, NewRange(1<<64-1000, 1<<64-1)},
+		{"morton 2x4", Morton, 2, 4, NewRange(5, 256)},
+		{"morton 3x3", Morton, 3, 3, NewRange(0, 511)},
+		{"peano 2x3", Peano, 2, 3, NewRange(10, 500)},
+		{"gray 3x3", Gray, 3, 3, NewRange(3, 500)},
+		{"onion 3x3", Onion, 3, 3, NewRange(3, 500)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCurve(tt.cType, tt.dims, tt.bits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			next := tt.r.Min
+			err = Walk(c, tt.r, func(code uint64, coords []uint64) bool {
+				if !assert.Equal(t, next, code) {
+					return false
+				}
+				want, err := c.Decode(code)
+				assert.NoError(t, err)
+				next++
+				return assert.Equal(t, want, coords, "code %v", code)
+			})
+			assert.NoError(t, err)
+			end := tt.r.Max
+			if end > c.Length() {
+				end = c.Length() + 1
+			}
+			assert.Equal(t, end, next)
+		})
+	}
+}
+
+func TestWalk_Ring(t *testing.T) {
+	for _, cType := range []CurveType{Hilbert, Morton, Peano, Moore, Gray, Onion} {
+		c, err := NewCurve(cType, 2, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var codes []uint64
+		err = Walk(c, NewRingRange(c.Length()-2, 3, c.Length()), func(code uint64, coords []uint64) bool {
+			want, err := c.Decode(code)
+			assert.NoError(t, err)
+			assert.Equal(t, want, coords, "code %v", code)
+			codes = append(codes, code)
+			return true
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{c.Length() - 2, c.Length() - 1, c.Length(), 0, 1, 2}, codes, "%v", cType)
+
+		codes = codes[:0]
+		err = Walk(c, NewRingRange(c.Length()-2, 3, c.Length()), func(code uint64, coords []uint64) bool {
+			codes = append(codes, code)
+			return code != c.Length()
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{c.Length() - 2, c.Length() - 1, c.Length()}, codes, "%v", cType)
+	}
+}
+
+func TestWalk_Stop(t *testing.T) {
+	for _, cType := range []CurveType{Hilbert, Morton, Peano, Gray, Onion} {
+		c, err := NewCurve(cType, 2, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var codes []uint64
+		err = Walk(c, NewRange(3, 20), func(code uint64, coords []uint64) bool {
+			codes = append(codes, code)
+			return code < 5
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{3, 4, 5}, codes)
+
+		err = Walk(c, NewRange(c.Length()+1, c.Length()+10), func(code uint64, coords []uint64) bool {
+			t.Errorf("code %v is beyond the curve", code)
+			return true
+		})
+		assert.NoError(t, err)
+		err = Walk(c, Range{}, func(code uint64, coords []uint64) bool {
+			t.Errorf("code %v is beyond the range", code)
+			return true
+		})
+		assert.NoError(t, err)
+	}
+}
@@ -0,0 +1,254 @@
+package curve
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/struckoff/sfcframework/curve/hilbert"
+	"github.com/struckoff/sfcframework/curve/morton"
+)
+
+//maxTruncatedBits - the maximum size in bits of the code produced by Truncated curve.
+//One bit is reserved, so Length() + 1 still fits uint64.
+const maxTruncatedBits = 63
+
+//WideCurve is an interface of space filling curve realisation which codes are not limited by 64 bits.
+type WideCurve interface {
+	DecodeWide(code *big.Int) (coords []uint64, err error) //DecodeWide returns coordinates for a given code(distance)
+	EncodeWide(coords []uint64) (code *big.Int, err error) //EncodeWide returns code(distance) for a given set of coordinates
+	LengthWide() *big.Int                                  //LengthWide returns the maximum distance along curve
+	DimensionSize() uint64                                 // DimensionSize returns the maximum coordinate value in any dimension
+	Dimensions() uint64                                    // Dimensions - amount of curve dimensions
+	Bits() uint64                                          // Bits - size in bits of each dimension
+}
+
+//NewWideCurve - create a curve with wide codes by given type
+//
+//cType - curve type(Hilbert, Morton)
+//
+//dims - amount of curve dimensions.
+//
+//bits - size in bits of each dimension, must be less or equal than 64.
+func NewWideCurve(cType CurveType, dims, bits uint64) (WideCurve, error) {
+	if bits > 64 {
+		return nil, errors.New("number of bits must be less or equal than 64")
+	}
+	switch cType {
+	case Hilbert:
+		return hilbert.NewWide(dims, bits)
+	case Morton:
+		return morton.NewWide(dims, bits)
+	default:
+		return nil, fmt.Errorf("curve type %v does not support wide codes", cType)
+	}
+}
+
+//Truncated adapts WideCurve to the Curve interface.
+//
+//Coordinates are accepted in full resolution of the wide curve,
+//but the code of the cell is only the most significant bits of the wide code.
+//Dropped bits are a multiple of the number of dimensions,
+//so each cell is an aligned hypercube of the wide curve and cells keep the order of the wide curve.
+//
+//Space, Range and cell groups work with 64-bit codes only, so the balancer with a wide curve(see NewBalancer)
+//runs at the truncated resolution: at most 63 / dims bits of each dimension are distinguished
+//and data of one truncated cell always belongs to the same node.
+//Boxes are widened to whole truncated cells(see BoxRanges).
+type Truncated struct {
+	wc     WideCurve
+	shift  uint64 //amount of dropped bits of the wide code
+	length uint64 //biggest code which could be decoded by curve
+}
+
+//Truncate creates a curve which codes are the leading bits of the given wide curve codes.
+//It keeps as many levels of the wide curve as fit 63 bits.
+func Truncate(wc WideCurve) (*Truncated, error) {
+	dims := wc.Dimensions()
+	if dims == 0 || dims > maxTruncatedBits {
+		return nil, fmt.Errorf("number of dimensions must be in range [1, %d]", maxTruncatedBits)
+	}
+	levels := wc.Bits()
+	if levels*dims > maxTruncatedBits {
+		levels = maxTruncatedBits / dims
+	}
+	t := &Truncated{
+		wc:    wc,
+		shift: (wc.Bits() - levels) * dims,
+	}
+	t.length = t.Prefix(wc.LengthWide())
+	return t, nil
+}
+
+//Prefix returns the code of Truncated curve cell which contains given wide code.
+func (t *Truncated) Prefix(code *big.Int) uint64 {
+	return new(big.Int).Rsh(code, uint(t.shift)).Uint64()
+}
+
+//Decode returns coordinates of the first wide curve cell inside the cell with given code.
+//
+//Method will return error if code(distance) exceeds the limit.
+func (t *Truncated) Decode(code uint64) (coords []uint64, err error) {
+	if code > t.length {
+		return nil, fmt.Errorf("code == %v exceeds limit == %v", code, t.length)
+	}
+	wc := new(big.Int).SetUint64(code)
+	return t.wc.DecodeWide(wc.Lsh(wc, uint(t.shift)))
+}
+
+//DecodeWithBuffer returns coordinates of the first wide curve cell inside the cell with given code.
+// Method will return error if:
+//
+// - buffer less than number of dimensions
+//
+// - code(distance) exceeds the limit
+func (t *Truncated) DecodeWithBuffer(buf []uint64, code uint64) (coords []uint64, err error) {
+	if len(buf) < int(t.wc.Dimensions()) {
+		return nil, errors.New("buffer length less then dimensions")
+	}
+	coords, err = t.Decode(code)
+	if err != nil {
+		return nil, err
+	}
+	copy(buf, coords)
+	return buf, nil
+}
+
+//Encode returns code(distance) of the cell which contains given coordinates.
+//
+//Method will return error if any of the coordinates exceeds limit(2 ^ bits - 1)
+func (t *Truncated) Encode(coords []uint64) (code uint64, err error) {
+	wc, err := t.wc.EncodeWide(coords)
+	if err != nil {
+		return 0, err
+	}
+	return t.Prefix(wc), nil
+}
+
+// DimensionSize returns the maximum coordinate value in any dimension
+func (t *Truncated) DimensionSize() uint64 {
+	return t.wc.DimensionSize()
+}
+
+// Length returns the maximum distance along curve(code value).
+func (t *Truncated) Length() uint64 {
+	return t.length
+}
+
+//Dimensions - amount of curve dimensions
+func (t *Truncated) Dimensions() uint64 {
+	return t.wc.Dimensions()
+}
+
+//Bits - size in bits of each dimension
+func (t *Truncated) Bits() uint64 {
+	return t.wc.Bits()
+}
+
+//AppendNeighbors appends codes of face neighbors(2 * dims) or all 3^dims - 1 neighbors of the cell to out.
+//Each cell of the curve is a hypercube of the wide curve, so coordinates are moved by the side of the hypercube.
+//If wrap is true, the grid is toroidal, otherwise neighbors beyond the grid are skipped.
+func (t *Truncated) AppendNeighbors(out []uint64, code uint64, wrap, all bool) ([]uint64, error) {
+	step := uint64(1) << (t.shift / t.Dimensions())
+	return appendNeighbors(t, out, code, step, wrap, all)
+}
+
+//RangeRegion returns disjoint boxes which cover exactly cells of the wide curve of the range of codes.
+//Each code is a hypercube of the wide curve, aligned ranges of codes are bigger hypercubes.
+//
+//Method will return error if the range wraps or exceeds the limit.
+func (t *Truncated) RangeRegion(r Range) ([]Box, error) {
+	if r.Wraps() {
+		return nil, errors.New("range must not wrap")
+	}
+	if r.Max > t.length+1 {
+		return nil, fmt.Errorf("range maximum == %v exceeds limit == %v", r.Max, t.length+1)
+	}
+	dims := t.Dimensions()
+	unit := t.shift / dims
+	levels := make([]regionLevel, 0, t.wc.Bits()-unit+1)
+	for k := uint64(0); k+unit <= t.wc.Bits() && k+unit < 64; k++ {
+		levels = append(levels, regionLevel{span: 1 << (k * dims), side: 1 << (k + unit)})
+	}
+	return alignedRegion(t, levels, r)
+}
+
+//BoxRanges returns ranges of codes of cells which overlap the box, the box is given in coordinates of the wide curve.
+//Cells are aligned hypercubes of the wide curve, so the box is widened to whole cells
+//and decomposed block by block on the grid of cells.
+func (t *Truncated) BoxRanges(min, max []uint64, limit int) ([]Range, error) {
+	g := cellGrid{t: t, unit: t.shift / t.Dimensions()}
+	gmin := make([]uint64, g.Dimensions())
+	gmax := make([]uint64, g.Dimensions())
+	for i := range gmin {
+		gmin[i] = min[i] >> g.unit
+		gmax[i] = max[i] >> g.unit
+	}
+	return blockRanges(g, gmin, gmax, limit)
+}
+
+//Shift - amount of dropped bits of the wide code.
+func (t *Truncated) Shift() uint64 {
+	return t.shift
+}
+
+//Wide returns the underlying curve with wide codes.
+func (t *Truncated) Wide() WideCurve {
+	return t.wc
+}
+
+//cellGrid - the view of Truncated curve where each cell has its own coordinates, a coordinate of the wide curve divided by the side of the cell.
+//It visits aligned blocks of cells as contiguous ranges of codes, like the wide curve does.
+type cellGrid struct {
+	t    *Truncated
+	unit uint64 //the side of the cell is 2^unit cells of the wide curve
+}
+
+func (g cellGrid) Decode(code uint64) ([]uint64, error) {
+	return g.DecodeWithBuffer(make([]uint64, g.Dimensions()), code)
+}
+
+func (g cellGrid) DecodeWithBuffer(buf []uint64, code uint64) ([]uint64, error) {
+	coords, err := g.t.DecodeWithBuffer(buf, code)
+	if err != nil {
+		return nil, err
+	}
+	for i := range coords[:g.Dimensions()] {
+		coords[i] >>= g.unit
+	}
+	return coords, nil
+}
+
+func (g cellGrid) Encode(coords []uint64) (uint64, error) {
+	if len(coords) != int(g.Dimensions()) {
+		return 0, fmt.Errorf("number of coordinates == %v mismatch with dimensions == %v", len(coords), g.Dimensions())
+	}
+	wide := make([]uint64, len(coords))
+	for i, c := range coords {
+		if c > g.DimensionSize() {
+			return 0, fmt.Errorf("coordinate == %v exceeds limit == %v", c, g.DimensionSize())
+		}
+		wide[i] = c << g.unit
+	}
+	return g.t.Encode(wide)
+}
+
+func (g cellGrid) DimensionSize() uint64 {
+	return g.t.DimensionSize() >> g.unit
+}
+
+func (g cellGrid) Length() uint64 {
+	return g.t.Length()
+}
+
+func (g cellGrid) Dimensions() uint64 {
+	return g.t.Dimensions()
+}
+
+func (g cellGrid) Bits() uint64 {
+	return g.t.Bits() - g.unit
+}
+
+func (g cellGrid) BlockRadix() uint64 {
+	return 2
+}
@@ -0,0 +1,214 @@
+package curve
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWideCurve(t *testing.T) {
+	type args struct {
+		cType CurveType
+		dims  uint64
+		bits  uint64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{"hilbert 4x32", args{Hilbert, 4, 32}, false},
+		{"morton 8x64", args{Morton, 8, 64}, false},
+		{"peano", args{Peano, 4, 32}, true},
+		{"bits exceeds limit", args{Morton, 2, 65}, true},
+		{"unknown", args{CurveType(42), 2, 2}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewWideCurve(tt.args.cType, tt.args.dims, tt.args.bits)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.args.dims, got.Dimensions())
+			assert.Equal(t, tt.args.bits, got.Bits())
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	type args struct {
+		cType CurveType
+		dims  uint64
+		bits  uint64
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantShift  uint64
+		wantLength uint64
+		wantErr    bool
+	}{
+		{"fits", args{Hilbert, 2, 8}, 0, 65535, false},
+		{"4x32", args{Hilbert, 4, 32}, 68, 1<<60 - 1, false},
+		{"3x64", args{Morton, 3, 64}, 129, 1<<63 - 1, false},
+		{"64 dimensions", args{Morton, 64, 2}, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wc, err := NewWideCurve(tt.args.cType, tt.args.dims, tt.args.bits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Truncate(wc)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantShift, got.Shift())
+			assert.Equal(t, tt.wantLength, got.Length())
+			assert.Equal(t, wc, got.Wide())
+			assert.Equal(t, wc.Dimensions(), got.Dimensions())
+			assert.Equal(t, wc.Bits(), got.Bits())
+			assert.Equal(t, wc.DimensionSize(), got.DimensionSize())
+		})
+	}
+}
+
+func TestTruncated_Encode(t *testing.T) {
+	wc, err := NewWideCurve(Hilbert, 4, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc, err := Truncate(wc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	coords := []uint64{4294967295, 0, 0, 0}
+	code, err := tc.Encode(coords)
+	assert.NoError(t, err)
+	assert.Equal(t, tc.Length(), code)
+
+	wcode, err := wc.EncodeWide([]uint64{1, 2, 3, 4})
+	assert.NoError(t, err)
+	code, err = tc.Encode([]uint64{1, 2, 3, 4})
+	assert.NoError(t, err)
+	assert.Equal(t, tc.Prefix(wcode), code)
+	assert.Equal(t, uint64(0), code)
+
+	_, err = tc.Encode([]uint64{1 << 32, 0, 0, 0})
+	assert.Error(t, err)
+}
+
+func TestTruncated_Decode(t *testing.T) {
+	wc, err := NewWideCurve(Morton, 4, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc, err := Truncate(wc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	coords, err := tc.Decode(tc.Length())
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{0xfffe0000, 0xfffe0000, 0xfffe0000, 0xfffe0000}, coords)
+	code, err := tc.Encode(coords)
+	assert.NoError(t, err)
+	assert.Equal(t, tc.Length(), code)
+
+	buf := make([]uint64, 5)
+	coords, err = tc.DecodeWithBuffer(buf, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1 << 17, 0, 0, 0, 0}, coords)
+	wcode, err := wc.EncodeWide(coords)
+	assert.NoError(t, err)
+	assert.Equal(t, new(big.Int).Lsh(big.NewInt(1), uint(tc.Shift())), wcode)
+
+	_, err = tc.Decode(tc.Length() + 1)
+	assert.Error(t, err)
+	_, err = tc.DecodeWithBuffer(make([]uint64, 3), 1)
+	assert.Error(t, err)
+}
+
+func TestTruncated_BoxRanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		cType    CurveType
+		dims     uint64
+		bits     uint64
+		min, max []uint64
+	}{
+		{"hilbert 4x32", Hilbert, 4, 32, []uint64{0, 0, 0, 0}, []uint64{1 << 20, 1 << 20, 1 << 20, 1 << 20}},
+		{"hilbert 4x32 unaligned", Hilbert, 4, 32, []uint64{12345, 1 << 22, 3 << 17, 0}, []uint64{1 << 21, 5<<20 + 7, 3<<17 + 1, 1 << 20}},
+		{"morton 3x64", Morton, 3, 64, []uint64{1 << 50, 0, 1<<63 + 1}, []uint64{1<<51 + 1, 1 << 45, 1<<63 + 1<<46}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wc, err := NewWideCurve(tt.cType, tt.dims, tt.bits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc, err := Truncate(wc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := BoxRanges(tc, tt.min, tt.max, 0)
+			if !assert.NoError(t, err) {
+				return
+			}
+			//every cell which overlaps the box is covered and nothing else
+			unit := tc.Shift() / tc.Dimensions()
+			lo := make([]uint64, len(tt.min))
+			hi := make([]uint64, len(tt.max))
+			for i := range lo {
+				lo[i], hi[i] = tt.min[i]>>unit, tt.max[i]>>unit
+			}
+			want := map[uint64]bool{}
+			idx := append([]uint64{}, lo...)
+			for {
+				coords := make([]uint64, len(idx))
+				for i := range idx {
+					coords[i] = idx[i] << unit
+				}
+				code, err := tc.Encode(coords)
+				if !assert.NoError(t, err) {
+					return
+				}
+				want[code] = true
+				i := 0
+				for ; i < len(idx); i++ {
+					if idx[i] < hi[i] {
+						idx[i]++
+						break
+					}
+					idx[i] = lo[i]
+				}
+				if i == len(idx) {
+					break
+				}
+			}
+			covered := uint64(0)
+			for _, r := range got {
+				covered += r.Max - r.Min
+			}
+			assert.Equal(t, uint64(len(want)), covered)
+			for code := range want {
+				if !assert.True(t, inRanges(code, got), "code %v", code) {
+					return
+				}
+			}
+
+			limited, err := BoxRanges(tc, tt.min, tt.max, 3)
+			assert.NoError(t, err)
+			assert.True(t, len(limited) <= 3)
+			for _, r := range got {
+				assert.True(t, inRanges(r.Min, limited) && inRanges(r.Max-1, limited))
+			}
+		})
+	}
+}
@@ -52,6 +52,79 @@ func RangeOptimizer(s *balancer.Space) (res []*balancer.CellGroup, err error) {
 	return cgs, nil
 }
 
+//RingRangeOptimizer - divide curve into segments as RangeOptimizer does,
+//but the curve is treated as a ring: the first segment starts from offset
+//and the last one wraps from the end of the curve back to zero.
+//
+//It is intended for closed curves(curve.Moore), which last cell is adjacent to the first one,
+//so cells around the start of the curve could be attached to the same cell group.
+//
+//If the curve has 2^64 codes(Length is the maximum uint64 value), the ring does not fit uint64,
+//so the last code is left out of the ring as Range.Split does.
+func RingRangeOptimizer(offset uint64) balancer.OptimizerFunc {
+	return func(s *balancer.Space) (res []*balancer.CellGroup, err error) {
+		totalPower := s.TotalPower()
+		cgs := s.CellGroups()
+		if len(cgs) == 0 {
+			return res, nil
+		}
+		length := s.Capacity()
+		size := length + 1
+		if size == 0 {
+			size = length
+		}
+		offset %= size
+		var max, min uint64
+
+		sort.Slice(cgs, func(i, j int) bool { return cgs[i].Node().Hash() < cgs[j].Node().Hash() })
+
+		bounds := make([][2]uint64, len(cgs))
+		for i := 0; i < len(cgs); i++ {
+			min = max
+			p := cgs[i].Node().Power().Get() / totalPower
+			if l := math.Round(float64(length) * p); l < float64(size-min) {
+				max = min + uint64(l)
+			} else {
+				max = size
+			}
+			bounds[i] = [2]uint64{min, max}
+		}
+		//segments cover the whole ring, the last one ends at the start of the first.
+		bounds[len(bounds)-1][1] = size
+
+		for i := range cgs {
+			l := bounds[i][1] - bounds[i][0]
+			//sums may overflow uint64 if the ring is close to 2^64 codes
+			min = bounds[i][0] + offset
+			if min >= size || min < offset {
+				min -= size
+			}
+			max = min + l
+			if max > size || max < min {
+				max -= size
+			}
+			if l > 0 && min == max {
+				min, max = 0, size
+			}
+			if err := cgs[i].SetRingRange(min, max, length); err != nil {
+				return nil, errors.Wrap(err, "ring range optimizer error")
+			}
+		}
+
+		cells := s.Cells()
+		for i := range cells {
+			for cgi := range cgs {
+				if cgs[cgi].FitsRange(cells[i].ID()) {
+					cells[i].Group().RemoveCell(cells[i].ID())
+					cgs[cgi].AddCell(cells[i])
+					break
+				}
+			}
+		}
+		return cgs, nil
+	}
+}
+
 //PowerRangeOptimizer - divide curve into segments.
 //Length of each segment depends on nodes power and capacity.
 //func PowerRangeOptimizer(s *balancer.Space) (res []*balancer.CellGroup, err error) {
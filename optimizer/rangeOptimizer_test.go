@@ -2,6 +2,7 @@ package optimizer
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/struckoff/sfcframework/node"
@@ -126,6 +127,225 @@ func TestRangeOptimizer(t *testing.T) {
 	}
 }
 
+func TestRingRangeOptimizer(t *testing.T) {
+	type args struct {
+		offset uint64
+		powers []float64
+		cType  curve.CurveType
+		dims   uint64
+		bits   uint64
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantRanges [][2]uint64
+	}{
+		{
+			"no nodes",
+			args{
+				offset: 512,
+				powers: []float64{},
+				cType:  curve.Moore,
+				dims:   2,
+				bits:   6,
+			},
+			[][2]uint64{},
+		},
+		{
+			"no offset",
+			args{
+				offset: 0,
+				powers: []float64{1, 1, 1, 1},
+				cType:  curve.Moore,
+				dims:   2,
+				bits:   6,
+			},
+			[][2]uint64{
+				{0, 1024},
+				{1024, 2048},
+				{2048, 3072},
+				{3072, 4096},
+			},
+		},
+		{
+			"equal 4 nodes",
+			args{
+				offset: 512,
+				powers: []float64{1, 1, 1, 1},
+				cType:  curve.Moore,
+				dims:   2,
+				bits:   6,
+			},
+			[][2]uint64{
+				{512, 1536},
+				{1536, 2560},
+				{2560, 3584},
+				{3584, 512},
+			},
+		},
+		{
+			"not equal 4 nodes",
+			args{
+				offset: 4096 + 3000,
+				powers: []float64{0, 1, 0, 1},
+				cType:  curve.Morton,
+				dims:   3,
+				bits:   4,
+			},
+			[][2]uint64{
+				{3000, 3000},
+				{3000, 952},
+				{952, 952},
+				{952, 3000},
+			},
+		},
+		{
+			"single node",
+			args{
+				offset: 100,
+				powers: []float64{1},
+				cType:  curve.Moore,
+				dims:   2,
+				bits:   6,
+			},
+			[][2]uint64{
+				{0, 4096},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sfc, err := curve.NewCurve(tt.args.cType, tt.args.dims, tt.args.bits)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var nodes []node.Node
+			var rgs []*balancer.CellGroup
+
+			if len(tt.args.powers) > 0 {
+				nodes = make([]node.Node, len(tt.args.powers))
+				rgs = make([]*balancer.CellGroup, len(tt.args.powers))
+			}
+
+			for i := range tt.args.powers {
+				p := &mocks.Power{}
+				p.On("Get").Return(tt.args.powers[i])
+				n := &mocks.Node{}
+				n.On("Power").Return(p)
+				n.On("Hash").Return(uint64(i))
+				n.On("ID").Return(fmt.Sprintf("node-%d", i))
+				nodes[i] = n
+				rgs[i] = balancer.NewCellGroup(n)
+				err := rgs[i].SetRingRange(tt.wantRanges[i][0], tt.wantRanges[i][1], sfc.Length())
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			s, err := balancer.NewSpace(sfc, nil, nodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := RingRangeOptimizer(tt.args.offset)(s)
+			assert.NoError(t, err)
+			assert.Equal(t, rgs, got)
+			if len(got) == 0 {
+				return
+			}
+			for _, code := range []uint64{0, sfc.Length()} {
+				fits := 0
+				for _, cg := range got {
+					if cg.FitsRange(code) {
+						fits++
+					}
+				}
+				assert.Equal(t, 1, fits, "code %v", code)
+			}
+		})
+	}
+}
+
+func TestRingRangeOptimizer_FullLength(t *testing.T) {
+	tests := []struct {
+		name       string
+		powers     []float64
+		wantRanges [][2]uint64
+	}{
+		{
+			"equal 4 nodes",
+			[]float64{1, 1, 1, 1},
+			[][2]uint64{
+				{1<<63 + 5, 3<<62 + 5},
+				{3<<62 + 5, 6},
+				{6, 1<<62 + 6},
+				{1<<62 + 6, 1<<63 + 5},
+			},
+		},
+		{
+			"single node",
+			[]float64{1},
+			[][2]uint64{
+				{0, math.MaxUint64},
+			},
+		},
+		{
+			"node without power",
+			[]float64{1, 0},
+			[][2]uint64{
+				{0, math.MaxUint64},
+				{1<<63 + 5, 1<<63 + 5},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sfc, err := curve.NewCurve(curve.Moore, 2, 32)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !assert.Equal(t, uint64(math.MaxUint64), sfc.Length()) {
+				return
+			}
+			nodes := make([]node.Node, len(tt.powers))
+			for i := range tt.powers {
+				p := &mocks.Power{}
+				p.On("Get").Return(tt.powers[i])
+				n := &mocks.Node{}
+				n.On("Power").Return(p)
+				n.On("Hash").Return(uint64(i))
+				n.On("ID").Return(fmt.Sprintf("node-%d", i))
+				nodes[i] = n
+			}
+			s, err := balancer.NewSpace(sfc, nil, nodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := RingRangeOptimizer(1<<63 + 5)(s)
+			if !assert.NoError(t, err) {
+				return
+			}
+			gotRanges := make([][2]uint64, len(got))
+			for i, cg := range got {
+				gotRanges[i] = [2]uint64{cg.Range().Min, cg.Range().Max}
+			}
+			assert.Equal(t, tt.wantRanges, gotRanges)
+			//the last code is left out of the ring, every other code belongs to a single cell group
+			for _, code := range []uint64{0, 5, 6, 1<<63 + 4, 1<<63 + 5, math.MaxUint64 - 1} {
+				fits := 0
+				for _, cg := range got {
+					if cg.FitsRange(code) {
+						fits++
+					}
+				}
+				assert.Equal(t, 1, fits, "code %v", code)
+			}
+		})
+	}
+}
+
 //func TestPowerRangeOptimizer(t *testing.T) {
 //	type args struct {
 //		loadSet []uint64
@@ -1,22 +1,18 @@
 package balancer
 
+import "github.com/struckoff/sfcframework/curve"
+
 //Range - range of cells IDs attached to the cell group.
-type Range struct {
-	Min uint64
-	Max uint64
-	Len uint64
-}
+type Range = curve.Range
 
 //NewRange - creates a new range by specified limits.
 func NewRange(min, max uint64) Range {
-	return Range{
-		Min: min,
-		Max: max,
-		Len: max - min,
-	}
+	return curve.NewRange(min, max)
 }
 
-//Fits <- min <= index < max
-func (r *Range) Fits(index uint64) bool {
-	return index >= r.Min && index < r.Max
+//NewRingRange - creates a new range which could wrap from the end of the curve back to zero.
+//
+//length - the maximum code of the curve(Space.Capacity).
+func NewRingRange(min, max, length uint64) Range {
+	return curve.NewRingRange(min, max, length)
 }
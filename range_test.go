@@ -45,6 +45,42 @@ func TestRange_Fits(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "wraps, end",
+			fields: fields{
+				Min: 250,
+				Max: 5,
+				Len: 11,
+			},
+			args: args{
+				index: 255,
+			},
+			want: true,
+		},
+		{
+			name: "wraps, start",
+			fields: fields{
+				Min: 250,
+				Max: 5,
+				Len: 11,
+			},
+			args: args{
+				index: 4,
+			},
+			want: true,
+		},
+		{
+			name: "wraps, false",
+			fields: fields{
+				Min: 250,
+				Max: 5,
+				Len: 11,
+			},
+			args: args{
+				index: 5,
+			},
+			want: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,264 @@
+/*
+	Rendering of the space for capacity reviews.
+	Only the standard library is used, the picture is a plain SVG document.
+*/
+package render
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	balancer "github.com/struckoff/sfcframework"
+	"github.com/struckoff/sfcframework/curve"
+)
+
+const (
+	defaultResolution = 256
+	defaultMaxPath    = 1 << 12
+	defaultWidth      = 512.0
+	legendLine        = 16.0
+)
+
+//Options of the rendering, zero values are replaced by defaults.
+type Options struct {
+	Axes       [2]uint64 //dimensions drawn horizontally and vertically, {0, 1} if both are zero
+	Slice      []uint64  //coordinates of other dimensions of cells which define cell groups of the picture, zeros by default
+	Resolution uint64    //maximum amount of cells drawn along each axis, 256 by default
+	CellSize   float64   //side of the drawn cell in pixels, by default the picture is about 512 pixels wide
+	MaxPath    uint64    //curve path is drawn if the curve has at most MaxPath cells, 4096 by default
+}
+
+//SVG renders the space with 2 or more dimensions as SVG document.
+//
+//The picture shows cells coloured by the node of the cell group they belong to,
+//load of cells as a heat map, the path of the curve and the legend of nodes.
+//
+//Spaces with more than 2 dimensions are projected onto Options.Axes:
+//loads of cells are summed along other dimensions, cell groups are taken from the slice of the space at Options.Slice.
+//Big spaces are scaled down to Options.Resolution cells along each axis,
+//the curve path is drawn only for curves with at most Options.MaxPath cells.
+func SVG(w io.Writer, s *balancer.Space, opts Options) error {
+	sfc := s.SFC()
+	p, err := newPicture(sfc, opts)
+	if err != nil {
+		return err
+	}
+	cgs := s.CellGroups()
+	if err := p.groups(cgs); err != nil {
+		return err
+	}
+	if err := p.load(s); err != nil {
+		return err
+	}
+	if err := p.path(); err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	p.write(bw, cgs)
+	return bw.Flush()
+}
+
+//picture - projection of the space onto the grid of drawn cells.
+type picture struct {
+	sfc    curve.Curve
+	opts   Options
+	sizes  []uint64  //maximum coordinate value of each dimension
+	cols   [2]uint64 //amount of drawn cells along each axis
+	owner  [][]int   //[x][y] -> index of the cell group, -1 if cell does not belong to any group
+	heat   [][]uint64
+	points [][2]uint64 //drawn cells visited by the curve
+}
+
+func newPicture(sfc curve.Curve, opts Options) (*picture, error) {
+	dims := sfc.Dimensions()
+	if dims < 2 {
+		return nil, errors.New("number of dimensions must be at least 2")
+	}
+	if opts.Axes == [2]uint64{} {
+		opts.Axes = [2]uint64{0, 1}
+	}
+	if opts.Axes[0] == opts.Axes[1] || opts.Axes[0] >= dims || opts.Axes[1] >= dims {
+		return nil, fmt.Errorf("axes %v must be different dimensions less than %v", opts.Axes, dims)
+	}
+	if opts.Slice != nil && uint64(len(opts.Slice)) < dims {
+		return nil, fmt.Errorf("slice length == %v less then dimensions == %v", len(opts.Slice), dims)
+	}
+	if opts.Resolution == 0 {
+		opts.Resolution = defaultResolution
+	}
+	if opts.MaxPath == 0 {
+		opts.MaxPath = defaultMaxPath
+	}
+	p := &picture{
+		sfc:   sfc,
+		opts:  opts,
+		sizes: curve.DimensionSizes(sfc),
+	}
+	for i, axis := range opts.Axes {
+		p.cols[i] = opts.Resolution
+		if p.sizes[axis] < opts.Resolution {
+			p.cols[i] = p.sizes[axis] + 1
+		}
+	}
+	if p.opts.CellSize <= 0 {
+		p.opts.CellSize = math.Max(1, math.Floor(defaultWidth/float64(p.cols[0])))
+	}
+	p.owner = make([][]int, p.cols[0])
+	p.heat = make([][]uint64, p.cols[0])
+	for x := range p.owner {
+		p.owner[x] = make([]int, p.cols[1])
+		p.heat[x] = make([]uint64, p.cols[1])
+	}
+	return p, nil
+}
+
+//column returns the drawn cell of the coordinate along the axis.
+func (p *picture) column(axis int, coord uint64) uint64 {
+	size := float64(p.sizes[p.opts.Axes[axis]]) + 1
+	col := uint64(float64(coord) / size * float64(p.cols[axis]))
+	if col >= p.cols[axis] {
+		col = p.cols[axis] - 1
+	}
+	return col
+}
+
+//coord returns the coordinate of the center of the drawn cell along the axis.
+func (p *picture) coord(axis int, col uint64) uint64 {
+	size := float64(p.sizes[p.opts.Axes[axis]]) + 1
+	coord := uint64((float64(col) + 0.5) * size / float64(p.cols[axis]))
+	if coord > p.sizes[p.opts.Axes[axis]] {
+		coord = p.sizes[p.opts.Axes[axis]]
+	}
+	return coord
+}
+
+//groups finds the cell group of each drawn cell.
+func (p *picture) groups(cgs []*balancer.CellGroup) error {
+	coords := make([]uint64, p.sfc.Dimensions())
+	copy(coords, p.opts.Slice)
+	buf := make([]uint64, len(coords))
+	for x := range p.owner {
+		for y := range p.owner[x] {
+			coords[p.opts.Axes[0]] = p.coord(0, uint64(x))
+			coords[p.opts.Axes[1]] = p.coord(1, uint64(y))
+			//Encode may alter coordinates
+			copy(buf, coords)
+			code, err := p.sfc.Encode(buf)
+			if err != nil {
+				return err
+			}
+			p.owner[x][y] = -1
+			for i := range cgs {
+				if cgs[i].FitsRange(code) {
+					p.owner[x][y] = i
+					break
+				}
+			}
+		}
+	}
+	return nil
+}
+
+//load sums load of cells of the space by drawn cells.
+func (p *picture) load(s *balancer.Space) error {
+	buf := make([]uint64, p.sfc.Dimensions())
+	for _, c := range s.Cells() {
+		coords, err := p.sfc.DecodeWithBuffer(buf, c.ID())
+		if err != nil {
+			return err
+		}
+		x := p.column(0, coords[p.opts.Axes[0]])
+		y := p.column(1, coords[p.opts.Axes[1]])
+		p.heat[x][y] += c.Load()
+	}
+	return nil
+}
+
+//path collects drawn cells visited by the curve in order.
+func (p *picture) path() error {
+	if p.sfc.Length() >= p.opts.MaxPath {
+		return nil
+	}
+	return curve.Walk(p.sfc, curve.NewRange(0, p.sfc.Length()+1), func(code uint64, coords []uint64) bool {
+		pt := [2]uint64{p.column(0, coords[p.opts.Axes[0]]), p.column(1, coords[p.opts.Axes[1]])}
+		if len(p.points) == 0 || p.points[len(p.points)-1] != pt {
+			p.points = append(p.points, pt)
+		}
+		return true
+	})
+}
+
+//write writes SVG document.
+//The vertical axis grows upwards, so the origin of the space is in the bottom left corner.
+func (p *picture) write(w *bufio.Writer, cgs []*balancer.CellGroup) {
+	size := p.opts.CellSize
+	width := float64(p.cols[0]) * size
+	height := float64(p.cols[1]) * size
+	total := height + legendLine*float64(len(cgs)+1)
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" viewBox="0 0 %g %g">`+"\n", width, total, width, total)
+	fmt.Fprintf(w, `<rect width="%g" height="%g" fill="white"/>`+"\n", width, total)
+
+	fmt.Fprintln(w, `<g id="groups">`)
+	for x := range p.owner {
+		for y, i := range p.owner[x] {
+			if i < 0 {
+				continue
+			}
+			fmt.Fprintf(w, `<rect x="%g" y="%g" width="%g" height="%g" fill="%s"/>`+"\n",
+				float64(x)*size, height-float64(y+1)*size, size, size, color(i, len(cgs)))
+		}
+	}
+	fmt.Fprintln(w, `</g>`)
+
+	var max uint64
+	for x := range p.heat {
+		for _, l := range p.heat[x] {
+			if l > max {
+				max = l
+			}
+		}
+	}
+	fmt.Fprintln(w, `<g id="load">`)
+	for x := range p.heat {
+		for y, l := range p.heat[x] {
+			if l == 0 {
+				continue
+			}
+			fmt.Fprintf(w, `<rect x="%g" y="%g" width="%g" height="%g" fill="red" fill-opacity="%.3f"><title>%d</title></rect>`+"\n",
+				float64(x)*size, height-float64(y+1)*size, size, size, 0.1+0.8*float64(l)/float64(max), l)
+		}
+	}
+	fmt.Fprintln(w, `</g>`)
+
+	if len(p.points) > 0 {
+		pts := make([]string, len(p.points))
+		for i, pt := range p.points {
+			pts[i] = fmt.Sprintf("%g,%g", (float64(pt[0])+0.5)*size, height-(float64(pt[1])+0.5)*size)
+		}
+		fmt.Fprintf(w, `<polyline id="curve" points="%s" fill="none" stroke="black" stroke-width="%g"/>`+"\n",
+			strings.Join(pts, " "), math.Max(size/8, 0.5))
+	}
+
+	fmt.Fprintln(w, `<g id="legend" font-family="sans-serif" font-size="12">`)
+	for i, cg := range cgs {
+		y := height + legendLine*float64(i) + 4
+		r := cg.Range()
+		fmt.Fprintf(w, `<rect x="4" y="%g" width="10" height="10" fill="%s"/>`, y, color(i, len(cgs)))
+		fmt.Fprintf(w, `<text x="20" y="%g">`, y+10)
+		xml.EscapeText(w, []byte(fmt.Sprintf("%s [%d, %d) load %d", cg.Node().ID(), r.Min, r.Max, cg.TotalLoad())))
+		fmt.Fprintln(w, `</text>`)
+	}
+	fmt.Fprintln(w, `</g>`)
+	fmt.Fprintln(w, `</svg>`)
+}
+
+//color returns the colour of the cell group, hues of groups are spread evenly.
+func color(i, n int) string {
+	return fmt.Sprintf("hsl(%d, 70%%, 75%%)", i*360/n)
+}
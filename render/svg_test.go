@@ -0,0 +1,270 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	balancer "github.com/struckoff/sfcframework"
+	"github.com/struckoff/sfcframework/curve"
+	"github.com/struckoff/sfcframework/mocks"
+	"github.com/struckoff/sfcframework/node"
+)
+
+func TestSVG(t *testing.T) {
+	type want struct {
+		groups int
+		load   int
+		points int
+		legend []string
+	}
+	tests := []struct {
+		name    string
+		cType   curve.CurveType
+		dims    uint64
+		bits    uint64
+		opts    Options
+		data    [][]uint64
+		want    want
+		wantErr bool
+	}{
+		{
+			name:  "hilbert 2x3",
+			cType: curve.Hilbert,
+			dims:  2,
+			bits:  3,
+			data:  [][]uint64{{0, 0}, {0, 0}, {7, 7}},
+			want: want{
+				//the last cell does not belong to any group
+				groups: 63,
+				load:   2,
+				points: 64,
+				legend: []string{"node-0 [0, 32) load 2", "node-&lt;1&gt; [32, 63) load 1"},
+			},
+		},
+		{
+			name:  "morton 3x2 projection",
+			cType: curve.Morton,
+			dims:  3,
+			bits:  2,
+			opts:  Options{Axes: [2]uint64{2, 0}, Slice: []uint64{0, 3, 0}},
+			data:  [][]uint64{{0, 0, 0}, {0, 3, 0}, {1, 2, 3}},
+			want: want{
+				//cell (3, 3, 3) is the last one
+				groups: 15,
+				load:   2,
+				points: 64,
+			},
+		},
+		{
+			name:  "scaled without path",
+			cType: curve.Hilbert,
+			dims:  2,
+			bits:  10,
+			opts:  Options{Resolution: 16, MaxPath: 1024},
+			data:  [][]uint64{{0, 0}, {1023, 1023}},
+			want: want{
+				groups: 256,
+				load:   2,
+			},
+		},
+		{
+			name:    "equal axes",
+			cType:   curve.Hilbert,
+			dims:    2,
+			bits:    3,
+			opts:    Options{Axes: [2]uint64{1, 1}},
+			wantErr: true,
+		},
+		{
+			name:    "axis out of range",
+			cType:   curve.Hilbert,
+			dims:    2,
+			bits:    3,
+			opts:    Options{Axes: [2]uint64{0, 2}},
+			wantErr: true,
+		},
+		{
+			name:    "short slice",
+			cType:   curve.Hilbert,
+			dims:    3,
+			bits:    3,
+			opts:    Options{Slice: []uint64{0}},
+			wantErr: true,
+		},
+		{
+			name:    "one dimension",
+			cType:   curve.Morton,
+			dims:    1,
+			bits:    3,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSpace(t, tt.cType, tt.dims, tt.bits, []string{"node-0", "node-<1>"})
+			for i, coords := range tt.data {
+				code, err := s.SFC().Encode(coords)
+				if err != nil {
+					t.Fatal(err)
+				}
+				d := &mocks.DataItem{}
+				d.On("ID").Return(fmt.Sprintf("di-%d", i))
+				d.On("Size").Return(uint64(1))
+				if err := s.AddData(code, d); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var buf bytes.Buffer
+			err := SVG(&buf, s, tt.opts)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Contains(t, buf.String(), "node-&lt;1&gt;")
+			got := parse(t, &buf)
+			assert.Equal(t, tt.want.groups, got.groups)
+			assert.Equal(t, tt.want.load, got.load)
+			assert.Equal(t, tt.want.points, got.points)
+			if tt.want.legend != nil {
+				assert.Equal(t, tt.want.legend, got.legend)
+			}
+		})
+	}
+}
+
+//Cell groups of the slice are found by coordinates of the slice for every drawn cell,
+//even if the curve alters coordinates on encoding.
+func TestSVG_SliceGroups(t *testing.T) {
+	ids := make([]string, 16)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("node-%d", i)
+	}
+	s := newSpace(t, curve.Hilbert, 4, 2, ids)
+	cgs := s.CellGroups()
+	opts := Options{Slice: []uint64{0, 0, 2, 3}, CellSize: 10}
+	var buf bytes.Buffer
+	if err := SVG(&buf, s, opts); !assert.NoError(t, err) {
+		return
+	}
+	got := parse(t, &buf)
+	groups := map[int]bool{}
+	for x := uint64(0); x < 4; x++ {
+		for y := uint64(0); y < 4; y++ {
+			code, err := s.SFC().Encode([]uint64{x, y, 2, 3})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := ""
+			for i, cg := range cgs {
+				if cg.FitsRange(code) {
+					want = color(i, len(cgs))
+					groups[i] = true
+				}
+			}
+			assert.Equal(t, want, got.fills[fmt.Sprintf("%d,%d", x*10, 30-y*10)], "cell (%v, %v)", x, y)
+		}
+	}
+	//the slice crosses several cell groups, so colours are distinguishable
+	assert.True(t, len(groups) > 1, "groups %v", groups)
+}
+
+func newSpace(t *testing.T, cType curve.CurveType, dims, bits uint64, ids []string) *balancer.Space {
+	sfc, err := curve.NewCurve(cType, dims, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes := make([]node.Node, len(ids))
+	for i, id := range ids {
+		n := &mocks.Node{}
+		n.On("ID").Return(id)
+		nodes[i] = n
+	}
+	s, err := balancer.NewSpace(sfc, nil, nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+type parsed struct {
+	groups int
+	load   int
+	points int
+	legend []string
+	fills  map[string]string //"x,y" of the drawn cell -> fill of the cell group
+}
+
+//parse checks the document is well-formed and counts its elements.
+func parse(t *testing.T, r io.Reader) (res parsed) {
+	dec := xml.NewDecoder(r)
+	var stack []string
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return res
+		}
+		if !assert.NoError(t, err) {
+			return res
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			group := ""
+			if len(stack) > 0 {
+				group = stack[len(stack)-1]
+			}
+			id := ""
+			var x, y, fill string
+			for _, a := range el.Attr {
+				switch a.Name.Local {
+				case "x":
+					x = a.Value
+				case "y":
+					y = a.Value
+				case "fill":
+					fill = a.Value
+				}
+				if a.Name.Local == "id" {
+					id = a.Value
+				}
+				if el.Name.Local == "polyline" && a.Name.Local == "points" {
+					res.points = len(strings.Fields(a.Value))
+				}
+			}
+			if el.Name.Local == "rect" && group == "groups" {
+				res.groups++
+				if res.fills == nil {
+					res.fills = map[string]string{}
+				}
+				res.fills[x+","+y] = fill
+			}
+			if el.Name.Local == "rect" && group == "load" {
+				res.load++
+			}
+			if id == "" {
+				id = group
+			}
+			stack = append(stack, id)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 1 && stack[len(stack)-2] == "legend" {
+				res.legend = append(res.legend, xmlEscape(string(el)))
+			}
+		}
+	}
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
@@ -132,6 +132,13 @@ func (s *Space) Len() int {
 	return len(s.cgs)
 }
 
+//SFC returns space-filling curve which space use.
+func (s *Space) SFC() curve.Curve {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sfc
+}
+
 //Capacity returns maximum number of cell which could be located in space
 func (s *Space) Capacity() uint64 {
 	s.mu.Lock()
@@ -210,6 +217,16 @@ func (s *Space) AddData(cID uint64, d DataItem) error {
 	return s.addData(cID, d)
 }
 
+//AddDataBatch locates data items and adds them to the space at once.
+//Coordinates of all items are encoded by a single batch call of the space-filling curve.
+//The batch is atomic: if any item could not be transformed, encoded or bound to a cell group, the space is not changed.
+//It returns IDs of cells where items were added, in the same order as items.
+func (s *Space) AddDataBatch(ds []DataItem) ([]uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addDataBatch(ds)
+}
+
 //RemoveData removes data item from the space.
 func (s *Space) RemoveData(d DataItem) error {
 	s.mu.Lock()
@@ -256,6 +273,59 @@ func (s *Space) addData(cID uint64, d DataItem) error {
 	return nil
 }
 
+func (s *Space) addDataBatch(ds []DataItem) ([]uint64, error) {
+	if len(s.cgs) == 0 {
+		return nil, errors.New("no nodes in the cluster")
+	}
+	if s.tf == nil {
+		return nil, errors.New("transform function is not set")
+	}
+	coords := make([][]uint64, len(ds))
+	for i, d := range ds {
+		c, err := s.tf(d.Values(), s.sfc)
+		if err != nil {
+			return nil, err
+		}
+		coords[i] = c
+	}
+	cIDs := make([]uint64, len(ds))
+	if err := curve.EncodeBatch(s.sfc, coords, cIDs); err != nil {
+		return nil, errors.Wrap(err, "item encoding error")
+	}
+
+	//cells are resolved before the space is changed, so the batch is added entirely or not at all:
+	//missing cells are created only when every item is bound to a cell group
+	groups := make(map[uint64]*CellGroup)
+	for i, d := range ds {
+		if c, ok := s.cells[cIDs[i]]; ok {
+			ncID, ok := c.Relocated(d.ID())
+			if !ok {
+				continue
+			}
+			cIDs[i] = ncID
+			if _, ok := s.cells[ncID]; ok {
+				continue
+			}
+		}
+		if _, ok := groups[cIDs[i]]; ok {
+			continue
+		}
+		cg, ok := s.findCellGroup(cIDs[i])
+		if !ok {
+			return nil, errors.Errorf("unable to bind cell to cell group (cID=%v)", cIDs[i])
+		}
+		groups[cIDs[i]] = cg
+	}
+	for cID, cg := range groups {
+		s.cells[cID] = NewCell(cID, cg)
+	}
+	for i, d := range ds {
+		s.cells[cIDs[i]].AddLoad(d.Size())
+		s.load += d.Size()
+	}
+	return cIDs, nil
+}
+
 //getCell returns cell from space by ID,
 //it creates a new one if cell by given ID not exists.
 func (s *Space) getCell(cID uint64) (*cell, error) {
@@ -361,6 +431,67 @@ func (s *Space) Nodes() []node.Node {
 	return res
 }
 
+//WalkCellGroup calls fn for each code of the range of the cell group in order with coordinates of the cell.
+//Coordinates are stored in the buffer which is reused between calls.
+//The space is not locked during the walk, so fn may call methods of the space.
+func (s *Space) WalkCellGroup(cg *CellGroup, fn curve.WalkFunc) error {
+	s.mu.Lock()
+	sfc := s.sfc
+	s.mu.Unlock()
+	return curve.Walk(sfc, cg.Range(), fn)
+}
+
+//CellGroupBox returns the minimum bounding box of cells of the cell group,
+//so the spatial extent of the node could be published to query planners.
+func (s *Space) CellGroupBox(cg *CellGroup) (curve.Box, error) {
+	s.mu.Lock()
+	sfc := s.sfc
+	s.mu.Unlock()
+	return curve.RangeBox(sfc, cg.Range())
+}
+
+//CellGroupRegion returns disjoint boxes which cover exactly cells of the cell group.
+func (s *Space) CellGroupRegion(cg *CellGroup) ([]curve.Box, error) {
+	s.mu.Lock()
+	sfc := s.sfc
+	s.mu.Unlock()
+	return curve.RangeRegion(sfc, cg.Range())
+}
+
+//BoxCellGroups returns cell groups which ranges contain at least one cell of the box, in the order of the space.
+//Box is given by the minimum and maximum(inclusive) coordinates in each dimension.
+func (s *Space) BoxCellGroups(min, max []uint64) ([]*CellGroup, error) {
+	s.mu.Lock()
+	sfc := s.sfc
+	cgs := make([]*CellGroup, len(s.cgs))
+	copy(cgs, s.cgs)
+	s.mu.Unlock()
+
+	rs, err := curve.BoxRanges(sfc, min, max, 0)
+	if err != nil {
+		return nil, err
+	}
+	var res []*CellGroup
+	for _, cg := range cgs {
+		if intersects(cg.Range(), rs, sfc.Length()) {
+			res = append(res, cg)
+		}
+	}
+	return res, nil
+}
+
+//intersects checks if the range, which may wrap, intersects any of ranges.
+func intersects(r Range, rs []Range, length uint64) bool {
+	for _, piece := range r.Split(length) {
+		for _, o := range rs {
+			if _, ok := piece.Intersect(o); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 //FillCellGroup - populate cell group by cells from space
 //considering group range.
 func (s *Space) FillCellGroup(cg *CellGroup) {
@@ -33,11 +33,11 @@ func Test_splitCells(t *testing.T) {
 				l: 5,
 			},
 			want: []Range{
-				{0, 1, 1},
-				{1, 2, 1},
-				{2, 3, 1},
-				{3, 4, 1},
-				{4, 5, 1},
+				{Min: 0, Max: 1, Len: 1},
+				{Min: 1, Max: 2, Len: 1},
+				{Min: 2, Max: 3, Len: 1},
+				{Min: 3, Max: 4, Len: 1},
+				{Min: 4, Max: 5, Len: 1},
 			},
 			wantErr: false,
 		},
@@ -57,9 +57,9 @@ func Test_splitCells(t *testing.T) {
 				l: 20,
 			},
 			want: []Range{
-				{0, 7, 7},
-				{7, 14, 7},
-				{14, 20, 6},
+				{Min: 0, Max: 7, Len: 7},
+				{Min: 7, Max: 14, Len: 7},
+				{Min: 14, Max: 20, Len: 6},
 			},
 			wantErr: false,
 		},
@@ -70,11 +70,11 @@ func Test_splitCells(t *testing.T) {
 				l: 256,
 			},
 			want: []Range{
-				{0, 52, 52},
-				{52, 103, 51},
-				{103, 154, 51},
-				{154, 205, 51},
-				{205, 256, 51},
+				{Min: 0, Max: 52, Len: 52},
+				{Min: 52, Max: 103, Len: 51},
+				{Min: 103, Max: 154, Len: 51},
+				{Min: 154, Max: 205, Len: 51},
+				{Min: 205, Max: 256, Len: 51},
 			},
 			wantErr: false,
 		},
@@ -566,6 +566,107 @@ func TestSpace_AddData(t *testing.T) {
 	assert.Equal(t, 111, int(s.load))
 }
 
+func TestSpace_AddDataBatch(t *testing.T) {
+	tf := func(values []interface{}, sfc curve.Curve) ([]uint64, error) {
+		res := make([]uint64, len(values))
+		for i := range values {
+			res[i] = values[i].(uint64)
+		}
+		return res, nil
+	}
+	sfc, err := curve.NewCurve(curve.Hilbert, 2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := &mocks.Node{}
+	n.On("ID").Return("test-node")
+	cg := NewCellGroup(n)
+	assert.NoError(t, cg.SetRange(0, math.MaxUint64))
+
+	ds := make([]DataItem, 3)
+	for i := range ds {
+		d := &mocks.DataItem{}
+		d.On("ID").Return(fmt.Sprintf("test-di-%d", i))
+		d.On("Size").Return(uint64(10 * (i + 1)))
+		d.On("Values").Return([]interface{}{uint64(i), uint64(i + 1)})
+		ds[i] = d
+	}
+	want := make([]uint64, len(ds))
+	for i := range want {
+		want[i], err = sfc.Encode([]uint64{uint64(i), uint64(i + 1)})
+		assert.NoError(t, err)
+	}
+
+	cells := map[uint64]*cell{
+		want[2]: {
+			id:   want[2],
+			load: uint64ptr(0),
+			cg:   cg,
+			off:  map[string]uint64{"test-di-2": 200},
+		},
+	}
+	s := &Space{
+		sfc:   sfc,
+		cgs:   []*CellGroup{cg},
+		cells: cells,
+		tf:    tf,
+	}
+
+	got, err := s.AddDataBatch(ds)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{want[0], want[1], 200}, got)
+	assert.Equal(t, uint64(60), s.load)
+	assert.Equal(t, uint64(10), s.cells[want[0]].Load())
+	assert.Equal(t, uint64(20), s.cells[want[1]].Load())
+	assert.Equal(t, uint64(30), s.cells[200].Load())
+}
+
+func TestSpace_AddDataBatch_Errors(t *testing.T) {
+	tf := func(values []interface{}, sfc curve.Curve) ([]uint64, error) {
+		return []uint64{values[0].(uint64), 0}, nil
+	}
+	sfc, err := curve.NewCurve(curve.Morton, 2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &mocks.DataItem{}
+	d.On("ID").Return("test-di")
+	d.On("Size").Return(uint64(1))
+	d.On("Values").Return([]interface{}{uint64(1)})
+	bad := &mocks.DataItem{}
+	bad.On("ID").Return("test-bad")
+	bad.On("Size").Return(uint64(1))
+	bad.On("Values").Return([]interface{}{uint64(16)})
+	n := &mocks.Node{}
+	n.On("ID").Return("test-node")
+	cg := NewCellGroup(n)
+	assert.NoError(t, cg.SetRange(0, 2))
+
+	s := &Space{sfc: sfc, cells: map[uint64]*cell{}, tf: tf}
+	_, err = s.AddDataBatch([]DataItem{d})
+	assert.Error(t, err, "no nodes")
+
+	s = &Space{sfc: sfc, cgs: []*CellGroup{cg}, cells: map[uint64]*cell{}}
+	_, err = s.AddDataBatch([]DataItem{d})
+	assert.Error(t, err, "no transform function")
+
+	s = &Space{sfc: sfc, cgs: []*CellGroup{cg}, cells: map[uint64]*cell{}, tf: tf}
+	_, err = s.AddDataBatch([]DataItem{d, bad})
+	assert.Error(t, err, "encoding error")
+	assert.Equal(t, uint64(0), s.load)
+
+	far := &mocks.DataItem{}
+	far.On("ID").Return("test-far")
+	far.On("Size").Return(uint64(1))
+	far.On("Values").Return([]interface{}{uint64(15)})
+	_, err = s.AddDataBatch([]DataItem{d, far})
+	assert.Error(t, err, "cell out of groups")
+	assert.Equal(t, uint64(0), s.load)
+	//cells of items before the failed one are not created
+	assert.Empty(t, s.cells)
+	assert.Empty(t, cg.Cells())
+}
+
 func TestSpace_LocateData(t *testing.T) {
 	tf := func(values []interface{}, sfc curve.Curve) ([]uint64, error) {
 		res := make([]uint64, len(values))
@@ -1106,6 +1207,169 @@ func TestSpace_FillCellGroup(t *testing.T) {
 	}
 }
 
+func TestSpace_WalkCellGroup(t *testing.T) {
+	sfc, err := curve.NewCurve(curve.Hilbert, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := &mocks.Node{}
+	n.On("ID").Return("test-node")
+	cg := NewCellGroup(n)
+	assert.NoError(t, cg.SetRange(10, 20))
+	s := &Space{sfc: sfc, cgs: []*CellGroup{cg}, cells: map[uint64]*cell{}}
+
+	var codes []uint64
+	err = s.WalkCellGroup(cg, func(code uint64, coords []uint64) bool {
+		want, err := sfc.Decode(code)
+		assert.NoError(t, err)
+		assert.Equal(t, want, coords)
+		assert.True(t, cg.FitsRange(code))
+		codes = append(codes, code)
+		return true
+	})
+	assert.NoError(t, err)
+	assert.Len(t, codes, 10)
+	assert.Equal(t, uint64(10), codes[0])
+}
+
+func TestSpace_CellGroupBox(t *testing.T) {
+	sfc, err := curve.NewCurve(curve.Hilbert, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := &mocks.Node{}
+	n.On("ID").Return("test-node")
+	cg := NewCellGroup(n)
+	assert.NoError(t, cg.SetRange(16, 48))
+	s := &Space{sfc: sfc, cgs: []*CellGroup{cg}, cells: map[uint64]*cell{}}
+
+	region, err := s.CellGroupRegion(cg)
+	assert.NoError(t, err)
+	assert.Len(t, region, 2)
+	box, err := s.CellGroupBox(cg)
+	assert.NoError(t, err)
+	assert.Len(t, box.Min, 2)
+	for code := uint64(0); code <= sfc.Length(); code++ {
+		coords, err := sfc.Decode(code)
+		assert.NoError(t, err)
+		inside := 0
+		for _, b := range region {
+			if coords[0] >= b.Min[0] && coords[0] <= b.Max[0] && coords[1] >= b.Min[1] && coords[1] <= b.Max[1] {
+				inside++
+			}
+		}
+		if cg.FitsRange(code) {
+			assert.Equal(t, 1, inside, "code %v", code)
+			assert.True(t, coords[0] >= box.Min[0] && coords[0] <= box.Max[0] && coords[1] >= box.Min[1] && coords[1] <= box.Max[1])
+		} else {
+			assert.Equal(t, 0, inside, "code %v", code)
+		}
+	}
+
+	assert.NoError(t, cg.SetRange(64, 64))
+	_, err = s.CellGroupBox(cg)
+	assert.Error(t, err)
+}
+
+func TestSpace_CellGroupBox_Cube(t *testing.T) {
+	sfc, err := curve.NewCurve(curve.Cube, 3, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	span := uint64(1) << 32
+	cgs := make([]*CellGroup, 2)
+	for i := range cgs {
+		n := &mocks.Node{}
+		n.On("ID").Return(fmt.Sprintf("node-%d", i))
+		cgs[i] = NewCellGroup(n)
+	}
+	assert.NoError(t, cgs[0].SetRange(0, span+span/2))
+	assert.NoError(t, cgs[1].SetRange(span+span/2, sfc.Length()+1))
+	s := &Space{sfc: sfc, cgs: cgs, cells: map[uint64]*cell{}}
+
+	box, err := s.CellGroupBox(cgs[0])
+	assert.NoError(t, err)
+	assert.Equal(t, curve.Box{Min: []uint64{0, 0, 0}, Max: []uint64{1, 1<<16 - 1, 1<<16 - 1}}, box)
+	region, err := s.CellGroupRegion(cgs[1])
+	assert.NoError(t, err)
+	assert.True(t, len(region) > 4)
+
+	got, err := s.BoxCellGroups([]uint64{2, 0, 0}, []uint64{5, 1<<16 - 1, 1<<16 - 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []*CellGroup{cgs[1]}, got)
+	got, err = s.BoxCellGroups([]uint64{0, 100, 100}, []uint64{3, 40000, 40000})
+	assert.NoError(t, err)
+	assert.Equal(t, cgs, got)
+}
+
+func TestSpace_BoxCellGroups(t *testing.T) {
+	sfc, err := curve.NewCurve(curve.Hilbert, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cgs := make([]*CellGroup, 4)
+	for i := range cgs {
+		n := &mocks.Node{}
+		n.On("ID").Return(fmt.Sprintf("node-%d", i))
+		cgs[i] = NewCellGroup(n)
+	}
+	assert.NoError(t, cgs[0].SetRingRange(56, 8, sfc.Length()))
+	assert.NoError(t, cgs[1].SetRange(8, 24))
+	assert.NoError(t, cgs[2].SetRange(24, 40))
+	assert.NoError(t, cgs[3].SetRange(40, 56))
+	s := &Space{sfc: sfc, cgs: cgs, cells: map[uint64]*cell{}}
+
+	type args struct {
+		min []uint64
+		max []uint64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []*CellGroup
+		wantErr bool
+	}{
+		{"origin", args{min: []uint64{0, 0}, max: []uint64{0, 0}}, []*CellGroup{cgs[0]}, false},
+		{"whole", args{min: []uint64{0, 0}, max: []uint64{7, 7}}, cgs, false},
+		{"center", args{min: []uint64{3, 3}, max: []uint64{4, 4}}, nil, false},
+		{"row", args{min: []uint64{0, 5}, max: []uint64{7, 5}}, nil, false},
+		{"column", args{min: []uint64{6, 0}, max: []uint64{6, 7}}, nil, false},
+		{"inverted", args{min: []uint64{4, 4}, max: []uint64{3, 3}}, nil, true},
+		{"out of range", args{min: []uint64{0, 0}, max: []uint64{8, 0}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.want
+			if want == nil && !tt.wantErr {
+				//groups of cells found by encoding each cell of the box
+				found := map[*CellGroup]bool{}
+				for x := tt.args.min[0]; x <= tt.args.max[0]; x++ {
+					for y := tt.args.min[1]; y <= tt.args.max[1]; y++ {
+						code, err := sfc.Encode([]uint64{x, y})
+						assert.NoError(t, err)
+						cg, ok := s.findCellGroup(code)
+						assert.True(t, ok)
+						found[cg] = true
+					}
+				}
+				for _, cg := range cgs {
+					if found[cg] {
+						want = append(want, cg)
+					}
+				}
+			}
+
+			got, err := s.BoxCellGroups(tt.args.min, tt.args.max)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
 func TestSpace_TotalPower(t *testing.T) {
 	type fields struct {
 		powers []float64
@@ -1144,6 +1408,12 @@ func TestSpace_TotalPower(t *testing.T) {
 	}
 }
 
+func TestSpace_SFC(t *testing.T) {
+	sfc := &mocks.Curve{}
+	s := &Space{sfc: sfc}
+	assert.Equal(t, sfc, s.SFC())
+}
+
 func TestSpace_Capacity(t *testing.T) {
 	type fields struct {
 		length uint64
@@ -0,0 +1,30 @@
+package transform
+
+import (
+	"errors"
+
+	"github.com/struckoff/sfcframework/curve"
+	"github.com/struckoff/sfcframework/curve/cube"
+)
+
+//CubeTransform is used to transform geo coordinates to fit the cube-face curve(curve.Cube).
+//It requires two float64 values(latitude in range [-90, 90], longitude in range [-180, 180]).
+//Unlike SpaceTransform, cells of the curve cover roughly equal area of the sphere.
+func CubeTransform(values []interface{}, sfc curve.Curve) ([]uint64, error) {
+	c, ok := sfc.(*cube.Curve)
+	if !ok {
+		return nil, errors.New("curve must be the cube-face curve")
+	}
+	if len(values) != 2 {
+		return nil, errors.New("number of values must be 2")
+	}
+	lat, ok := values[0].(float64)
+	if !ok {
+		return nil, errors.New("first value must be float64 latitude")
+	}
+	lon, ok := values[1].(float64)
+	if !ok {
+		return nil, errors.New("second value must be float64 longitude")
+	}
+	return c.Coords(lat, lon)
+}
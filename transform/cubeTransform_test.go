@@ -0,0 +1,145 @@
+package transform
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/struckoff/sfcframework/curve"
+)
+
+func TestCubeTransform(t *testing.T) {
+	type args struct {
+		values []interface{}
+		cType  curve.CurveType
+		dims   uint64
+		bits   uint64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []uint64
+		wantErr bool
+	}{
+		{
+			name: "equator, prime meridian",
+			args: args{
+				values: []interface{}{0.0, 0.0},
+				cType:  curve.Cube,
+				dims:   3,
+				bits:   4,
+			},
+			want: []uint64{0, 8, 8},
+		},
+		{
+			name: "north pole",
+			args: args{
+				values: []interface{}{90.0, 0.0},
+				cType:  curve.Cube,
+				dims:   3,
+				bits:   4,
+			},
+			want: []uint64{2, 8, 8},
+		},
+		{
+			name: "south pole",
+			args: args{
+				values: []interface{}{-90.0, 0.0},
+				cType:  curve.Cube,
+				dims:   3,
+				bits:   4,
+			},
+			want: []uint64{5, 8, 8},
+		},
+		{
+			name: "not cube curve",
+			args: args{
+				values: []interface{}{0.0, 0.0},
+				cType:  curve.Hilbert,
+				dims:   2,
+				bits:   4,
+			},
+			wantErr: true,
+		},
+		{
+			name: "one value",
+			args: args{
+				values: []interface{}{0.0},
+				cType:  curve.Cube,
+				dims:   3,
+				bits:   4,
+			},
+			wantErr: true,
+		},
+		{
+			name: "latitude is not float64",
+			args: args{
+				values: []interface{}{"0", 0.0},
+				cType:  curve.Cube,
+				dims:   3,
+				bits:   4,
+			},
+			wantErr: true,
+		},
+		{
+			name: "longitude is not float64",
+			args: args{
+				values: []interface{}{0.0, 0},
+				cType:  curve.Cube,
+				dims:   3,
+				bits:   4,
+			},
+			wantErr: true,
+		},
+		{
+			name: "latitude out of range",
+			args: args{
+				values: []interface{}{200.0, 0.0},
+				cType:  curve.Cube,
+				dims:   3,
+				bits:   4,
+			},
+			wantErr: true,
+		},
+		{
+			name: "nan latitude",
+			args: args{
+				values: []interface{}{math.NaN(), 0.0},
+				cType:  curve.Cube,
+				dims:   3,
+				bits:   4,
+			},
+			wantErr: true,
+		},
+		{
+			name: "infinite longitude",
+			args: args{
+				values: []interface{}{0.0, math.Inf(1)},
+				cType:  curve.Cube,
+				dims:   3,
+				bits:   4,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sfc, err := curve.NewCurve(tt.args.cType, tt.args.dims, tt.args.bits)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			got, err := CubeTransform(tt.args.values, sfc)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CubeTransform() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+			if err == nil {
+				_, err = sfc.Encode(got)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package transform
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/struckoff/sfcframework/curve"
+)
+
+//FloatTransform returns a transform function for float64 values with given bounds of each dimension.
+//Values are scaled to cells of the curve of the space by curve.FloatCurve with given bounds,
+//the float curve is built once and reused while the curve of the space is the same.
+func FloatTransform(bounds []curve.FloatBounds) func(values []interface{}, sfc curve.Curve) ([]uint64, error) {
+	fcs := &floatCurves{bounds: append([]curve.FloatBounds{}, bounds...)}
+	return func(values []interface{}, sfc curve.Curve) ([]uint64, error) {
+		fc, err := fcs.get(sfc)
+		if err != nil {
+			return nil, err
+		}
+		fs := make([]float64, len(values))
+		for i, v := range values {
+			f, ok := v.(float64)
+			if !ok {
+				return nil, fmt.Errorf("value %v must be float64", i)
+			}
+			fs[i] = f
+		}
+		return fc.Coords(fs)
+	}
+}
+
+//floatCurves - the float curve with given bounds of the last used curve.
+type floatCurves struct {
+	mu     sync.Mutex
+	bounds []curve.FloatBounds
+	last   *curve.FloatCurve
+}
+
+//get returns the float curve with given bounds around the curve, float curves are unwrapped.
+func (fcs *floatCurves) get(sfc curve.Curve) (*curve.FloatCurve, error) {
+	if fc, ok := sfc.(*curve.FloatCurve); ok {
+		sfc = fc.Curve
+	}
+	fcs.mu.Lock()
+	defer fcs.mu.Unlock()
+	//curves of uncomparable types could not be told apart, so they are wrapped every time
+	if fcs.last != nil && reflect.TypeOf(sfc).Comparable() && fcs.last.Curve == sfc {
+		return fcs.last, nil
+	}
+	fc, err := curve.NewFloatCurve(sfc, fcs.bounds)
+	if err != nil {
+		return nil, err
+	}
+	fcs.last = fc
+	return fc, nil
+}
@@ -0,0 +1,83 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/struckoff/sfcframework/curve"
+)
+
+func TestFloatTransform(t *testing.T) {
+	sfc, err := curve.NewCurve(curve.Hilbert, 2, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fc, err := curve.NewFloatCurve(sfc, []curve.FloatBounds{{Min: -1, Max: 1}, {Min: -1, Max: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tf := FloatTransform([]curve.FloatBounds{{Min: 0, Max: 1}, {Min: 0, Max: 100}})
+	tests := []struct {
+		name    string
+		values  []interface{}
+		sfc     curve.Curve
+		want    []uint64
+		wantErr bool
+	}{
+		{"minimum", []interface{}{0.0, 0.0}, sfc, []uint64{0, 0}, false},
+		{"maximum", []interface{}{1.0, 100.0}, sfc, []uint64{255, 255}, false},
+		{"inside", []interface{}{0.5, 25.0}, sfc, []uint64{128, 64}, false},
+		{"float curve", []interface{}{0.5, 25.0}, fc, []uint64{128, 64}, false},
+		{"out of bounds", []interface{}{0.5, 101.0}, sfc, nil, true},
+		{"not float64", []interface{}{0.5, 25}, sfc, nil, true},
+		{"not enough values", []interface{}{0.5}, sfc, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tf(tt.values, tt.sfc)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err = FloatTransform(nil)([]interface{}{0.5, 0.5}, sfc)
+	assert.Error(t, err)
+}
+
+//uncomparableCurve could not be compared with ==.
+type uncomparableCurve struct {
+	curve.Curve
+	_ []int
+}
+
+func Test_floatCurves(t *testing.T) {
+	a := mustCurve(t, curve.Hilbert, 2, 8)
+	b := mustCurve(t, curve.Morton, 2, 8)
+	fcs := &floatCurves{bounds: []curve.FloatBounds{{Min: 0, Max: 1}, {Min: 0, Max: 1}}}
+	first, err := fcs.get(a)
+	assert.NoError(t, err)
+	got, err := fcs.get(a)
+	assert.NoError(t, err)
+	assert.True(t, first == got, "float curve should be reused for the same curve")
+	got, err = fcs.get(first)
+	assert.NoError(t, err)
+	assert.True(t, first == got, "float curve should be unwrapped")
+
+	got, err = fcs.get(b)
+	assert.NoError(t, err)
+	assert.Equal(t, b, got.Curve)
+	got, err = fcs.get(uncomparableCurve{Curve: a})
+	assert.NoError(t, err)
+	assert.Equal(t, uncomparableCurve{Curve: a}, got.Curve)
+	got, err = fcs.get(uncomparableCurve{Curve: a})
+	assert.NoError(t, err)
+	assert.Equal(t, uncomparableCurve{Curve: a}, got.Curve)
+
+	_, err = fcs.get(mustCurve(t, curve.Hilbert, 3, 8))
+	assert.Error(t, err)
+}
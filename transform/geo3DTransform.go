@@ -0,0 +1,144 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/struckoff/sfcframework/curve"
+)
+
+//WGS84 ellipsoid.
+const (
+	wgs84A  = 6378137.0             //semi-major axis in metres
+	wgs84F  = 1 / 298.257223563     //flattening
+	wgs84E2 = wgs84F * (2 - wgs84F) //square of the first eccentricity
+	wgs84B  = wgs84A * (1 - wgs84F) //semi-minor axis in metres
+)
+
+//GeoFrame - the frame where geodetic coordinates are placed onto dimensions of 3D curve.
+type GeoFrame int
+
+const (
+	GeodeticFrame GeoFrame = iota //latitude(degrees), longitude(degrees), altitude(metres)
+	ECEFFrame                     //Earth-centred Earth-fixed x, y, z(metres)
+)
+
+//Geo3DTransform is used to transform geodetic coordinates with altitude to fit 3D curve.
+//It requires three float64 values(latitude, longitude, altitude in metres above WGS84 ellipsoid).
+//
+//GeodeticFrame divides latitude, longitude and altitude bounds into cells independently,
+//ECEFFrame converts coordinates into Earth-centred Earth-fixed cartesian coordinates,
+//so cells are cubes of equal size and points near the poles or the antimeridian are close on the curve.
+//
+//Resolution(width of the cell along each axis) with altitude bounds [-1000, 10000] and bits per dimension:
+//  bits | ECEF x, y, z | latitude              | longitude             | altitude
+//  10   | 12.5 km      | 0.176°(19.6 km)       | 0.352°(39.1 km)       | 10.7 m
+//  16   | 195 m        | 0.00275°(306 m)       | 0.00549°(611 m)       | 0.168 m
+//  21   | 6.09 m       | 0.0000858°(9.55 m)    | 0.000172°(19.1 m)     | 0.0052 m
+//Distances of latitude and longitude are given at the equator.
+type Geo3DTransform struct {
+	frame    GeoFrame
+	altitude curve.FloatBounds
+	bounds   []curve.FloatBounds
+}
+
+//NewGeo3DTransform creates a transform with the frame and bounds of altitude in metres.
+func NewGeo3DTransform(frame GeoFrame, altitude curve.FloatBounds) (*Geo3DTransform, error) {
+	if math.IsNaN(altitude.Min) || math.IsNaN(altitude.Max) || math.IsInf(altitude.Max-altitude.Min, 0) {
+		return nil, errors.New("altitude bounds must be finite")
+	}
+	if altitude.Min >= altitude.Max {
+		return nil, fmt.Errorf("minimum altitude == %v must be less than maximum altitude == %v", altitude.Min, altitude.Max)
+	}
+	if altitude.Min <= -wgs84B {
+		return nil, fmt.Errorf("minimum altitude == %v must be above the centre of the Earth", altitude.Min)
+	}
+	g := &Geo3DTransform{frame: frame, altitude: altitude}
+	switch frame {
+	case GeodeticFrame:
+		g.bounds = []curve.FloatBounds{{Min: -90, Max: 90}, {Min: -180, Max: 180}, altitude}
+	case ECEFFrame:
+		//the farthest point from the centre is on the equator at the maximum altitude
+		r := wgs84A + math.Max(altitude.Max, 0)
+		g.bounds = []curve.FloatBounds{{Min: -r, Max: r}, {Min: -r, Max: r}, {Min: -r, Max: r}}
+	default:
+		return nil, fmt.Errorf("unknown frame %v", frame)
+	}
+	return g, nil
+}
+
+//Bounds returns bounds of each dimension of the curve in units of the frame.
+func (g *Geo3DTransform) Bounds() []curve.FloatBounds {
+	return append([]curve.FloatBounds{}, g.bounds...)
+}
+
+//Resolution returns the width of the cell of the curve along each dimension in units of the frame:
+//degrees for latitude and longitude, metres for altitude and ECEF axes.
+func (g *Geo3DTransform) Resolution(sfc curve.Curve) ([]float64, error) {
+	fc, err := g.floatCurve(sfc)
+	if err != nil {
+		return nil, err
+	}
+	return fc.Resolution(), nil
+}
+
+//Transform is the transform function of the balancer.
+func (g *Geo3DTransform) Transform(values []interface{}, sfc curve.Curve) ([]uint64, error) {
+	if len(values) != 3 {
+		return nil, errors.New("number of values must be 3")
+	}
+	lat, ok := values[0].(float64)
+	if !ok {
+		return nil, errors.New("first value must be float64 latitude")
+	}
+	lon, ok := values[1].(float64)
+	if !ok {
+		return nil, errors.New("second value must be float64 longitude")
+	}
+	alt, ok := values[2].(float64)
+	if !ok {
+		return nil, errors.New("third value must be float64 altitude")
+	}
+	if !(lat >= -90 && lat <= 90) {
+		return nil, fmt.Errorf("latitude == %v must be in range [-90, 90]", lat)
+	}
+	if !(lon >= -180 && lon <= 180) {
+		return nil, fmt.Errorf("longitude == %v must be in range [-180, 180]", lon)
+	}
+	if !(alt >= g.altitude.Min && alt <= g.altitude.Max) {
+		return nil, fmt.Errorf("altitude == %v must be in range [%v, %v]", alt, g.altitude.Min, g.altitude.Max)
+	}
+	fc, err := g.floatCurve(sfc)
+	if err != nil {
+		return nil, err
+	}
+	if g.frame == ECEFFrame {
+		x, y, z := GeodeticToECEF(lat, lon, alt)
+		return fc.Coords([]float64{x, y, z})
+	}
+	return fc.Coords([]float64{lat, lon, alt})
+}
+
+func (g *Geo3DTransform) floatCurve(sfc curve.Curve) (*curve.FloatCurve, error) {
+	if sfc.Dimensions() != 3 {
+		return nil, errors.New("number of dimensions must be 3")
+	}
+	if fc, ok := sfc.(*curve.FloatCurve); ok {
+		sfc = fc.Curve
+	}
+	return curve.NewFloatCurve(sfc, g.bounds)
+}
+
+//GeodeticToECEF converts latitude, longitude(degrees) and altitude above WGS84 ellipsoid(metres)
+//into Earth-centred Earth-fixed coordinates(metres).
+func GeodeticToECEF(lat, lon, alt float64) (x, y, z float64) {
+	sinLat, cosLat := math.Sincos(lat * math.Pi / 180)
+	sinLon, cosLon := math.Sincos(lon * math.Pi / 180)
+	//radius of curvature in the prime vertical
+	n := wgs84A / math.Sqrt(1-wgs84E2*sinLat*sinLat)
+	x = (n + alt) * cosLat * cosLon
+	y = (n + alt) * cosLat * sinLon
+	z = (n*(1-wgs84E2) + alt) * sinLat
+	return x, y, z
+}
@@ -0,0 +1,181 @@
+package transform
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/struckoff/sfcframework/curve"
+)
+
+func mustGeo3DTransform(t *testing.T, frame GeoFrame) *Geo3DTransform {
+	g, err := NewGeo3DTransform(frame, curve.FloatBounds{Min: -1000, Max: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestNewGeo3DTransform(t *testing.T) {
+	tests := []struct {
+		name     string
+		frame    GeoFrame
+		altitude curve.FloatBounds
+		wantErr  bool
+	}{
+		{"geodetic", GeodeticFrame, curve.FloatBounds{Min: -1000, Max: 10000}, false},
+		{"ecef", ECEFFrame, curve.FloatBounds{Min: 0, Max: 400000}, false},
+		{"ecef underground", ECEFFrame, curve.FloatBounds{Min: -5000, Max: -10}, false},
+		{"unknown frame", GeoFrame(42), curve.FloatBounds{Min: 0, Max: 100}, true},
+		{"empty altitude", GeodeticFrame, curve.FloatBounds{Min: 100, Max: 100}, true},
+		{"inverted altitude", GeodeticFrame, curve.FloatBounds{Min: 100, Max: 0}, true},
+		{"nan altitude", GeodeticFrame, curve.FloatBounds{Min: math.NaN(), Max: 0}, true},
+		{"infinite altitude", ECEFFrame, curve.FloatBounds{Min: 0, Max: math.Inf(1)}, true},
+		{"below the centre", ECEFFrame, curve.FloatBounds{Min: -7e6, Max: 0}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGeo3DTransform(tt.frame, tt.altitude)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestGeodeticToECEF(t *testing.T) {
+	tests := []struct {
+		name          string
+		lat, lon, alt float64
+		x, y, z       float64
+	}{
+		{"origin", 0, 0, 0, wgs84A, 0, 0},
+		{"east", 0, 90, 0, 0, wgs84A, 0},
+		{"antimeridian", 0, 180, 100, -wgs84A - 100, 0, 0},
+		{"north pole", 90, 0, 0, 0, 0, 6356752.314245},
+		{"south pole", -90, 45, 1000, 0, 0, -6357752.314245},
+		{"paris", 48.8566, 2.3522, 35, 4200937.80, 172560.72, 4780107.70},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y, z := GeodeticToECEF(tt.lat, tt.lon, tt.alt)
+			assert.InDelta(t, tt.x, x, 0.01)
+			assert.InDelta(t, tt.y, y, 0.01)
+			assert.InDelta(t, tt.z, z, 0.01)
+		})
+	}
+}
+
+func TestGeo3DTransform_Transform(t *testing.T) {
+	sfc, err := curve.NewCurve(curve.Hilbert, 3, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		values  []interface{}
+		wantErr bool
+	}{
+		{"sea level", []interface{}{48.8566, 2.3522, 35.0}, false},
+		{"bounds", []interface{}{-90.0, 180.0, 10000.0}, false},
+		{"minimum altitude", []interface{}{90.0, -180.0, -1000.0}, false},
+		{"not enough values", []interface{}{48.8566, 2.3522}, true},
+		{"latitude not float", []interface{}{48, 2.3522, 35.0}, true},
+		{"longitude not float", []interface{}{48.8566, "2.3522", 35.0}, true},
+		{"altitude not float", []interface{}{48.8566, 2.3522, 35}, true},
+		{"latitude out of range", []interface{}{90.5, 2.3522, 35.0}, true},
+		{"longitude out of range", []interface{}{48.8566, 180.5, 35.0}, true},
+		{"altitude out of range", []interface{}{48.8566, 2.3522, 10001.0}, true},
+		{"nan", []interface{}{math.NaN(), 2.3522, 35.0}, true},
+	}
+	for _, frame := range []GeoFrame{GeodeticFrame, ECEFFrame} {
+		g := mustGeo3DTransform(t, frame)
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := g.Transform(tt.values, sfc)
+				if tt.wantErr {
+					assert.Error(t, err)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Len(t, got, 3)
+				_, err = sfc.Encode(got)
+				assert.NoError(t, err)
+			})
+		}
+		flat, err := curve.NewCurve(curve.Hilbert, 2, 16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = g.Transform([]interface{}{48.8566, 2.3522, 35.0}, flat)
+		assert.Error(t, err)
+	}
+}
+
+func TestGeo3DTransform_Altitude(t *testing.T) {
+	sfc, err := curve.NewUnevenCurve(curve.Morton, []uint64{16, 16, 12})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := mustGeo3DTransform(t, GeodeticFrame)
+	res, err := g.Resolution(sfc)
+	assert.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{180.0 / 65536, 360.0 / 65536, 11000.0 / 4096}, res, 1e-12)
+
+	//altitude keeps its order, latitude and longitude do not change
+	var prev []uint64
+	for alt := -1000.0; alt <= 10000; alt += 250 {
+		got, err := g.Transform([]interface{}{48.8566, 2.3522, alt}, sfc)
+		if !assert.NoError(t, err) {
+			return
+		}
+		if prev != nil {
+			assert.Equal(t, prev[:2], got[:2])
+			assert.True(t, got[2] > prev[2], "altitude %v", alt)
+		}
+		prev = got
+	}
+}
+
+func TestGeo3DTransform_ECEF(t *testing.T) {
+	sfc, err := curve.NewCurve(curve.Hilbert, 3, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := mustGeo3DTransform(t, ECEFFrame)
+	res, err := g.Resolution(sfc)
+	assert.NoError(t, err)
+	for _, r := range res {
+		assert.InDelta(t, 2*(wgs84A+10000)/65536, r, 1e-9)
+	}
+	assert.Equal(t, []curve.FloatBounds{
+		{Min: -wgs84A - 10000, Max: wgs84A + 10000},
+		{Min: -wgs84A - 10000, Max: wgs84A + 10000},
+		{Min: -wgs84A - 10000, Max: wgs84A + 10000},
+	}, g.Bounds())
+
+	//all longitudes meet at the pole and both sides of the antimeridian are neighbours
+	pairs := [][2][]interface{}{
+		{{89.9999, 0.0, 100.0}, {89.9999, 180.0, 100.0}},
+		{{10.0, 179.9999, 100.0}, {10.0, -179.9999, 100.0}},
+	}
+	geodetic := mustGeo3DTransform(t, GeodeticFrame)
+	for _, p := range pairs {
+		a, err := g.Transform(p[0], sfc)
+		assert.NoError(t, err)
+		b, err := g.Transform(p[1], sfc)
+		assert.NoError(t, err)
+		for i := range a {
+			assert.True(t, a[i]-b[i]+1 <= 2, "%v and %v are not neighbours", a, b)
+		}
+
+		a, err = geodetic.Transform(p[0], sfc)
+		assert.NoError(t, err)
+		b, err = geodetic.Transform(p[1], sfc)
+		assert.NoError(t, err)
+		assert.NotEqual(t, a[1], b[1])
+	}
+}
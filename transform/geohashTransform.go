@@ -0,0 +1,232 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	balancer "github.com/struckoff/sfcframework"
+	"github.com/struckoff/sfcframework/curve"
+	"github.com/struckoff/sfcframework/curve/morton"
+)
+
+//geohashAlphabet - base32 alphabet of geohash, each character holds 5 bits.
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+//MaxGeohashPrecision - the longest geohash(in characters) which fits the 64-bit code.
+const MaxGeohashPrecision = 12
+
+//geohashIndex - character -> value of the character, -1 for characters out of the alphabet.
+var geohashIndex = func() (res [256]int8) {
+	for i := range res {
+		res[i] = -1
+	}
+	for i, c := range geohashAlphabet {
+		res[c] = int8(i)
+		if c >= 'a' {
+			res[c-'a'+'A'] = int8(i)
+		}
+	}
+	return res
+}()
+
+//geohashLayout - the placement of latitude and longitude bits on the Morton curve.
+type geohashLayout struct {
+	precision uint64
+	lat       int    //dimension of latitude
+	lon       int    //dimension of longitude
+	latBits   uint64 //size in bits of latitude
+	lonBits   uint64 //size in bits of longitude
+}
+
+//GeohashSizes returns sizes of dimensions of the Morton curve which codes are geohashes with given precision(characters),
+//the result is suitable for NewUnevenBalancer with curve.Morton.
+//
+//Geohash interleaves bits starting with the longitude and the dimension 0 of Morton curve holds the least significant bit,
+//so dimensions are (latitude, longitude) for even precision and (longitude, latitude) for odd precision,
+//where the longitude has an extra bit.
+func GeohashSizes(precision uint64) ([]uint64, error) {
+	l, err := newGeohashLayout(precision)
+	if err != nil {
+		return nil, err
+	}
+	sizes := make([]uint64, 2)
+	sizes[l.lat] = 1 << l.latBits
+	sizes[l.lon] = 1 << l.lonBits
+	return sizes, nil
+}
+
+//GeohashTransform is used to transform geohash strings or geo coordinates to fit the Morton curve with sizes of GeohashSizes,
+//so codes of cells are equal to geohashes of the precision of the curve.
+//It requires a geohash string or two float64 values(latitude, longitude).
+//Geohash longer than the precision is cut, geohash shorter than the precision is an error.
+func GeohashTransform(values []interface{}, sfc curve.Curve) ([]uint64, error) {
+	l, err := geohashLayoutOf(sfc)
+	if err != nil {
+		return nil, err
+	}
+	switch len(values) {
+	case 1:
+		hash, ok := values[0].(string)
+		if !ok {
+			return nil, errors.New("value must be geohash string")
+		}
+		code, err := parseGeohash(hash, l.precision)
+		if err != nil {
+			return nil, err
+		}
+		return sfc.Decode(code)
+	case 2:
+		lat, ok := values[0].(float64)
+		if !ok {
+			return nil, errors.New("first value must be float64 latitude")
+		}
+		lon, ok := values[1].(float64)
+		if !ok {
+			return nil, errors.New("second value must be float64 longitude")
+		}
+		if !(lat >= -90 && lat <= 90) {
+			return nil, fmt.Errorf("latitude == %v must be in range [-90, 90]", lat)
+		}
+		if !(lon >= -180 && lon <= 180) {
+			return nil, fmt.Errorf("longitude == %v must be in range [-180, 180]", lon)
+		}
+		res := make([]uint64, 2)
+		res[l.lat] = bisect(lat, -90, 90, l.latBits)
+		res[l.lon] = bisect(lon, -180, 180, l.lonBits)
+		return res, nil
+	default:
+		return nil, errors.New("number of values must be 1 or 2")
+	}
+}
+
+//Geohash returns the geohash of the cell of the Morton curve with sizes of GeohashSizes.
+func Geohash(code uint64, sfc curve.Curve) (string, error) {
+	l, err := geohashLayoutOf(sfc)
+	if err != nil {
+		return "", err
+	}
+	if code > sfc.Length() {
+		return "", fmt.Errorf("code == %v exceeds limit == %v", code, sfc.Length())
+	}
+	return formatGeohash(code, l.precision), nil
+}
+
+//GeohashPrefixes returns the shortest list of sorted geohash prefixes which cover exactly cells of the range
+//of the Morton curve with sizes of GeohashSizes.
+//Aligned blocks of 32^k codes are cells of the same prefix, so the range is split into the biggest aligned blocks.
+//Wrapping range is split at the end of the curve, the range of the whole curve is the empty prefix.
+func GeohashPrefixes(r balancer.Range, sfc curve.Curve) ([]string, error) {
+	l, err := geohashLayoutOf(sfc)
+	if err != nil {
+		return nil, err
+	}
+	end := sfc.Length() + 1
+	var res []string
+	for _, piece := range r.Split(sfc.Length()) {
+		if piece.Max > end {
+			piece.Max = end
+		}
+		for pos := piece.Min; pos < piece.Max; {
+			k := uint64(0)
+			for k < l.precision {
+				span := uint64(1) << (5 * (k + 1))
+				if pos%span != 0 || piece.Max-pos < span {
+					break
+				}
+				k++
+			}
+			res = append(res, formatGeohash(pos>>(5*k), l.precision-k))
+			pos += 1 << (5 * k)
+		}
+	}
+	return res, nil
+}
+
+//CellGroupGeohashes returns geohash prefixes which cover cells of the cell group(see GeohashPrefixes).
+func CellGroupGeohashes(cg *balancer.CellGroup, sfc curve.Curve) ([]string, error) {
+	return GeohashPrefixes(cg.Range(), sfc)
+}
+
+func newGeohashLayout(precision uint64) (geohashLayout, error) {
+	if precision == 0 || precision > MaxGeohashPrecision {
+		return geohashLayout{}, fmt.Errorf("precision == %v must be in range [1, %v]", precision, MaxGeohashPrecision)
+	}
+	l := geohashLayout{
+		precision: precision,
+		latBits:   5 * precision / 2,
+		lonBits:   (5*precision + 1) / 2,
+	}
+	if precision%2 == 0 {
+		l.lat, l.lon = 0, 1
+	} else {
+		l.lat, l.lon = 1, 0
+	}
+	return l, nil
+}
+
+//geohashLayoutOf returns the layout of the curve, the curve must be 2D Morton curve with sizes of GeohashSizes.
+func geohashLayoutOf(sfc curve.Curve) (geohashLayout, error) {
+	switch sfc.(type) {
+	case *morton.Curve, *morton.Uneven:
+	default:
+		return geohashLayout{}, errors.New("curve must be the Morton curve")
+	}
+	if sfc.Dimensions() != 2 {
+		return geohashLayout{}, errors.New("number of dimensions must be 2")
+	}
+	sizes := curve.DimensionSizes(sfc)
+	total := uint64(bits.Len64(sizes[0]) + bits.Len64(sizes[1]))
+	if total%5 != 0 {
+		return geohashLayout{}, fmt.Errorf("size in bits of the curve == %v must be a multiple of 5", total)
+	}
+	l, err := newGeohashLayout(total / 5)
+	if err != nil {
+		return geohashLayout{}, err
+	}
+	if sizes[l.lat] != 1<<l.latBits-1 || sizes[l.lon] != 1<<l.lonBits-1 {
+		return geohashLayout{}, fmt.Errorf("sizes of dimensions differ from geohash sizes of precision %v", l.precision)
+	}
+	return l, nil
+}
+
+//parseGeohash returns the code of the first precision characters of the geohash.
+func parseGeohash(hash string, precision uint64) (uint64, error) {
+	if uint64(len(hash)) < precision {
+		return 0, fmt.Errorf("geohash %q is shorter than precision %v", hash, precision)
+	}
+	var code uint64
+	for i := uint64(0); i < precision; i++ {
+		v := geohashIndex[hash[i]]
+		if v < 0 {
+			return 0, fmt.Errorf("geohash %q contains invalid character %q", hash, hash[i])
+		}
+		code = code<<5 | uint64(v)
+	}
+	return code, nil
+}
+
+func formatGeohash(code, precision uint64) string {
+	res := make([]byte, precision)
+	for i := len(res) - 1; i >= 0; i-- {
+		res[i] = geohashAlphabet[code&31]
+		code >>= 5
+	}
+	return string(res)
+}
+
+//bisect returns n bits of the value in range [lo, hi] halving the range the same way geohash does.
+func bisect(v, lo, hi float64, n uint64) uint64 {
+	var res uint64
+	for i := uint64(0); i < n; i++ {
+		mid := lo + (hi-lo)/2
+		res <<= 1
+		if v >= mid {
+			res |= 1
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return res
+}
@@ -0,0 +1,263 @@
+package transform
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	balancer "github.com/struckoff/sfcframework"
+	"github.com/struckoff/sfcframework/curve"
+	"github.com/struckoff/sfcframework/mocks"
+	"github.com/struckoff/sfcframework/node"
+)
+
+func mustGeohashCurve(t *testing.T, precision uint64) curve.Curve {
+	sizes, err := GeohashSizes(precision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bits := make([]uint64, len(sizes))
+	for i, size := range sizes {
+		for size > 1 {
+			size >>= 1
+			bits[i]++
+		}
+	}
+	sfc, err := curve.NewUnevenCurve(curve.Morton, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sfc
+}
+
+func TestGeohashSizes(t *testing.T) {
+	tests := []struct {
+		name      string
+		precision uint64
+		want      []uint64
+		wantErr   bool
+	}{
+		{"odd", 1, []uint64{8, 4}, false},
+		{"even", 2, []uint64{32, 32}, false},
+		{"odd 5", 5, []uint64{1 << 13, 1 << 12}, false},
+		{"max", MaxGeohashPrecision, []uint64{1 << 30, 1 << 30}, false},
+		{"zero", 0, nil, true},
+		{"too long", MaxGeohashPrecision + 1, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GeohashSizes(tt.precision)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGeohashTransform(t *testing.T) {
+	tests := []struct {
+		name string
+		lat  float64
+		lon  float64
+		hash string
+	}{
+		{"jutland", 57.64911, 10.40744, "u4pruydqqvj"},
+		{"spain", 42.605, -5.603, "ezs42s000es"},
+		{"origin", 0, 0, "s0000000000"},
+		{"south west", -90, -180, "00000000000"},
+		{"north east", 90, 180, "zzzzzzzzzzz"},
+		{"sydney", -33.8688, 151.2093, "r3gx2f77bn4"},
+	}
+	for precision := uint64(1); precision <= 11; precision++ {
+		sfc := mustGeohashCurve(t, precision)
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s/%d", tt.name, precision), func(t *testing.T) {
+				want := tt.hash[:precision]
+				coords, err := GeohashTransform([]interface{}{tt.lat, tt.lon}, sfc)
+				if !assert.NoError(t, err) {
+					return
+				}
+				code, err := sfc.Encode(coords)
+				assert.NoError(t, err)
+				got, err := Geohash(code, sfc)
+				assert.NoError(t, err)
+				assert.Equal(t, want, got)
+
+				//geohash string is located in the same cell, longer geohash is cut
+				for _, hash := range []string{want, tt.hash, strings.ToUpper(tt.hash)} {
+					fromHash, err := GeohashTransform([]interface{}{hash}, sfc)
+					assert.NoError(t, err)
+					assert.Equal(t, coords, fromHash, hash)
+				}
+			})
+		}
+	}
+}
+
+func TestGeohashTransform_Errors(t *testing.T) {
+	sfc := mustGeohashCurve(t, 5)
+	tests := []struct {
+		name   string
+		values []interface{}
+	}{
+		{"no values", []interface{}{}},
+		{"too many values", []interface{}{1.0, 2.0, 3.0}},
+		{"not string", []interface{}{42}},
+		{"short geohash", []interface{}{"u4pr"}},
+		{"invalid character", []interface{}{"u4pai"}},
+		{"latitude not float", []interface{}{"57", 10.4}},
+		{"longitude not float", []interface{}{57.6, 10}},
+		{"latitude out of range", []interface{}{91.0, 10.4}},
+		{"longitude out of range", []interface{}{57.6, -181.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GeohashTransform(tt.values, sfc)
+			assert.Error(t, err)
+		})
+	}
+
+	curves := []struct {
+		name string
+		sfc  curve.Curve
+	}{
+		{"hilbert", mustCurve(t, curve.Hilbert, 2, 10)},
+		{"morton 3D", mustCurve(t, curve.Morton, 3, 5)},
+		{"not multiple of 5", mustCurve(t, curve.Morton, 2, 4)},
+		{"swapped dimensions", mustUneven(t, []uint64{12, 13})},
+	}
+	for _, tt := range curves {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GeohashTransform([]interface{}{"u4pru"}, tt.sfc)
+			assert.Error(t, err)
+			_, err = Geohash(0, tt.sfc)
+			assert.Error(t, err)
+			_, err = GeohashPrefixes(balancer.NewRange(0, 1), tt.sfc)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := Geohash(sfc.Length()+1, sfc)
+	assert.Error(t, err)
+}
+
+func mustCurve(t *testing.T, cType curve.CurveType, dims, bits uint64) curve.Curve {
+	sfc, err := curve.NewCurve(cType, dims, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sfc
+}
+
+func mustUneven(t *testing.T, bits []uint64) curve.Curve {
+	sfc, err := curve.NewUnevenCurve(curve.Morton, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sfc
+}
+
+func TestGeohashPrefixes(t *testing.T) {
+	sfc := mustGeohashCurve(t, 2)
+	tests := []struct {
+		name string
+		r    balancer.Range
+		want []string
+	}{
+		{"whole curve", balancer.NewRange(0, 1024), []string{""}},
+		{"single cell", balancer.NewRange(33, 34), []string{"11"}},
+		{"block", balancer.NewRange(32, 64), []string{"1"}},
+		{"unaligned", balancer.NewRange(30, 66), []string{"0y", "0z", "1", "20", "21"}},
+		{"wrapping", balancer.NewRingRange(1023, 32, 1023), []string{"0", "zz"}},
+		{"beyond the curve", balancer.NewRange(992, 2000), []string{"z"}},
+		{"empty", balancer.NewRange(5, 5), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GeohashPrefixes(tt.r, sfc)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	//prefixes cover exactly cells of the range
+	rnd := rand.New(rand.NewSource(42))
+	for _, precision := range []uint64{1, 2, 3} {
+		sfc := mustGeohashCurve(t, precision)
+		for i := 0; i < 20; i++ {
+			a := uint64(rnd.Int63n(int64(sfc.Length()) + 1))
+			b := uint64(rnd.Int63n(int64(sfc.Length()) + 2))
+			r := balancer.NewRingRange(a, b, sfc.Length())
+			prefixes, err := GeohashPrefixes(r, sfc)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.True(t, sort.StringsAreSorted(prefixes) || r.Wraps(), "%v", prefixes)
+			for code := uint64(0); code <= sfc.Length(); code++ {
+				hash, err := Geohash(code, sfc)
+				if !assert.NoError(t, err) {
+					return
+				}
+				covered, want := 0, 0
+				for _, p := range prefixes {
+					if strings.HasPrefix(hash, p) {
+						covered++
+					}
+				}
+				if r.Fits(code) {
+					want = 1
+				}
+				if covered != want {
+					assert.Equal(t, want, covered, "range %v, geohash %v", r, hash)
+				}
+			}
+		}
+	}
+}
+
+func TestCellGroupGeohashes(t *testing.T) {
+	ns := make([]node.Node, 5)
+	for i := range ns {
+		n := &mocks.Node{}
+		n.On("ID").Return(fmt.Sprintf("node-%d", i))
+		ns[i] = n
+	}
+	sizes, err := GeohashSizes(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := balancer.NewUnevenBalancer(curve.Morton, sizes, GeohashTransform, nil, ns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &mocks.DataItem{}
+	d.On("ID").Return("jutland")
+	d.On("Values").Return([]interface{}{57.64911, 10.40744})
+	n, cID, err := b.LocateData(d)
+	if !assert.NoError(t, err) {
+		return
+	}
+	hash, err := Geohash(cID, b.SFC())
+	assert.NoError(t, err)
+	assert.Equal(t, "u4p", hash)
+
+	//the geohash is covered by prefixes of the cell group of the node only
+	for _, cg := range b.Space().CellGroups() {
+		prefixes, err := CellGroupGeohashes(cg, b.SFC())
+		if !assert.NoError(t, err) {
+			return
+		}
+		covered := false
+		for _, p := range prefixes {
+			covered = covered || strings.HasPrefix(hash, p)
+		}
+		assert.Equal(t, cg.Node().ID() == n.ID(), covered, cg.ID())
+	}
+}
@@ -0,0 +1,104 @@
+package transform
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+
+	"github.com/struckoff/sfcframework/curve"
+)
+
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+	golden    = 0x9e3779b97f4a7c15 //2^64 / golden ratio, increment of splitmix64
+)
+
+//Hasher computes 64-bit hash of the key with the seed.
+type Hasher interface {
+	Hash(key []byte, seed uint64) uint64
+}
+
+//HasherFunc adapts the function to the Hasher interface.
+type HasherFunc func(key []byte, seed uint64) uint64
+
+//Hash calls f(key, seed).
+func (f HasherFunc) Hash(key []byte, seed uint64) uint64 {
+	return f(key, seed)
+}
+
+//FNV1a - 64-bit FNV-1a hash, the seed is mixed into the offset basis(seed 0 gives the standard FNV-1a).
+var FNV1a Hasher = HasherFunc(fnv1a)
+
+//Mix - seeded hash which mixes the key word by word(8 bytes) with the splitmix64 finalizer.
+var Mix Hasher = HasherFunc(mixHash)
+
+//HashTransform returns a transform function for one string or []byte key.
+//
+//The key is hashed once by the hasher with the seed,
+//the value of each dimension is derived from the hash by the splitmix64 sequence
+//and scaled to the size of the dimension, so keys are spread uniformly over cells.
+//If the hasher is nil, Mix is used.
+//
+//Unlike KVTransform, similar keys(anagrams, keys with common prefix) do not cluster in the same cells.
+func HashTransform(h Hasher, seed uint64) func(values []interface{}, sfc curve.Curve) ([]uint64, error) {
+	if h == nil {
+		h = Mix
+	}
+	return func(values []interface{}, sfc curve.Curve) ([]uint64, error) {
+		if len(values) != 1 {
+			return nil, errors.New("number of values must be 1")
+		}
+		var key []byte
+		switch v := values[0].(type) {
+		case string:
+			key = []byte(v)
+		case []byte:
+			key = v
+		default:
+			return nil, errors.New("value must be string or []byte")
+		}
+		hash := h.Hash(key, seed)
+		sizes := curve.DimensionSizes(sfc)
+		res := make([]uint64, len(sizes))
+		for i, size := range sizes {
+			x := mix64(hash + uint64(i+1)*golden)
+			if size+1 == 0 {
+				res[i] = x
+				continue
+			}
+			//the high word of x * (size + 1) is uniform in range [0, size]
+			res[i], _ = bits.Mul64(x, size+1)
+		}
+		return res, nil
+	}
+}
+
+func fnv1a(key []byte, seed uint64) uint64 {
+	h := uint64(fnvOffset) ^ seed
+	for _, b := range key {
+		h ^= uint64(b)
+		h *= fnvPrime
+	}
+	return h
+}
+
+func mixHash(key []byte, seed uint64) uint64 {
+	h := mix64(seed ^ uint64(len(key))*golden)
+	for len(key) >= 8 {
+		h = mix64(h ^ binary.LittleEndian.Uint64(key) + golden)
+		key = key[8:]
+	}
+	var tail uint64
+	for i, b := range key {
+		tail |= uint64(b) << (8 * i)
+	}
+	return mix64(h ^ tail + golden)
+}
+
+//mix64 - the finalizer of splitmix64, every bit of the input affects every bit of the output.
+func mix64(z uint64) uint64 {
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
@@ -0,0 +1,230 @@
+package transform
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/struckoff/sfcframework/curve"
+)
+
+func TestHashTransform(t *testing.T) {
+	sfc, err := curve.NewCurve(curve.Morton, 3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		values  []interface{}
+		wantErr bool
+	}{
+		{"string", []interface{}{"user:123"}, false},
+		{"bytes", []interface{}{[]byte("user:123")}, false},
+		{"empty", []interface{}{""}, false},
+		{"not enough values", []interface{}{}, true},
+		{"too many values", []interface{}{"a", "b"}, true},
+		{"not string", []interface{}{42}, true},
+	}
+	for _, h := range []Hasher{nil, FNV1a, Mix} {
+		tf := HashTransform(h, 42)
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := tf(tt.values, sfc)
+				if tt.wantErr {
+					assert.Error(t, err)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Len(t, got, 3)
+				for _, x := range got {
+					assert.True(t, x <= sfc.DimensionSize())
+				}
+				again, err := tf(tt.values, sfc)
+				assert.NoError(t, err)
+				assert.Equal(t, got, again)
+			})
+		}
+	}
+
+	s, err := HashTransform(Mix, 42)([]interface{}{"user:123"}, sfc)
+	assert.NoError(t, err)
+	b, err := HashTransform(Mix, 42)([]interface{}{[]byte("user:123")}, sfc)
+	assert.NoError(t, err)
+	assert.Equal(t, s, b)
+}
+
+func TestFNV1a(t *testing.T) {
+	for _, key := range []string{"", "a", "user:123", "the quick brown fox"} {
+		h := fnv.New64a()
+		h.Write([]byte(key))
+		assert.Equal(t, h.Sum64(), FNV1a.Hash([]byte(key), 0), key)
+		assert.NotEqual(t, h.Sum64(), FNV1a.Hash([]byte(key), 1), key)
+	}
+}
+
+func TestHashTransform_Anagrams(t *testing.T) {
+	sfc, err := curve.NewCurve(curve.Hilbert, 2, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := []string{"user:123", "user:132", "user:213", "user:231", "user:312", "user:321"}
+	for _, h := range []Hasher{FNV1a, Mix} {
+		seen := map[string]bool{}
+		for _, key := range keys {
+			coords, err := HashTransform(h, 0)([]interface{}{key}, sfc)
+			assert.NoError(t, err)
+			seen[fmt.Sprint(coords)] = true
+		}
+		assert.Len(t, seen, len(keys))
+	}
+
+	//sum of runes does not depend on their order
+	old := map[string]bool{}
+	for _, key := range keys {
+		coords, err := KVTransform([]interface{}{key}, sfc)
+		assert.NoError(t, err)
+		old[fmt.Sprint(coords)] = true
+	}
+	assert.True(t, len(old) < len(keys))
+}
+
+func TestHashTransform_Seed(t *testing.T) {
+	sfc, err := curve.NewCurve(curve.Morton, 2, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, h := range []Hasher{FNV1a, Mix} {
+		same := 0
+		for i := 0; i < 1000; i++ {
+			key := []interface{}{fmt.Sprintf("user:%d", i)}
+			a, err := HashTransform(h, 1)(key, sfc)
+			assert.NoError(t, err)
+			b, err := HashTransform(h, 2)(key, sfc)
+			assert.NoError(t, err)
+			if a[0] == b[0] && a[1] == b[1] {
+				same++
+			}
+		}
+		assert.True(t, same < 5, "%v keys are located in the same cell with different seeds", same)
+	}
+}
+
+//chiSquare returns the chi-square statistic of the counts against the uniform distribution.
+func chiSquare(counts []int, total int) float64 {
+	expected := float64(total) / float64(len(counts))
+	var res float64
+	for _, c := range counts {
+		d := float64(c) - expected
+		res += d * d / expected
+	}
+	return res
+}
+
+//chiSquareLimit returns the value which chi-square statistic of uniform counts exceeds with negligible probability.
+func chiSquareLimit(buckets int) float64 {
+	dof := float64(buckets - 1)
+	return dof + 6*math.Sqrt(2*dof)
+}
+
+func TestHashTransform_Uniform(t *testing.T) {
+	keySets := []struct {
+		name string
+		key  func(i int) string
+	}{
+		{"sequential", func(i int) string { return fmt.Sprintf("user:%d", i) }},
+		{"short", func(i int) string { return fmt.Sprintf("%x", i) }},
+		{"common prefix", func(i int) string { return fmt.Sprintf("tenant/eu-west-1/bucket/objects/%08d", i) }},
+	}
+	curves := []struct {
+		name  string
+		cType curve.CurveType
+		dims  uint64
+		bits  uint64
+	}{
+		{"morton 1x8", curve.Morton, 1, 8},
+		{"hilbert 2x4", curve.Hilbert, 2, 4},
+		{"morton 3x3", curve.Morton, 3, 3},
+		{"peano 2x2", curve.Peano, 2, 2},
+	}
+	const n = 1 << 16
+	for _, h := range []struct {
+		name string
+		h    Hasher
+	}{{"fnv1a", FNV1a}, {"mix", Mix}} {
+		for _, ks := range keySets {
+			for _, cs := range curves {
+				t.Run(h.name+"/"+ks.name+"/"+cs.name, func(t *testing.T) {
+					sfc, err := curve.NewCurve(cs.cType, cs.dims, cs.bits)
+					if err != nil {
+						t.Fatal(err)
+					}
+					tf := HashTransform(h.h, 0)
+					//every cell of the curve and every value of each dimension are buckets
+					cells := make([]int, sfc.Length()+1)
+					dims := make([][]int, sfc.Dimensions())
+					for i := range dims {
+						dims[i] = make([]int, sfc.DimensionSize()+1)
+					}
+					for i := 0; i < n; i++ {
+						coords, err := tf([]interface{}{ks.key(i)}, sfc)
+						if !assert.NoError(t, err) {
+							return
+						}
+						for d, x := range coords {
+							dims[d][x]++
+						}
+						code, err := sfc.Encode(coords)
+						if !assert.NoError(t, err) {
+							return
+						}
+						cells[code]++
+					}
+					chi := chiSquare(cells, n)
+					assert.True(t, chi < chiSquareLimit(len(cells)), "cells: chi-square == %.1f, limit %.1f", chi, chiSquareLimit(len(cells)))
+					for d := range dims {
+						chi := chiSquare(dims[d], n)
+						assert.True(t, chi < chiSquareLimit(len(dims[d])), "dimension %v: chi-square == %.1f", d, chi)
+					}
+				})
+			}
+		}
+	}
+
+	//the sum of runes is far from uniform on the same keys
+	sfc, err := curve.NewCurve(curve.Morton, 1, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cells := make([]int, sfc.Length()+1)
+	for i := 0; i < n; i++ {
+		coords, err := KVTransform([]interface{}{fmt.Sprintf("user:%d", i)}, sfc)
+		assert.NoError(t, err)
+		cells[coords[0]]++
+	}
+	assert.True(t, chiSquare(cells, n) > chiSquareLimit(len(cells)))
+}
+
+func BenchmarkHashTransform(b *testing.B) {
+	sfc, err := curve.NewCurve(curve.Hilbert, 3, 20)
+	if err != nil {
+		b.Fatal(err)
+	}
+	key := []interface{}{"tenant/eu-west-1/bucket/objects/00012345"}
+	for _, h := range []struct {
+		name string
+		h    Hasher
+	}{{"fnv1a", FNV1a}, {"mix", Mix}} {
+		tf := HashTransform(h.h, 0)
+		b.Run(h.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := tf(key, sfc); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -8,12 +8,14 @@ import (
 
 //KVTransform is used to transform string to fit SFC.
 //It requires one string value.
+//Each dimension is the sum of runes of the part of the key, so anagrams collide,
+//HashTransform spreads keys uniformly.
 func KVTransform(values []interface{}, sfc curve.Curve) ([]uint64, error) {
 	if len(values) != 1 {
 		return nil, errors.New("number of values must be 1")
 	}
 
-	ds := sfc.DimensionSize()
+	ds := curve.DimensionSizes(sfc)
 	dc := int(sfc.Dimensions())
 	key, ok := values[0].(string)
 
@@ -34,12 +36,12 @@ func KVTransform(values []interface{}, sfc curve.Curve) ([]uint64, error) {
 		}
 
 		if i < dc-1 {
-			res[i] = stringhash(key[:cut], ds)
+			res[i] = stringhash(key[:cut], ds[i])
 			key = key[cut:]
 			continue
 		}
 
-		res[i] = stringhash(key, ds)
+		res[i] = stringhash(key, ds[i])
 	}
 
 	return res, nil
@@ -0,0 +1,150 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	balancer "github.com/struckoff/sfcframework"
+	"github.com/struckoff/sfcframework/curve"
+	"github.com/struckoff/sfcframework/node"
+)
+
+//maxBudget - the maximum amount of bytes of the key mapped to a single dimension.
+const maxBudget = 8
+
+//PrefixTransform maps leading bytes of string keys onto coordinates and preserves the lexicographic order of keys.
+//
+//Bytes of the key are split between dimensions in order: the first budgets[0] bytes are mapped to the dimension 0,
+//the next budgets[1] bytes to the dimension 1 and so on, missing bytes of short keys are zeros.
+//Bytes of each dimension are scaled to the size of the dimension, so keys keep their order along each dimension
+//and keys with the common prefix are located in a single box of cells(see Box).
+//If the dimension has less than 8*budget bits, keys which differ only in lower bytes of the dimension share the coordinate.
+//Box queries of the curve turn the box into a few ranges of codes(see CellGroups),
+//with a single dimension keys with the common prefix form a contiguous range of codes of Hilbert and Morton curves.
+type PrefixTransform struct {
+	budgets []uint64
+}
+
+//NewPrefixTransform creates a transform with the amount of bytes of the key mapped to each dimension.
+//Each budget must be in range [1, 8].
+func NewPrefixTransform(budgets []uint64) (*PrefixTransform, error) {
+	if len(budgets) == 0 {
+		return nil, errors.New("at least one budget is required")
+	}
+	for i, b := range budgets {
+		if b == 0 || b > maxBudget {
+			return nil, fmt.Errorf("budget == %v of dimension %v must be in range [1, %v]", b, i, maxBudget)
+		}
+	}
+	return &PrefixTransform{budgets: append([]uint64{}, budgets...)}, nil
+}
+
+//Budgets returns the amount of bytes of the key mapped to each dimension.
+func (p *PrefixTransform) Budgets() []uint64 {
+	return append([]uint64{}, p.budgets...)
+}
+
+//Transform is the transform function of the balancer, it requires one string value.
+func (p *PrefixTransform) Transform(values []interface{}, sfc curve.Curve) ([]uint64, error) {
+	if len(values) != 1 {
+		return nil, errors.New("number of values must be 1")
+	}
+	key, ok := values[0].(string)
+	if !ok {
+		return nil, errors.New("value must be string")
+	}
+	sizes, err := p.sizes(sfc)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]uint64, len(sizes))
+	for i, part := range p.split(key) {
+		res[i] = scale(word(part, 0), p.budgets[i], sizes[i])
+	}
+	return res, nil
+}
+
+//Box returns the box of cells where keys with the prefix are located.
+//Dimensions which bytes are covered by the prefix are fixed,
+//the dimension where the prefix ends is limited by the rest of the prefix and other dimensions are not limited.
+//The box may contain keys without the prefix if cells are wider than a byte.
+func (p *PrefixTransform) Box(prefix string, sfc curve.Curve) (min, max []uint64, err error) {
+	sizes, err := p.sizes(sfc)
+	if err != nil {
+		return nil, nil, err
+	}
+	min = make([]uint64, len(sizes))
+	max = make([]uint64, len(sizes))
+	for i, part := range p.split(prefix) {
+		min[i] = scale(word(part, 0), p.budgets[i], sizes[i])
+		max[i] = scale(word(part, 0xff), p.budgets[i], sizes[i])
+	}
+	return min, max, nil
+}
+
+//CellGroups returns cell groups of the space which contain keys with the prefix,
+//so a prefix scan is routed only to their nodes.
+func (p *PrefixTransform) CellGroups(s *balancer.Space, prefix string) ([]*balancer.CellGroup, error) {
+	min, max, err := p.Box(prefix, s.SFC())
+	if err != nil {
+		return nil, err
+	}
+	return s.BoxCellGroups(min, max)
+}
+
+//Nodes returns nodes of the balancer which contain keys with the prefix.
+func (p *PrefixTransform) Nodes(b *balancer.Balancer, prefix string) ([]node.Node, error) {
+	min, max, err := p.Box(prefix, b.SFC())
+	if err != nil {
+		return nil, err
+	}
+	return b.BoxNodes(min, max)
+}
+
+func (p *PrefixTransform) sizes(sfc curve.Curve) ([]uint64, error) {
+	if sfc.Dimensions() != uint64(len(p.budgets)) {
+		return nil, fmt.Errorf("number of dimensions == %v differs from number of budgets == %v", sfc.Dimensions(), len(p.budgets))
+	}
+	return curve.DimensionSizes(sfc), nil
+}
+
+//split returns bytes of the key which belong to each dimension, parts of short keys are cut or empty.
+func (p *PrefixTransform) split(key string) []string {
+	res := make([]string, len(p.budgets))
+	for i, b := range p.budgets {
+		if uint64(len(key)) <= b {
+			res[i] = key
+			break
+		}
+		res[i], key = key[:b], key[b:]
+	}
+	return res
+}
+
+//word returns the big-endian value of bytes aligned to the most significant byte,
+//bytes missing up to 8 are filled with fill.
+func word(part string, fill byte) uint64 {
+	var res uint64
+	for i := 0; i < maxBudget; i++ {
+		b := fill
+		if i < len(part) {
+			b = part[i]
+		}
+		res = res<<8 | uint64(b)
+	}
+	return res
+}
+
+//scale maps the word of budget bytes onto the range [0, size] keeping the order.
+func scale(w, budget, size uint64) uint64 {
+	if budget < maxBudget {
+		//only budget leading bytes belong to the dimension
+		w &^= 1<<(8*(maxBudget-budget)) - 1
+	}
+	if size+1 == 0 {
+		return w
+	}
+	res, _ := bits.Mul64(w, size+1)
+	return res
+}
@@ -0,0 +1,292 @@
+package transform
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	balancer "github.com/struckoff/sfcframework"
+	"github.com/struckoff/sfcframework/curve"
+	"github.com/struckoff/sfcframework/mocks"
+	"github.com/struckoff/sfcframework/node"
+)
+
+func mustPrefixTransform(t *testing.T, budgets []uint64) *PrefixTransform {
+	p, err := NewPrefixTransform(budgets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestNewPrefixTransform(t *testing.T) {
+	tests := []struct {
+		name    string
+		budgets []uint64
+		wantErr bool
+	}{
+		{"one dimension", []uint64{8}, false},
+		{"three dimensions", []uint64{1, 4, 8}, false},
+		{"no budgets", nil, true},
+		{"zero budget", []uint64{2, 0}, true},
+		{"budget exceeds word", []uint64{9}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewPrefixTransform(tt.budgets)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.budgets, got.Budgets())
+		})
+	}
+}
+
+func TestPrefixTransform_Transform(t *testing.T) {
+	sfc, err := curve.NewCurve(curve.Morton, 2, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := mustPrefixTransform(t, []uint64{2, 2})
+	tests := []struct {
+		name    string
+		values  []interface{}
+		want    []uint64
+		wantErr bool
+	}{
+		{"full", []interface{}{"abcd"}, []uint64{0x6162, 0x6364}, false},
+		{"long", []interface{}{"abcdef"}, []uint64{0x6162, 0x6364}, false},
+		{"short", []interface{}{"abc"}, []uint64{0x6162, 0x6300}, false},
+		{"shorter than budget", []interface{}{"a"}, []uint64{0x6100, 0}, false},
+		{"empty", []interface{}{""}, []uint64{0, 0}, false},
+		{"not enough values", []interface{}{}, nil, true},
+		{"too many values", []interface{}{"a", "b"}, nil, true},
+		{"not string", []interface{}{42}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.Transform(tt.values, sfc)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	wrong, err := curve.NewCurve(curve.Morton, 3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = p.Transform([]interface{}{"abcd"}, wrong)
+	assert.Error(t, err)
+}
+
+func TestPrefixTransform_Order(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	keys := make([]string, 2000)
+	for i := range keys {
+		b := make([]byte, rnd.Intn(12))
+		for j := range b {
+			b[j] = byte('a' + rnd.Intn(4))
+		}
+		keys[i] = string(b)
+	}
+	sort.Strings(keys)
+	tests := []struct {
+		name    string
+		cType   curve.CurveType
+		bits    []uint64
+		budgets []uint64
+		exact   bool
+	}{
+		{"one dimension", curve.Morton, []uint64{20}, []uint64{3}, true},
+		{"two dimensions", curve.Hilbert, []uint64{16, 16}, []uint64{2, 4}, true},
+		{"uneven", curve.Morton, []uint64{8, 16, 7}, []uint64{1, 2, 8}, true},
+		{"coarse", curve.Morton, []uint64{3, 12, 7}, []uint64{1, 2, 8}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sfc, err := curve.NewUnevenCurve(tt.cType, tt.bits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := mustPrefixTransform(t, tt.budgets)
+			var prev []uint64
+			for _, key := range keys {
+				coords, err := p.Transform([]interface{}{key}, sfc)
+				if !assert.NoError(t, err) {
+					return
+				}
+				//coordinates of sorted keys are sorted in the order of dimensions,
+				//if cells are wider than bytes only the first dimension keeps the order
+				if prev != nil && tt.exact {
+					assert.True(t, compareCoords(prev, coords) <= 0, "%v > %v, key %q", prev, coords, key)
+				} else if prev != nil {
+					assert.True(t, prev[0] <= coords[0], "%v > %v, key %q", prev, coords, key)
+				}
+				prev = coords
+			}
+		})
+	}
+}
+
+func compareCoords(a, b []uint64) int {
+	for i := range a {
+		if a[i] < b[i] {
+			return -1
+		}
+		if a[i] > b[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+func TestPrefixTransform_Box(t *testing.T) {
+	sfc, err := curve.NewUnevenCurve(curve.Morton, []uint64{6, 12, 16})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := mustPrefixTransform(t, []uint64{1, 2, 2})
+	rnd := rand.New(rand.NewSource(42))
+	for _, prefix := range []string{"", "u", "us", "use", "user", "user:", "user:1", "\xff\xff"} {
+		t.Run(prefix, func(t *testing.T) {
+			min, max, err := p.Box(prefix, sfc)
+			if !assert.NoError(t, err) {
+				return
+			}
+			for i := 0; i < 200; i++ {
+				b := make([]byte, rnd.Intn(8))
+				rnd.Read(b)
+				coords, err := p.Transform([]interface{}{prefix + string(b)}, sfc)
+				if !assert.NoError(t, err) {
+					return
+				}
+				for d := range coords {
+					assert.True(t, min[d] <= coords[d] && coords[d] <= max[d], "key %q, dimension %v", prefix+string(b), d)
+				}
+			}
+		})
+	}
+
+	min, max, err := p.Box("", sfc)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{0, 0, 0}, min)
+	assert.Equal(t, curve.DimensionSizes(sfc), max)
+
+	//the dimension of the last byte of the prefix is limited, the rest are not
+	min, max, err = p.Box("us", sfc)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{'u' >> 2, 's' << 4, 0}, min)
+	assert.Equal(t, []uint64{'u' >> 2, 's'<<4 | 0xf, 0xffff}, max)
+
+	wrong, err := curve.NewCurve(curve.Morton, 2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = p.Box("user", wrong)
+	assert.Error(t, err)
+}
+
+func TestPrefixTransform_Contiguous(t *testing.T) {
+	for _, cType := range []curve.CurveType{curve.Hilbert, curve.Morton} {
+		t.Run(cType.String(), func(t *testing.T) {
+			sfc, err := curve.NewCurve(cType, 1, 24)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := mustPrefixTransform(t, []uint64{4})
+			min, max, err := p.Box("us", sfc)
+			assert.NoError(t, err)
+			first, err := sfc.Encode(min)
+			assert.NoError(t, err)
+			last, err := sfc.Encode(max)
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(0xff), last-first)
+			for _, key := range []string{"us", "user", "us\x00", "us\xff\xff"} {
+				coords, err := p.Transform([]interface{}{key}, sfc)
+				assert.NoError(t, err)
+				code, err := sfc.Encode(coords)
+				assert.NoError(t, err)
+				assert.True(t, first <= code && code <= last, key)
+			}
+		})
+	}
+}
+
+func TestPrefixTransform_Nodes(t *testing.T) {
+	ns := make([]node.Node, 16)
+	for i := range ns {
+		n := &mocks.Node{}
+		n.On("ID").Return(fmt.Sprintf("node-%d", i))
+		ns[i] = n
+	}
+	p := mustPrefixTransform(t, []uint64{4, 4})
+	for _, cType := range []curve.CurveType{curve.Hilbert, curve.Morton} {
+		t.Run(cType.String(), func(t *testing.T) {
+			b, err := balancer.NewBalancer(cType, 2, 256, p.Transform, nil, ns)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, prefix := range []string{"user:", "us", "u", "order:2020"} {
+				got, err := p.Nodes(b, prefix)
+				if !assert.NoError(t, err) {
+					return
+				}
+				cgs, err := p.CellGroups(b.Space(), prefix)
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.True(t, len(got) <= len(cgs), prefix)
+				ids := map[string]bool{}
+				for _, n := range got {
+					ids[n.ID()] = true
+				}
+				//every key with the prefix is located on one of the nodes
+				for i := 0; i < 500; i++ {
+					d := &mocks.DataItem{}
+					d.On("ID").Return(fmt.Sprintf("%s%d", prefix, i))
+					d.On("Values").Return([]interface{}{fmt.Sprintf("%s%d", prefix, i)})
+					n, _, err := b.LocateData(d)
+					if !assert.NoError(t, err) {
+						return
+					}
+					assert.True(t, ids[n.ID()], "key %s%d is located on %s", prefix, i, n.ID())
+				}
+			}
+
+			//prefix scan over "user:" hits a single node, the whole space is spread over all nodes
+			got, err := p.Nodes(b, "user:")
+			assert.NoError(t, err)
+			assert.Len(t, got, 1)
+			all, err := p.Nodes(b, "")
+			assert.NoError(t, err)
+			assert.Len(t, all, len(ns))
+		})
+	}
+}
+
+func BenchmarkPrefixTransform(b *testing.B) {
+	sfc, err := curve.NewCurve(curve.Hilbert, 3, 20)
+	if err != nil {
+		b.Fatal(err)
+	}
+	p, err := NewPrefixTransform([]uint64{4, 4, 4})
+	if err != nil {
+		b.Fatal(err)
+	}
+	key := []interface{}{"tenant/eu-west-1/bucket/objects/00012345"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := p.Transform(key, sfc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package transform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/struckoff/sfcframework/curve"
+)
+
+//QuantileModel - the piecewise-linear approximation of the cumulative distribution function of each dimension,
+//fitted from samples(see FitQuantileModel).
+//
+//Boundaries of the dimension are quantiles of samples at 0, 1/B, 2/B ... 1, where B is the amount of buckets,
+//so each bucket(equi-depth histogram) holds the same share of samples.
+//Transform maps values through the function, so coordinates of values with the same distribution as samples are uniform
+//and skewed values do not pile into a few cells.
+//Dimensions are fitted independently, so values which depend on each other(latitude and longitude of cities)
+//are uniform along each dimension but not over cells.
+//
+//The model is serialized to JSON as it is, so every instance which loads the model maps values identically.
+type QuantileModel struct {
+	Boundaries [][]float64 `json:"boundaries"` //[dimension] -> sorted quantiles of samples
+}
+
+//FitQuantileModel fits the model from samples, each sample is the slice of values of dimensions(DataItem.Values()).
+//Values are numbers(ints, uints, floats, time.Duration), buckets is the amount of buckets of each dimension.
+func FitQuantileModel(samples [][]interface{}, buckets int) (*QuantileModel, error) {
+	if buckets < 1 {
+		return nil, fmt.Errorf("amount of buckets == %v must be positive", buckets)
+	}
+	if len(samples) == 0 {
+		return nil, errors.New("at least one sample is required")
+	}
+	dims := len(samples[0])
+	if dims == 0 {
+		return nil, errors.New("samples must have values")
+	}
+	values := make([][]float64, dims)
+	for i := range values {
+		values[i] = make([]float64, len(samples))
+	}
+	for n, s := range samples {
+		if len(s) != dims {
+			return nil, fmt.Errorf("sample %v: number of values == %v differs from %v", n, len(s), dims)
+		}
+		for i, v := range s {
+			f, _, err := toFloat(v)
+			if err != nil {
+				return nil, fmt.Errorf("sample %v, value %v: %w", n, i, err)
+			}
+			if math.IsInf(f, 0) {
+				return nil, fmt.Errorf("sample %v, value %v is infinite", n, i)
+			}
+			values[i][n] = f
+		}
+	}
+	m := &QuantileModel{Boundaries: make([][]float64, dims)}
+	for i, vs := range values {
+		sort.Float64s(vs)
+		b := make([]float64, buckets+1)
+		for j := range b {
+			//linear interpolation between the closest ranks
+			rank := float64(j) * float64(len(vs)-1) / float64(buckets)
+			lo := int(math.Floor(rank))
+			hi := int(math.Ceil(rank))
+			b[j] = vs[lo] + (vs[hi]-vs[lo])*(rank-float64(lo))
+		}
+		m.Boundaries[i] = b
+	}
+	return m, nil
+}
+
+//Transform is the transform function of the balancer, the amount of values and dimensions of the curve must be equal to dimensions of the model.
+//Values out of range of samples are limited by the lowest and the highest boundaries.
+func (m *QuantileModel) Transform(values []interface{}, sfc curve.Curve) ([]uint64, error) {
+	if sfc.Dimensions() != uint64(len(m.Boundaries)) {
+		return nil, fmt.Errorf("number of dimensions == %v differs from dimensions of the model == %v", sfc.Dimensions(), len(m.Boundaries))
+	}
+	if len(values) != len(m.Boundaries) {
+		return nil, fmt.Errorf("number of values must be %v", len(m.Boundaries))
+	}
+	sizes := curve.DimensionSizes(sfc)
+	res := make([]uint64, len(values))
+	for i, v := range values {
+		f, _, err := toFloat(v)
+		if err != nil {
+			return nil, fmt.Errorf("value %v: %w", i, err)
+		}
+		res[i] = cell(cdf(m.Boundaries[i], f), sizes[i])
+	}
+	return res, nil
+}
+
+//UnmarshalJSON decodes the model and validates boundaries.
+func (m *QuantileModel) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Boundaries [][]float64 `json:"boundaries"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw.Boundaries) == 0 {
+		return errors.New("model must have dimensions")
+	}
+	for i, b := range raw.Boundaries {
+		if len(b) < 2 {
+			return fmt.Errorf("dimension %v must have at least 2 boundaries", i)
+		}
+		if !sort.Float64sAreSorted(b) {
+			return fmt.Errorf("boundaries of dimension %v must be sorted", i)
+		}
+	}
+	m.Boundaries = raw.Boundaries
+	return nil
+}
+
+//cdf returns the share of samples which are less than the value in range [0, 1].
+//Values equal to several boundaries(frequent samples) are placed in the middle of them.
+func cdf(b []float64, v float64) float64 {
+	last := len(b) - 1
+	lo := sort.SearchFloat64s(b, v)
+	if lo > last {
+		return 1
+	}
+	if b[lo] == v {
+		hi := sort.Search(len(b), func(i int) bool { return b[i] > v }) - 1
+		return float64(lo+hi) / 2 / float64(last)
+	}
+	if lo == 0 {
+		return 0
+	}
+	j := lo - 1
+	return (float64(j) + (v-b[j])/(b[lo]-b[j])) / float64(last)
+}
+
+//toFloat converts the number to float64 and returns the kind of its type.
+func toFloat(v interface{}) (float64, ValueKind, error) {
+	var f float64
+	var kind ValueKind
+	switch x := v.(type) {
+	case int:
+		f, kind = float64(x), IntKind
+	case int8:
+		f, kind = float64(x), IntKind
+	case int16:
+		f, kind = float64(x), IntKind
+	case int32:
+		f, kind = float64(x), IntKind
+	case int64:
+		f, kind = float64(x), IntKind
+	case uint:
+		f, kind = float64(x), UintKind
+	case uint8:
+		f, kind = float64(x), UintKind
+	case uint16:
+		f, kind = float64(x), UintKind
+	case uint32:
+		f, kind = float64(x), UintKind
+	case uint64:
+		f, kind = float64(x), UintKind
+	case float32:
+		f, kind = float64(x), FloatKind
+	case float64:
+		f, kind = x, FloatKind
+	case time.Duration:
+		f, kind = float64(x), DurationKind
+	default:
+		return 0, 0, fmt.Errorf("type %T is not a number", v)
+	}
+	if math.IsNaN(f) {
+		return 0, kind, errors.New("value is NaN")
+	}
+	return f, kind, nil
+}
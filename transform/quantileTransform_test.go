@@ -0,0 +1,228 @@
+package transform
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/struckoff/sfcframework/curve"
+)
+
+func TestFitQuantileModel(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples [][]interface{}
+		buckets int
+		want    [][]float64
+		wantErr bool
+	}{
+		{"uniform", [][]interface{}{{0}, {1}, {2}, {3}, {4}}, 4, [][]float64{{0, 1, 2, 3, 4}}, false},
+		{"interpolated", [][]interface{}{{0.0}, {10.0}}, 4, [][]float64{{0, 2.5, 5, 7.5, 10}}, false},
+		{"kinds", [][]interface{}{{uint8(1), time.Second}, {int64(3), 3 * time.Second}}, 2, [][]float64{{1, 2, 3}, {1e9, 2e9, 3e9}}, false},
+		{"single sample", [][]interface{}{{float32(5)}}, 2, [][]float64{{5, 5, 5}}, false},
+		{"no samples", nil, 4, nil, true},
+		{"no values", [][]interface{}{{}}, 4, nil, true},
+		{"no buckets", [][]interface{}{{1}}, 0, nil, true},
+		{"different lengths", [][]interface{}{{1, 2}, {1}}, 4, nil, true},
+		{"not number", [][]interface{}{{"1"}}, 4, nil, true},
+		{"nan", [][]interface{}{{math.NaN()}}, 4, nil, true},
+		{"infinity", [][]interface{}{{math.Inf(-1)}}, 4, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FitQuantileModel(tt.samples, tt.buckets)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.Boundaries)
+		})
+	}
+}
+
+func TestQuantileModel_Transform(t *testing.T) {
+	m := &QuantileModel{Boundaries: [][]float64{{0, 1, 2, 2, 2, 10, 100, 1000, 10000}}}
+	sfc, err := curve.NewCurve(curve.Morton, 1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    uint64
+		wantErr bool
+	}{
+		{"below", -5, 0, false},
+		{"minimum", 0, 0, false},
+		{"interpolated", 1.5, 1, false},
+		{"frequent value", 2, 3, false},
+		{"interpolated after frequent", 55.0, 5, false},
+		{"maximum", 10000, 7, false},
+		{"above", uint64(1 << 40), 7, false},
+		{"not number", "5", 0, true},
+		{"nan", math.NaN(), 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.Transform([]interface{}{tt.value}, sfc)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []uint64{tt.want}, got)
+		})
+	}
+
+	_, err = m.Transform([]interface{}{1, 2}, sfc)
+	assert.Error(t, err)
+	flat, err := curve.NewCurve(curve.Morton, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = m.Transform([]interface{}{1, 2}, flat)
+	assert.Error(t, err)
+}
+
+//skewedSample returns values of two independent skewed dimensions:
+//log-normal values and Zipfian IDs.
+func skewedSample(rnd *rand.Rand, zipf *rand.Zipf) []interface{} {
+	return []interface{}{math.Exp(rnd.NormFloat64() * 2), zipf.Uint64()}
+}
+
+func TestQuantileModel_Uniform(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	zipf := rand.NewZipf(rnd, 1.2, 1, 1<<30)
+	samples := make([][]interface{}, 50000)
+	for i := range samples {
+		samples[i] = skewedSample(rnd, zipf)
+	}
+	m, err := FitQuantileModel(samples, 512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sfc, err := curve.NewCurve(curve.Hilbert, 2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	linear, err := NewRangeTransform(2, []DimSpec{
+		{Kind: FloatKind, Min: 0, Max: 1000},
+		{Kind: UintKind, Min: 0, Max: 1 << 30},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 1 << 15
+	dims := [2][]int{make([]int, 16), make([]int, 16)}
+	linearMax := 0
+	linearCells := make([]int, 16)
+	for i := 0; i < n; i++ {
+		values := skewedSample(rnd, zipf)
+		coords, err := m.Transform(values, sfc)
+		if !assert.NoError(t, err) {
+			return
+		}
+		dims[0][coords[0]]++
+		dims[1][coords[1]]++
+		coords, err = linear(values, sfc)
+		if !assert.NoError(t, err) {
+			return
+		}
+		linearCells[coords[0]]++
+		if linearCells[coords[0]] > linearMax {
+			linearMax = linearCells[coords[0]]
+		}
+	}
+	//continuous values are uniform
+	chi := chiSquare(dims[0], n)
+	assert.True(t, chi < chiSquareLimit(16), "chi-square == %.1f", chi)
+	//frequent IDs can not be split, but they do not pile into a few cells
+	for c, count := range dims[1] {
+		assert.True(t, count < n/4, "cell %v has %v of %v values", c, count, n)
+	}
+	assert.True(t, linearMax > n*3/4, "linear scaling piles %v of %v values into a cell", linearMax, n)
+}
+
+func TestQuantileModel_JSON(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	zipf := rand.NewZipf(rnd, 1.2, 1, 1<<30)
+	samples := make([][]interface{}, 1000)
+	for i := range samples {
+		samples[i] = skewedSample(rnd, zipf)
+	}
+	m, err := FitQuantileModel(samples, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(m)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var loaded QuantileModel
+	if !assert.NoError(t, json.Unmarshal(data, &loaded)) {
+		return
+	}
+	assert.Equal(t, m.Boundaries, loaded.Boundaries)
+
+	sfc, err := curve.NewCurve(curve.Morton, 2, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		values := skewedSample(rnd, zipf)
+		want, err := m.Transform(values, sfc)
+		assert.NoError(t, err)
+		got, err := loaded.Transform(values, sfc)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	for _, data := range []string{
+		`{"boundaries": []}`,
+		`{"boundaries": [[1]]}`,
+		`{"boundaries": [[1, 3, 2]]}`,
+		`{"boundaries": "1, 2"}`,
+	} {
+		var m QuantileModel
+		assert.Error(t, json.Unmarshal([]byte(data), &m), data)
+	}
+}
+
+func TestQuantileModel_Quarters(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	sample := func() []interface{} {
+		return []interface{}{math.Exp(rnd.NormFloat64() * 2), math.Exp(rnd.NormFloat64())}
+	}
+	samples := make([][]interface{}, 20000)
+	for i := range samples {
+		samples[i] = sample()
+	}
+	m, err := FitQuantileModel(samples, 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sfc, err := curve.NewCurve(curve.Hilbert, 2, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 4000
+	quarters := make([]int, 4)
+	for i := 0; i < n; i++ {
+		coords, err := m.Transform(sample(), sfc)
+		if !assert.NoError(t, err) {
+			return
+		}
+		code, err := sfc.Encode(coords)
+		assert.NoError(t, err)
+		quarters[code/((sfc.Length()+1)/4)]++
+	}
+	//independent dimensions are uniform over cells, so each quarter of the curve(range of the node) gets a quarter of data
+	for i, q := range quarters {
+		assert.InDelta(t, n/4, q, n/20, "quarter %v", i)
+	}
+}
@@ -0,0 +1,158 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	balancer "github.com/struckoff/sfcframework"
+	"github.com/struckoff/sfcframework/curve"
+)
+
+//ValueKind - the type of values of the dimension.
+type ValueKind int
+
+const (
+	IntKind      ValueKind = iota //int, int8, int16, int32, int64
+	UintKind                      //uint, uint8, uint16, uint32, uint64
+	FloatKind                     //float32, float64
+	DurationKind                  //time.Duration, bounds are in nanoseconds
+)
+
+//Scale - the function which maps values between bounds onto cells of the dimension.
+type Scale int
+
+const (
+	LinearScale Scale = iota //cells have equal width
+	LogScale                 //width of cells grows exponentially from the minimum, log(1 + value - min)
+	SqrtScale                //width of cells grows linearly from the minimum, sqrt(value - min)
+)
+
+//RangePolicy - the way values out of bounds are handled.
+type RangePolicy int
+
+const (
+	ClampPolicy RangePolicy = iota //values are limited by bounds
+	WrapPolicy                     //values are wrapped with the period max - min, the maximum is equal to the minimum
+	ErrorPolicy                    //values out of bounds are an error
+)
+
+//DimSpec - the description of values of the dimension.
+type DimSpec struct {
+	Kind   ValueKind
+	Min    float64
+	Max    float64
+	Scale  Scale
+	Policy RangePolicy
+}
+
+//NewRangeTransform returns a transform function for numeric values of the curve with given amount of dimensions.
+//Each value is handled by the policy of the spec of its dimension and scaled onto range [0, DimensionSize].
+//Values are converted to float64, so integers beyond 2^53 lose precision.
+func NewRangeTransform(dimensions uint64, dims []DimSpec) (balancer.TransformFunc, error) {
+	if len(dims) == 0 {
+		return nil, errors.New("at least one dimension is required")
+	}
+	if uint64(len(dims)) != dimensions {
+		return nil, fmt.Errorf("number of specs == %v differs from dimensions == %v", len(dims), dimensions)
+	}
+	for i, d := range dims {
+		if err := d.validate(); err != nil {
+			return nil, fmt.Errorf("dimension %v: %w", i, err)
+		}
+	}
+	dims = append([]DimSpec{}, dims...)
+	return func(values []interface{}, sfc curve.Curve) ([]uint64, error) {
+		if sfc.Dimensions() != uint64(len(dims)) {
+			return nil, fmt.Errorf("number of dimensions == %v differs from number of specs == %v", sfc.Dimensions(), len(dims))
+		}
+		if len(values) != len(dims) {
+			return nil, fmt.Errorf("number of values must be %v", len(dims))
+		}
+		sizes := curve.DimensionSizes(sfc)
+		res := make([]uint64, len(dims))
+		for i, d := range dims {
+			v, err := d.value(values[i])
+			if err != nil {
+				return nil, fmt.Errorf("value %v: %w", i, err)
+			}
+			pos, err := d.position(v)
+			if err != nil {
+				return nil, fmt.Errorf("value %v: %w", i, err)
+			}
+			res[i] = cell(pos, sizes[i])
+		}
+		return res, nil
+	}, nil
+}
+
+func (d DimSpec) validate() error {
+	if d.Kind < IntKind || d.Kind > DurationKind {
+		return fmt.Errorf("unknown value kind %v", d.Kind)
+	}
+	if d.Scale < LinearScale || d.Scale > SqrtScale {
+		return fmt.Errorf("unknown scale %v", d.Scale)
+	}
+	if d.Policy < ClampPolicy || d.Policy > ErrorPolicy {
+		return fmt.Errorf("unknown policy %v", d.Policy)
+	}
+	if math.IsNaN(d.Min) || math.IsNaN(d.Max) || math.IsInf(d.Max-d.Min, 0) {
+		return errors.New("bounds must be finite")
+	}
+	if d.Min >= d.Max {
+		return fmt.Errorf("minimum == %v must be less than maximum == %v", d.Min, d.Max)
+	}
+	if d.Kind == UintKind && d.Min < 0 {
+		return fmt.Errorf("minimum == %v of unsigned values must not be negative", d.Min)
+	}
+	return nil
+}
+
+//value converts the value of the kind of the dimension to float64.
+func (d DimSpec) value(v interface{}) (float64, error) {
+	f, kind, err := toFloat(v)
+	if err != nil {
+		return 0, err
+	}
+	if kind != d.Kind {
+		return 0, fmt.Errorf("type %T does not match kind of the dimension", v)
+	}
+	return f, nil
+}
+
+//position handles the value by the policy and returns its scaled position in range [0, 1].
+func (d DimSpec) position(v float64) (float64, error) {
+	switch d.Policy {
+	case ClampPolicy:
+		v = math.Max(d.Min, math.Min(d.Max, v))
+	case WrapPolicy:
+		if math.IsInf(v, 0) {
+			return 0, errors.New("infinite value can not be wrapped")
+		}
+		v = d.Min + math.Mod(v-d.Min, d.Max-d.Min)
+		if v < d.Min {
+			v += d.Max - d.Min
+		}
+	case ErrorPolicy:
+		if v < d.Min || v > d.Max {
+			return 0, fmt.Errorf("value == %v is out of bounds [%v, %v]", v, d.Min, d.Max)
+		}
+	}
+	switch d.Scale {
+	case LogScale:
+		return math.Log1p(v-d.Min) / math.Log1p(d.Max-d.Min), nil
+	case SqrtScale:
+		return math.Sqrt(v-d.Min) / math.Sqrt(d.Max-d.Min), nil
+	default:
+		return (v - d.Min) / (d.Max - d.Min), nil
+	}
+}
+
+//cell maps the position in range [0, 1] onto range [0, size] of the dimension, cells have equal width.
+func cell(pos float64, size uint64) uint64 {
+	c := math.Floor(pos * (float64(size) + 1))
+	if c >= float64(size) {
+		return size
+	}
+	return uint64(c)
+}
@@ -0,0 +1,194 @@
+package transform
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	balancer "github.com/struckoff/sfcframework"
+	"github.com/struckoff/sfcframework/curve"
+	"github.com/struckoff/sfcframework/mocks"
+	"github.com/struckoff/sfcframework/node"
+)
+
+func TestNewRangeTransform(t *testing.T) {
+	tests := []struct {
+		name    string
+		dims    []DimSpec
+		wantErr bool
+	}{
+		{"linear", []DimSpec{{Kind: IntKind, Min: -10, Max: 10}}, false},
+		{"all options", []DimSpec{
+			{Kind: UintKind, Min: 0, Max: 100, Scale: LogScale, Policy: WrapPolicy},
+			{Kind: DurationKind, Min: 0, Max: float64(time.Hour), Scale: SqrtScale, Policy: ErrorPolicy},
+		}, false},
+		{"no dimensions", nil, true},
+		{"unknown kind", []DimSpec{{Kind: ValueKind(42), Min: 0, Max: 1}}, true},
+		{"unknown scale", []DimSpec{{Kind: IntKind, Min: 0, Max: 1, Scale: Scale(-1)}}, true},
+		{"unknown policy", []DimSpec{{Kind: IntKind, Min: 0, Max: 1, Policy: RangePolicy(3)}}, true},
+		{"empty bounds", []DimSpec{{Kind: FloatKind, Min: 1, Max: 1}}, true},
+		{"inverted bounds", []DimSpec{{Kind: FloatKind, Min: 1, Max: 0}}, true},
+		{"nan bounds", []DimSpec{{Kind: FloatKind, Min: math.NaN(), Max: 1}}, true},
+		{"infinite bounds", []DimSpec{{Kind: FloatKind, Min: 0, Max: math.Inf(1)}}, true},
+		{"negative unsigned", []DimSpec{{Kind: UintKind, Min: -1, Max: 1}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRangeTransform(uint64(len(tt.dims)), tt.dims)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, got)
+		})
+	}
+
+	got, err := NewRangeTransform(3, []DimSpec{{Kind: IntKind, Min: 0, Max: 1}, {Kind: IntKind, Min: 0, Max: 1}})
+	assert.Error(t, err)
+	assert.Nil(t, got)
+}
+
+func TestRangeTransform(t *testing.T) {
+	sfc, err := curve.NewCurve(curve.Morton, 1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		spec    DimSpec
+		value   interface{}
+		want    uint64
+		wantErr bool
+	}{
+		{"int minimum", DimSpec{Kind: IntKind, Min: -8, Max: 8}, -8, 0, false},
+		{"int middle", DimSpec{Kind: IntKind, Min: -8, Max: 8}, int8(0), 4, false},
+		{"int maximum", DimSpec{Kind: IntKind, Min: -8, Max: 8}, int64(8), 7, false},
+		{"int not int", DimSpec{Kind: IntKind, Min: -8, Max: 8}, 1.0, 0, true},
+		{"uint", DimSpec{Kind: UintKind, Min: 0, Max: 800}, uint16(350), 3, false},
+		{"uint not uint", DimSpec{Kind: UintKind, Min: 0, Max: 800}, 350, 0, true},
+		{"float", DimSpec{Kind: FloatKind, Min: 0, Max: 1}, float32(0.3), 2, false},
+		{"float nan", DimSpec{Kind: FloatKind, Min: 0, Max: 1, Policy: ClampPolicy}, math.NaN(), 0, true},
+		{"duration", DimSpec{Kind: DurationKind, Min: 0, Max: float64(8 * time.Second)}, 5 * time.Second, 5, false},
+		{"duration not duration", DimSpec{Kind: DurationKind, Min: 0, Max: 8}, int64(5), 0, true},
+		{"clamp below", DimSpec{Kind: IntKind, Min: 0, Max: 8}, -100, 0, false},
+		{"clamp above", DimSpec{Kind: IntKind, Min: 0, Max: 8}, 100, 7, false},
+		{"wrap above", DimSpec{Kind: FloatKind, Min: 0, Max: 360, Policy: WrapPolicy}, 405.0, 1, false},
+		{"wrap below", DimSpec{Kind: FloatKind, Min: 0, Max: 360, Policy: WrapPolicy}, -45.0, 7, false},
+		{"wrap maximum", DimSpec{Kind: FloatKind, Min: 0, Max: 360, Policy: WrapPolicy}, 360.0, 0, false},
+		{"wrap infinity", DimSpec{Kind: FloatKind, Min: 0, Max: 360, Policy: WrapPolicy}, math.Inf(1), 0, true},
+		{"error inside", DimSpec{Kind: IntKind, Min: 0, Max: 8, Policy: ErrorPolicy}, 8, 7, false},
+		{"error below", DimSpec{Kind: IntKind, Min: 0, Max: 8, Policy: ErrorPolicy}, -1, 0, true},
+		{"error above", DimSpec{Kind: IntKind, Min: 0, Max: 8, Policy: ErrorPolicy}, 9, 0, true},
+		{"log", DimSpec{Kind: FloatKind, Min: 0, Max: 255, Scale: LogScale}, 15.0, 4, false},
+		{"log shifted", DimSpec{Kind: FloatKind, Min: 100, Max: 355, Scale: LogScale}, 115.0, 4, false},
+		{"sqrt", DimSpec{Kind: FloatKind, Min: 0, Max: 64, Scale: SqrtScale}, 16.0, 4, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := NewRangeTransform(1, []DimSpec{tt.spec})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := tf([]interface{}{tt.value}, sfc)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []uint64{tt.want}, got)
+		})
+	}
+}
+
+func TestRangeTransform_Dimensions(t *testing.T) {
+	tf, err := NewRangeTransform(2, []DimSpec{
+		{Kind: IntKind, Min: 0, Max: 100},
+		{Kind: DurationKind, Min: 0, Max: float64(time.Minute), Scale: LogScale},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	flat, err := curve.NewUnevenCurve(curve.Hilbert, []uint64{4, 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := tf([]interface{}{100, time.Minute}, flat)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{15, 255}, got)
+
+	_, err = tf([]interface{}{100}, flat)
+	assert.Error(t, err)
+
+	cube, err := curve.NewCurve(curve.Hilbert, 3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = tf([]interface{}{100, time.Minute, 1}, cube)
+	assert.Error(t, err)
+}
+
+func TestRangeTransform_Monotonic(t *testing.T) {
+	sfc, err := curve.NewCurve(curve.Morton, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, scale := range []Scale{LinearScale, LogScale, SqrtScale} {
+		t.Run(fmt.Sprint(scale), func(t *testing.T) {
+			tf, err := NewRangeTransform(1, []DimSpec{{Kind: FloatKind, Min: -50, Max: 1e6, Scale: scale}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			prev := uint64(0)
+			cells := map[uint64]bool{}
+			for v := -50.0; v <= 1e6; v = v*1.01 + 1 {
+				got, err := tf([]interface{}{v}, sfc)
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.True(t, got[0] >= prev, "value %v", v)
+				prev = got[0]
+				cells[got[0]] = true
+			}
+			last, err := tf([]interface{}{1e6}, sfc)
+			assert.NoError(t, err)
+			assert.Equal(t, sfc.DimensionSize(), last[0])
+			if scale == LogScale {
+				//log scale spreads exponentially growing values over many cells
+				assert.True(t, len(cells) > 500, "%v cells", len(cells))
+			}
+		})
+	}
+}
+
+func TestRangeTransform_Balancer(t *testing.T) {
+	tf, err := NewRangeTransform(2, []DimSpec{
+		{Kind: UintKind, Min: 0, Max: 1 << 20, Scale: LogScale},
+		{Kind: DurationKind, Min: 0, Max: float64(time.Hour), Policy: ErrorPolicy},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := &mocks.Node{}
+	n.On("ID").Return("node-0")
+	b, err := balancer.NewBalancer(curve.Hilbert, 2, 256, tf, nil, []node.Node{n})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &mocks.DataItem{}
+	d.On("ID").Return("request")
+	d.On("Values").Return([]interface{}{uint64(4096), 90 * time.Second})
+	got, _, err := b.LocateData(d)
+	assert.NoError(t, err)
+	assert.Equal(t, "node-0", got.ID())
+
+	late := &mocks.DataItem{}
+	late.On("ID").Return("late")
+	late.On("Values").Return([]interface{}{uint64(4096), 2 * time.Hour})
+	_, _, err = b.LocateData(late)
+	assert.Error(t, err)
+}
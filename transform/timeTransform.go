@@ -0,0 +1,292 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"time"
+
+	balancer "github.com/struckoff/sfcframework"
+	"github.com/struckoff/sfcframework/curve"
+)
+
+//TimeAxis - the time dimension of the curve split into buckets of equal duration.
+//
+//The window of the axis is Buckets buckets from Start, the zero Start is the Unix epoch.
+//Buckets are spread over cells of the dimension evenly, neighbour buckets share the cell if there are more buckets than cells.
+//
+//In the rolling mode the axis wraps every Buckets buckets(epoch), so time is not limited by the window:
+//data of the current epoch clusters on the curve, and cells of the oldest buckets are reused by the next epoch,
+//so they could be dropped by range(see Boxes) before the axis comes back to them.
+//Otherwise time out of the window is an error.
+type TimeAxis struct {
+	Start   time.Time
+	Bucket  time.Duration
+	Buckets uint64
+	Rolling bool
+}
+
+//NewTimeTransform returns a transform function where the dimension 0 is the time axis
+//and other dimensions are transformed by rest.
+//The first value is time.Time or int64 Unix nanoseconds, other values are passed to rest.
+//rest gets a view of the curve without the time dimension, which provides only dimensions and their sizes.
+//If rest is nil, the curve must have a single dimension.
+func NewTimeTransform(axis TimeAxis, rest balancer.TransformFunc) (balancer.TransformFunc, error) {
+	if err := axis.validate(); err != nil {
+		return nil, err
+	}
+	return func(values []interface{}, sfc curve.Curve) ([]uint64, error) {
+		if len(values) == 0 {
+			return nil, errors.New("time value is required")
+		}
+		if rest == nil && (len(values) != 1 || sfc.Dimensions() != 1) {
+			return nil, errors.New("number of values and dimensions must be 1")
+		}
+		sizes := curve.DimensionSizes(sfc)
+		t, err := axis.Coord(values[0], sizes[0])
+		if err != nil {
+			return nil, err
+		}
+		if rest == nil {
+			return []uint64{t}, nil
+		}
+		coords, err := rest(values[1:], &projection{sizes: sizes[1:]})
+		if err != nil {
+			return nil, err
+		}
+		if len(coords) != len(sizes)-1 {
+			return nil, fmt.Errorf("rest transform returned %v coordinates, %v expected", len(coords), len(sizes)-1)
+		}
+		return append([]uint64{t}, coords...), nil
+	}, nil
+}
+
+//Index returns the index of the bucket of the time value from the start of the axis, it may be out of the window.
+func (a TimeAxis) Index(v interface{}) (int64, error) {
+	var t time.Time
+	switch x := v.(type) {
+	case time.Time:
+		t = x
+	case int64:
+		t = time.Unix(0, x)
+	default:
+		return 0, errors.New("value must be time.Time or int64 Unix nanoseconds")
+	}
+	if err := a.validate(); err != nil {
+		return 0, err
+	}
+	start := a.start()
+	d := t.Sub(start)
+	if d != math.MinInt64 && d != math.MaxInt64 {
+		idx := int64(d / a.Bucket)
+		if d%a.Bucket < 0 {
+			idx--
+		}
+		return idx, nil
+	}
+	//Sub saturates about 292 years away from the start, so the distance is counted in seconds and nanoseconds separately
+	n := new(big.Int).Sub(big.NewInt(t.Unix()), big.NewInt(start.Unix()))
+	n.Mul(n, big.NewInt(int64(time.Second)))
+	n.Add(n, big.NewInt(int64(t.Nanosecond()-start.Nanosecond())))
+	//the bucket is positive, so the Euclidean division is the floor division
+	idx, _ := n.DivMod(n, big.NewInt(int64(a.Bucket)), new(big.Int))
+	if !idx.IsInt64() {
+		return 0, fmt.Errorf("index of the bucket of %v is out of range of int64", t)
+	}
+	return idx.Int64(), nil
+}
+
+//Epoch returns the number of complete windows between the start of the axis and the time value.
+func (a TimeAxis) Epoch(v interface{}) (int64, error) {
+	idx, err := a.Index(v)
+	if err != nil {
+		return 0, err
+	}
+	return floorDiv(idx, a.Buckets), nil
+}
+
+//Coord returns the coordinate of the time value on the dimension with size as the maximum coordinate value.
+func (a TimeAxis) Coord(v interface{}, size uint64) (uint64, error) {
+	idx, err := a.Index(v)
+	if err != nil {
+		return 0, err
+	}
+	b, err := a.bucket(idx)
+	if err != nil {
+		return 0, err
+	}
+	return a.scale(b, size), nil
+}
+
+//Boxes returns boxes of cells of the curve where data with time in range [from, to) is located,
+//the time axis is limited and other dimensions are not.
+//In the rolling mode the range wraps around the end of the axis and ranges longer than the window cover the whole axis.
+//Boxes may contain cells of other buckets if there are more buckets than cells.
+func (a TimeAxis) Boxes(from, to time.Time, sfc curve.Curve) ([]curve.Box, error) {
+	if !from.Before(to) {
+		return nil, errors.New("from must be before to")
+	}
+	first, err := a.Index(from)
+	if err != nil {
+		return nil, err
+	}
+	last, err := a.Index(to.Add(-1))
+	if err != nil {
+		return nil, err
+	}
+	sizes := curve.DimensionSizes(sfc)
+	box := func(minBucket, maxBucket uint64) curve.Box {
+		b := curve.Box{Min: make([]uint64, len(sizes)), Max: append([]uint64{}, sizes...)}
+		b.Min[0] = a.scale(minBucket, sizes[0])
+		b.Max[0] = a.scale(maxBucket, sizes[0])
+		return b
+	}
+	if !a.Rolling {
+		if last < 0 || first >= int64(a.Buckets) {
+			return nil, nil
+		}
+		if first < 0 {
+			first = 0
+		}
+		if last >= int64(a.Buckets) {
+			last = int64(a.Buckets) - 1
+		}
+		return []curve.Box{box(uint64(first), uint64(last))}, nil
+	}
+	if uint64(last-first) >= a.Buckets-1 {
+		return []curve.Box{box(0, a.Buckets-1)}, nil
+	}
+	min, _ := a.bucket(first)
+	max, _ := a.bucket(last)
+	if min <= max {
+		return []curve.Box{box(min, max)}, nil
+	}
+	return []curve.Box{box(0, max), box(min, a.Buckets-1)}, nil
+}
+
+//CellGroups returns cell groups of the space which contain data with time in range [from, to)(see Boxes).
+func (a TimeAxis) CellGroups(s *balancer.Space, from, to time.Time) ([]*balancer.CellGroup, error) {
+	boxes, err := a.Boxes(from, to, s.SFC())
+	if err != nil {
+		return nil, err
+	}
+	seen := map[*balancer.CellGroup]bool{}
+	var res []*balancer.CellGroup
+	for _, b := range boxes {
+		cgs, err := s.BoxCellGroups(b.Min, b.Max)
+		if err != nil {
+			return nil, err
+		}
+		for _, cg := range cgs {
+			if !seen[cg] {
+				seen[cg] = true
+				res = append(res, cg)
+			}
+		}
+	}
+	return res, nil
+}
+
+func (a TimeAxis) validate() error {
+	if a.Bucket <= 0 {
+		return fmt.Errorf("bucket == %v must be positive", a.Bucket)
+	}
+	if a.Buckets == 0 || a.Buckets > math.MaxInt64 {
+		return fmt.Errorf("amount of buckets == %v must be in range [1, %v]", a.Buckets, int64(math.MaxInt64))
+	}
+	return nil
+}
+
+func (a TimeAxis) start() time.Time {
+	if a.Start.IsZero() {
+		return time.Unix(0, 0)
+	}
+	return a.Start
+}
+
+//bucket returns the bucket of the axis by the index from the start.
+func (a TimeAxis) bucket(idx int64) (uint64, error) {
+	if a.Rolling {
+		return uint64(idx - floorDiv(idx, a.Buckets)*int64(a.Buckets)), nil
+	}
+	if idx < 0 || uint64(idx) >= a.Buckets {
+		return 0, fmt.Errorf("bucket %v is out of the window of %v buckets", idx, a.Buckets)
+	}
+	return uint64(idx), nil
+}
+
+//scale maps the bucket onto range [0, size] evenly.
+func (a TimeAxis) scale(b, size uint64) uint64 {
+	//b * (size + 1) / Buckets, the product is 128-bit
+	hi, lo := bits.Mul64(b, size+1)
+	if size+1 == 0 {
+		hi, lo = b, 0
+	}
+	res, _ := bits.Div64(hi, lo, a.Buckets)
+	return res
+}
+
+func floorDiv(a int64, b uint64) int64 {
+	q := a / int64(b)
+	if a%int64(b) < 0 {
+		q--
+	}
+	return q
+}
+
+//projection - the view of dimensions of the curve except the time axis, it is passed to transforms of other dimensions.
+//It provides only dimensions and their sizes, codes could not be encoded or decoded.
+type projection struct {
+	sizes []uint64
+}
+
+func (p *projection) Decode(code uint64) ([]uint64, error) {
+	return nil, errors.New("projection of the curve does not decode codes")
+}
+
+func (p *projection) DecodeWithBuffer(buf []uint64, code uint64) ([]uint64, error) {
+	return p.Decode(code)
+}
+
+func (p *projection) Encode(coords []uint64) (uint64, error) {
+	return 0, errors.New("projection of the curve does not encode coordinates")
+}
+
+func (p *projection) DimensionSize() uint64 {
+	if len(p.sizes) == 0 {
+		return 0
+	}
+	min := p.sizes[0]
+	for _, s := range p.sizes[1:] {
+		if s < min {
+			min = s
+		}
+	}
+	return min
+}
+
+func (p *projection) DimensionSizes() []uint64 {
+	return append([]uint64{}, p.sizes...)
+}
+
+func (p *projection) Length() uint64 {
+	res := uint64(1)
+	for _, s := range p.sizes {
+		hi, lo := bits.Mul64(res, s+1)
+		if hi != 0 || s+1 == 0 {
+			return math.MaxUint64
+		}
+		res = lo
+	}
+	return res - 1
+}
+
+func (p *projection) Dimensions() uint64 {
+	return uint64(len(p.sizes))
+}
+
+func (p *projection) Bits() uint64 {
+	return uint64(bits.Len64(p.DimensionSize()))
+}
@@ -0,0 +1,259 @@
+package transform
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	balancer "github.com/struckoff/sfcframework"
+	"github.com/struckoff/sfcframework/curve"
+	"github.com/struckoff/sfcframework/mocks"
+	"github.com/struckoff/sfcframework/node"
+)
+
+var testStart = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestNewTimeTransform(t *testing.T) {
+	tests := []struct {
+		name    string
+		axis    TimeAxis
+		wantErr bool
+	}{
+		{"window", TimeAxis{Start: testStart, Bucket: time.Minute, Buckets: 60}, false},
+		{"rolling", TimeAxis{Bucket: time.Hour, Buckets: 24, Rolling: true}, false},
+		{"zero bucket", TimeAxis{Start: testStart, Buckets: 60}, true},
+		{"negative bucket", TimeAxis{Start: testStart, Bucket: -time.Minute, Buckets: 60}, true},
+		{"no buckets", TimeAxis{Start: testStart, Bucket: time.Minute}, true},
+		{"too many buckets", TimeAxis{Start: testStart, Bucket: time.Minute, Buckets: 1 << 63}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewTimeTransform(tt.axis, nil)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, got)
+		})
+	}
+}
+
+func TestTimeTransform(t *testing.T) {
+	window := TimeAxis{Start: testStart, Bucket: time.Minute, Buckets: 60}
+	rolling := TimeAxis{Start: testStart, Bucket: time.Minute, Buckets: 60, Rolling: true}
+	tests := []struct {
+		name    string
+		axis    TimeAxis
+		bits    uint64
+		value   interface{}
+		want    uint64
+		wantErr bool
+	}{
+		{"start", window, 6, testStart, 0, false},
+		{"bucket", window, 6, testStart.Add(17*time.Minute + 59*time.Second), 17 * 64 / 60, false},
+		{"last bucket", window, 6, testStart.Add(time.Hour - 1), 59 * 64 / 60, false},
+		{"nanoseconds", window, 6, testStart.Add(17 * time.Minute).UnixNano(), 17 * 64 / 60, false},
+		{"buckets share cells", window, 4, testStart.Add(59 * time.Minute), 15, false},
+		{"buckets spread over cells", window, 8, testStart.Add(30 * time.Minute), 128, false},
+		{"before window", window, 6, testStart.Add(-time.Second), 0, true},
+		{"after window", window, 6, testStart.Add(time.Hour), 0, true},
+		{"not time", window, 6, "2020-01-01", 0, true},
+		{"rolling next epoch", rolling, 6, testStart.Add(time.Hour + 17*time.Minute), 17 * 64 / 60, false},
+		{"rolling previous epoch", rolling, 6, testStart.Add(-time.Minute), 59 * 64 / 60, false},
+		{"unix epoch", TimeAxis{Bucket: 24 * time.Hour, Buckets: 1 << 20}, 20, testStart, 18262, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sfc, err := curve.NewCurve(curve.Morton, 1, tt.bits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tf, err := NewTimeTransform(tt.axis, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := tf([]interface{}{tt.value}, sfc)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []uint64{tt.want}, got)
+		})
+	}
+}
+
+func TestTimeAxis_Epoch(t *testing.T) {
+	axis := TimeAxis{Start: testStart, Bucket: time.Minute, Buckets: 60, Rolling: true}
+	for _, tt := range []struct {
+		t    time.Time
+		want int64
+	}{
+		{testStart, 0},
+		{testStart.Add(time.Hour - 1), 0},
+		{testStart.Add(time.Hour), 1},
+		{testStart.Add(-1), -1},
+		{testStart.Add(-time.Hour), -1},
+		{testStart.Add(-time.Hour - 1), -2},
+	} {
+		got, err := axis.Epoch(tt.t)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got, tt.t)
+	}
+}
+
+func TestTimeAxis_IndexFarTime(t *testing.T) {
+	axis := TimeAxis{Bucket: time.Hour, Buckets: 24, Rolling: true}
+	for _, tt := range []time.Time{
+		time.Date(2270, 1, 1, 0, 30, 0, 0, time.UTC),
+		time.Date(2370, 1, 1, 0, 30, 0, 0, time.UTC),
+		time.Date(2370, 1, 1, 1, 30, 0, 0, time.UTC),
+		time.Date(1600, 1, 1, 0, 30, 0, 1, time.UTC),
+		time.Date(1600, 1, 1, 23, 59, 59, 999999999, time.UTC),
+	} {
+		got, err := axis.Index(tt)
+		assert.NoError(t, err)
+		//Unix seconds are floored, so the floor division of seconds by the bucket is the index
+		want := floorDiv(tt.Unix(), 3600)
+		assert.Equal(t, want, got, tt)
+		coord, err := axis.Coord(tt, 23)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(tt.Hour()), coord, tt)
+	}
+	_, err := TimeAxis{Bucket: 1, Buckets: 24, Rolling: true}.Index(time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.Error(t, err)
+	_, err = TimeAxis{Start: testStart, Bucket: time.Hour, Buckets: 24}.Coord(time.Date(2370, 1, 1, 0, 0, 0, 0, time.UTC), 23)
+	assert.Error(t, err)
+}
+
+func TestTimeTransform_Rest(t *testing.T) {
+	axis := TimeAxis{Start: testStart, Bucket: time.Hour, Buckets: 24, Rolling: true}
+	tf, err := NewTimeTransform(axis, HashTransform(Mix, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sfc, err := curve.NewUnevenCurve(curve.Hilbert, []uint64{5, 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := curve.NewCurve(curve.Morton, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := tf([]interface{}{testStart.Add(5 * time.Hour), "sensor-42"}, sfc)
+	assert.NoError(t, err)
+	want, err := HashTransform(Mix, 0)([]interface{}{"sensor-42"}, key)
+	assert.NoError(t, err)
+	assert.Equal(t, append([]uint64{5 * 32 / 24}, want...), got)
+
+	_, err = tf([]interface{}{testStart, 42}, sfc)
+	assert.Error(t, err)
+	_, err = tf([]interface{}{}, sfc)
+	assert.Error(t, err)
+
+	//the time transform without rest requires a single dimension
+	single, err := NewTimeTransform(axis, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = single([]interface{}{testStart, "sensor-42"}, sfc)
+	assert.Error(t, err)
+
+	//projection of the curve provides only dimensions and sizes
+	p := &projection{sizes: []uint64{15, 1023}}
+	assert.Equal(t, uint64(2), p.Dimensions())
+	assert.Equal(t, uint64(15), p.DimensionSize())
+	assert.Equal(t, uint64(1<<14-1), p.Length())
+	assert.Equal(t, uint64(4), p.Bits())
+	_, err = p.Encode([]uint64{1, 2})
+	assert.Error(t, err)
+	_, err = p.Decode(1)
+	assert.Error(t, err)
+}
+
+func TestTimeAxis_Boxes(t *testing.T) {
+	sfc, err := curve.NewUnevenCurve(curve.Morton, []uint64{4, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	window := TimeAxis{Start: testStart, Bucket: time.Hour, Buckets: 16}
+	rolling := window
+	rolling.Rolling = true
+	hour := func(h int) time.Time {
+		return testStart.Add(time.Duration(h) * time.Hour)
+	}
+	box := func(min, max uint64) curve.Box {
+		return curve.Box{Min: []uint64{min, 0}, Max: []uint64{max, 7}}
+	}
+	tests := []struct {
+		name     string
+		axis     TimeAxis
+		from, to time.Time
+		want     []curve.Box
+		wantErr  bool
+	}{
+		{"window", window, hour(2), hour(5), []curve.Box{box(2, 4)}, false},
+		{"unaligned", window, hour(2).Add(time.Minute), hour(5).Add(time.Minute), []curve.Box{box(2, 5)}, false},
+		{"clipped", window, hour(-3), hour(3), []curve.Box{box(0, 2)}, false},
+		{"out of window", window, hour(16), hour(20), nil, false},
+		{"rolling", rolling, hour(18), hour(21), []curve.Box{box(2, 4)}, false},
+		{"rolling wraps", rolling, hour(14), hour(18), []curve.Box{box(0, 1), box(14, 15)}, false},
+		{"rolling whole axis", rolling, hour(3), hour(19), []curve.Box{box(0, 15)}, false},
+		{"empty", window, hour(3), hour(3), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.axis.Boxes(tt.from, tt.to, sfc)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTimeAxis_CellGroups(t *testing.T) {
+	ns := make([]node.Node, 4)
+	for i := range ns {
+		n := &mocks.Node{}
+		n.On("ID").Return(fmt.Sprintf("node-%d", i))
+		ns[i] = n
+	}
+	axis := TimeAxis{Start: testStart, Bucket: time.Hour, Buckets: 16, Rolling: true}
+	tf, err := NewTimeTransform(axis, HashTransform(Mix, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := balancer.NewBalancer(curve.Hilbert, 2, 16, tf, nil, ns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//the first half of the axis of the next epoch is covered by two quadrants of the curve
+	from, to := testStart.Add(16*time.Hour), testStart.Add(24*time.Hour)
+	cgs, err := axis.CellGroups(b.Space(), from, to)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, cgs, 2)
+	for i := 0; i < 200; i++ {
+		at := from.Add(time.Duration(i) * (to.Sub(from) / 200))
+		d := &mocks.DataItem{}
+		d.On("ID").Return(fmt.Sprintf("item-%d", i))
+		d.On("Values").Return([]interface{}{at, fmt.Sprintf("sensor-%d", i)})
+		_, cID, err := b.LocateData(d)
+		if !assert.NoError(t, err) {
+			return
+		}
+		found := false
+		for _, cg := range cgs {
+			found = found || cg.FitsRange(cID)
+		}
+		assert.True(t, found, "item at %v", at)
+	}
+}